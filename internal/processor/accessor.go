@@ -0,0 +1,25 @@
+package processor
+
+import "fmt"
+
+// ValidatorAccessorFunc 返回生成代码使用的验证器，便于测试及其他包复用已注册自定义验证标签的验证器
+const ValidatorAccessorFunc = `
+// Validator 获取生成代码使用的验证器，已注册插件内置、配置文件定义及自定义的验证标签
+// 例如在测试中: Validator().Var("13800138000", "mobile")
+func Validator() *validator.Validate {
+	return validate
+}
+`
+
+// withValidatorAccessor 验证文件缺少Validator函数时追加到文件末尾
+// 包内其他文件已声明同名的函数或类型时不追加，避免重复声明
+func withValidatorAccessor(filePath string, content []byte, packageFuncs map[string]bool) ([]byte, error) {
+	f, err := parseExistingFile(filePath, content)
+	if err != nil {
+		return nil, fmt.Errorf("解析验证文件失败: %w", err)
+	}
+	if declaredFuncs(f)["Validator"] || packageFuncs["Validator"] || declaresType(f, "Validator") {
+		return content, nil
+	}
+	return append(append([]byte(nil), content...), ValidatorAccessorFunc...), nil
+}
@@ -0,0 +1,232 @@
+package processor
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+	"github.com/zeromicro/go-zero/tools/goctl/plugin"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// APIMethodsFileName 根据api文件生成时，Validate等方法写入的文件名，每次执行都会完整重新生成
+// 设置了命名风格时按风格格式化，如goZero风格为validationMethods.go
+const APIMethodsFileName = "validation_methods.go"
+
+// DefaultAPIPackageName 根据api文件生成时的默认包名，与goctl生成的types包一致
+const DefaultAPIPackageName = "types"
+
+// GenerateAPI 根据api文件中定义的类型生成代码，返回生成的文件内容，不读写文件系统（配置文件除外）
+// 返回结果中的TypesFile为只包含Validate等方法的独立文件，pkg为空时使用types
+func GenerateAPI(api *spec.ApiSpec, pkg string, options Options) (*GenerateResult, error) {
+	fileName, err := styledFileName(APIMethodsFileName, options)
+	if err != nil {
+		return nil, err
+	}
+	return generateAPI(generateInput{FilePath: fileName, PackageName: pkg}, api, options)
+}
+
+// ProcessTypesAPI 根据p.Api中的类型定义直接处理，不依赖goctl生成的types.go
+// Validate等方法写入types目录中的validation_methods.go（按命名风格格式化），其他生成文件与处理types.go时相同
+func ProcessTypesAPI(p *plugin.Plugin, options Options, summary *Summary) error {
+	if p.Api == nil {
+		return fmt.Errorf("插件未提供api文件的解析结果")
+	}
+	typesDir := cmp.Or(options.TypesDir, DefaultTypesDir)
+	if len(options.TypesDirs) > 0 && options.TypesDir == "" {
+		typesDir = options.TypesDirs[0]
+	}
+	methodsFilePath, err := apiMethodsFilePath(filepath.Join(p.Dir, filepath.FromSlash(typesDir)), options)
+	if err != nil {
+		return err
+	}
+	existing, err := readExistingFile(methodsFilePath)
+	if err != nil {
+		return fmt.Errorf("读取现有验证方法文件失败: %w", err)
+	}
+
+	in := generateInput{FilePath: methodsFilePath}
+	gen := func(in generateInput, options Options) (*GenerateResult, error) {
+		return generateAPI(in, p.Api, options)
+	}
+	_, err = processTypes(in, methodsFilePath, existing, options, summary, gen)
+	return err
+}
+
+// apiMethodsFilePath 获取目录中验证方法文件的路径，文件名按命名风格格式化
+// 目录中已有按默认文件名生成的文件时继续使用该文件，避免同一包中出现两份方法
+func apiMethodsFilePath(dir string, options Options) (string, error) {
+	fileName, err := styledFileName(APIMethodsFileName, options)
+	if err != nil {
+		return "", err
+	}
+	filePath := filepath.Join(dir, fileName)
+	if fileName == APIMethodsFileName {
+		return filePath, nil
+	}
+	legacyPath := filepath.Join(dir, APIMethodsFileName)
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		if _, err := os.Stat(legacyPath); err == nil {
+			return legacyPath, nil
+		}
+	}
+	return filePath, nil
+}
+
+// generateAPI 将api文件中的类型定义转换为types包源码后生成代码，并从生成的types文件中去掉类型声明
+func generateAPI(in generateInput, api *spec.ApiSpec, options Options) (*GenerateResult, error) {
+	in.PackageName = cmp.Or(in.PackageName, DefaultAPIPackageName)
+	in.Src = apiTypesSource(api, in.PackageName)
+	// 方法文件每次完整重新生成，验证器变量始终声明在方法文件中
+	in.GenFlag = false
+	result, err := generate(in, options)
+	if err != nil {
+		return nil, err
+	}
+	if result.TypesFile != nil {
+		if result.TypesFile, err = stripTypeDecls(in.FilePath, result.TypesFile); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// apiTypesSource 根据api文件中定义的结构体生成types包源码，只用于分析结构体和验证标签
+func apiTypesSource(api *spec.ApiSpec, pkg string) []byte {
+	var src strings.Builder
+	src.WriteString(fmt.Sprintf("package %s\n\n", pkg))
+	for _, t := range api.Types {
+		st, ok := t.(spec.DefineStruct)
+		if !ok {
+			continue
+		}
+		// 文档注释中可能包含+validate:ignore等标记
+		writeDocs(&src, st.Docs, "")
+		src.WriteString(fmt.Sprintf("type %s %s\n\n", st.RawName, structExpr(st.Members, "")))
+	}
+	return []byte(src.String())
+}
+
+// structExpr 生成结构体类型的源码，indent为结构体所在行的缩进
+func structExpr(members []spec.Member, indent string) string {
+	var code strings.Builder
+	code.WriteString("struct {\n")
+	for _, m := range members {
+		writeDocs(&code, m.Docs, indent+"\t")
+		code.WriteString(indent + "\t")
+		// 匿名嵌入的结构体只写类型
+		if !m.IsInline {
+			code.WriteString(m.Name + " ")
+		}
+		code.WriteString(typeExpr(m.Type, indent+"\t"))
+		if tag := strings.TrimSpace(m.Tag); tag != "" {
+			if !strings.HasPrefix(tag, "`") {
+				tag = "`" + tag + "`"
+			}
+			code.WriteString(" " + tag)
+		}
+		code.WriteString("\n")
+	}
+	code.WriteString(indent + "}")
+	return code.String()
+}
+
+// typeExpr 生成字段类型的源码，内嵌的匿名结构体展开为结构体类型
+func typeExpr(t spec.Type, indent string) string {
+	switch t := t.(type) {
+	case spec.NestedStruct:
+		return structExpr(t.Members, indent)
+	case spec.ArrayType:
+		if _, ok := t.Value.(spec.NestedStruct); ok {
+			return "[]" + typeExpr(t.Value, indent)
+		}
+	case spec.PointerType:
+		if _, ok := t.Type.(spec.NestedStruct); ok {
+			return "*" + typeExpr(t.Type, indent)
+		}
+	case spec.MapType:
+		if _, ok := t.Value.(spec.NestedStruct); ok {
+			return fmt.Sprintf("map[%s]%s", t.Key, typeExpr(t.Value, indent))
+		}
+	}
+	return t.Name()
+}
+
+// writeDocs 写入文档注释，api文件中的文档可能不带注释符号
+func writeDocs(code *strings.Builder, docs spec.Doc, indent string) {
+	for _, doc := range docs {
+		doc = strings.TrimSpace(doc)
+		if doc == "" {
+			continue
+		}
+		if !strings.HasPrefix(doc, "//") && !strings.HasPrefix(doc, "/*") {
+			doc = "// " + doc
+		}
+		code.WriteString(indent + doc + "\n")
+	}
+}
+
+// stripTypeDecls 去掉生成的types文件中的类型声明，只保留导入、验证器变量和方法
+func stripTypeDecls(filePath string, content []byte) ([]byte, error) {
+	return stripDecls(filePath, content, func(decl ast.Decl) bool {
+		genDecl, ok := decl.(*ast.GenDecl)
+		return ok && genDecl.Tok == token.TYPE
+	})
+}
+
+// stripDecls 去掉生成的types文件中strip返回true的声明及package之前的文件注释，并删除不再使用的导入
+func stripDecls(filePath string, content []byte, strip func(ast.Decl) bool) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filePath, content, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("解析生成的验证方法文件失败: %w", err)
+	}
+	comments := ast.NewCommentMap(fset, f, f.Comments)
+	var decls []ast.Decl
+	for _, decl := range f.Decls {
+		if strip(decl) {
+			continue
+		}
+		decls = append(decls, decl)
+	}
+	f.Decls = decls
+	// types文件开头的生成代码标记等注释由GeneratedHeader代替
+	f.Doc = nil
+	var kept []*ast.CommentGroup
+	for _, c := range comments.Filter(f).Comments() {
+		if c.End() > f.Package {
+			kept = append(kept, c)
+		}
+	}
+	f.Comments = kept
+	for _, imp := range slices.Clone(f.Imports) {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := ""
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if name != "_" && name != "." && !astutil.UsesImport(f, path) {
+			astutil.DeleteNamedImport(fset, f, name, path)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(GeneratedHeader)
+	if err := format.Node(&buf, fset, f); err != nil {
+		return nil, fmt.Errorf("格式化验证方法文件失败: %w", err)
+	}
+	return buf.Bytes(), nil
+}
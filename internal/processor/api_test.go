@@ -0,0 +1,79 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+)
+
+// testAPISpec 包含一个带验证标签的请求结构体的api定义
+func testAPISpec() *spec.ApiSpec {
+	return &spec.ApiSpec{
+		Types: []spec.Type{
+			spec.DefineStruct{
+				RawName: "CreateUserReq",
+				Members: []spec.Member{
+					{Name: "Name", Type: spec.PrimitiveType{RawName: "string"}, Tag: "`json:\"name\" validate:\"required,min=2\"`"},
+					{Name: "Mobile", Type: spec.PrimitiveType{RawName: "string"}, Tag: "`json:\"mobile\" validate:\"required,mobile\"`"},
+				},
+			},
+			spec.DefineStruct{
+				RawName: "CreateUserResp",
+				Members: []spec.Member{
+					{Name: "Id", Type: spec.PrimitiveType{RawName: "int64"}, Tag: "`json:\"id\"`"},
+				},
+			},
+		},
+	}
+}
+
+func TestGenerateAPI(t *testing.T) {
+	result, err := GenerateAPI(testAPISpec(), "types", Options{EnableTranslator: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	methods := string(result.TypesFile)
+	// 方法文件只包含Validate方法，结构体由goctl生成到types.go中
+	if !strings.Contains(methods, "func (r *CreateUserReq) Validate() error") || strings.Contains(methods, "type CreateUserReq struct") {
+		t.Errorf("methods file does not contain only the Validate method:\n%s", methods)
+	}
+	if strings.Contains(methods, "CreateUserResp") {
+		t.Errorf("methods file generates Validate for a struct without validate tags:\n%s", methods)
+	}
+	if !strings.Contains(string(result.ValidationFile), "validateMobile") || result.TranslatorFile == nil {
+		t.Errorf("GenerateAPI() does not generate the validation and translator files")
+	}
+}
+
+func TestCanonicalValidationFile(t *testing.T) {
+	// types.go、内存中的Generate及api定义三种入口生成的验证文件完全相同
+	src := "package types\n\n" +
+		"type CreateUserReq struct {\n" +
+		"\tName   string `json:\"name\" validate:\"required,min=2\"`\n" +
+		"\tMobile string `json:\"mobile\" validate:\"required,mobile\"`\n" +
+		"}\n\n" +
+		"type CreateUserResp struct {\n" +
+		"\tId int64 `json:\"id\"`\n" +
+		"}\n"
+	options := Options{EnableTranslator: true}
+	generated, err := Generate([]byte(src), "types", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromAPI, err := GenerateAPI(testAPISpec(), "types", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := processFiles(t, options, writeTypesFile(t, dir, "types.go", src)); err != nil {
+		t.Fatal(err)
+	}
+	written := readFile(t, dir, "validation.go")
+	if string(generated.ValidationFile) != written || string(fromAPI.ValidationFile) != written {
+		t.Errorf("validation files differ between entry points:\nGenerate:\n%s\nGenerateAPI:\n%s\nProcessTypesFile:\n%s", generated.ValidationFile, fromAPI.ValidationFile, written)
+	}
+	if string(generated.TranslatorFile) != readFile(t, dir, "translator.go") {
+		t.Errorf("translator files differ between Generate and ProcessTypesFile")
+	}
+}
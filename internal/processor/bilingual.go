@@ -0,0 +1,63 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TranslateBilingualFunc 同时使用两种语言翻译验证错误，%[1]s 为默认语言，%[2]s 为括号中的第二种语言
+const TranslateBilingualFunc = `
+// TranslateBilingual 同时使用%[1]s和%[2]s翻译验证错误，每个字段的%[1]s错误信息后接括号中的%[2]s错误信息，err为nil时返回空字符串
+// 例如: 用户名为必填字段 (username is a required field)
+func TranslateBilingual(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var errs validator.ValidationErrors
+	if ok := errors.As(err, &errs); !ok {
+		return err.Error()
+	}
+
+	primary, _ := uni.GetTranslator(%[1]q)
+	secondary, _ := uni.GetTranslator(%[2]q)
+
+	var errMsgs []string
+	for _, e := range errs {
+		msg, secondaryMsg := translateField(e, primary), translateField(e, secondary)
+		// 字段通过msg标签自定义的错误信息与语言无关，不重复显示
+		if secondaryMsg != msg {
+			msg += " (" + secondaryMsg + ")"
+		}
+		errMsgs = append(errMsgs, msg)
+	}
+	return strings.Join(errMsgs, ", ")
+}
+`
+
+// bilingualOptions 检查生成双语错误信息时的选项，双语翻译使用多语言翻译器中的前两种语言
+func bilingualOptions(options Options, langs []string) error {
+	if !options.Bilingual {
+		return nil
+	}
+	if !options.EnableTranslator {
+		return fmt.Errorf("--bilingual需要同时启用--translator")
+	}
+	if len(langs) < 2 {
+		return fmt.Errorf("--bilingual需要通过--langs指定至少两种翻译语言，如zh,en")
+	}
+	return nil
+}
+
+// translateBilingualFunc 生成TranslateBilingual函数，wrap表示翻译器需要包装为sharedTranslator，lazy表示翻译前初始化翻译器
+func translateBilingualFunc(langs []string, wrap, lazy bool) string {
+	code := fmt.Sprintf(TranslateBilingualFunc, langs[0], langs[1])
+	if wrap {
+		code = strings.Replace(code, "translateField(e, primary), translateField(e, secondary)",
+			"translateField(e, sharedTranslator{primary}), translateField(e, sharedTranslator{secondary})", 1)
+	}
+	if lazy {
+		code = strings.Replace(code, "\tprimary, _ :=", "\ttranslatorOnce.Do(initTranslator)\n\tprimary, _ :=", 1)
+	}
+	return code
+}
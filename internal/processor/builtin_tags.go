@@ -0,0 +1,188 @@
+// Code generated from github.com/go-playground/validator/v10@v10.26.0 baked_in.go and validator_instance.go. DO NOT EDIT.
+
+package processor
+
+// goPlaygroundTags go-playground/validator v10内置的验证标签、别名及控制标签
+var goPlaygroundTags = map[string]bool{
+	"-":                             true,
+	"alpha":                         true,
+	"alphanum":                      true,
+	"alphanumunicode":               true,
+	"alphaunicode":                  true,
+	"ascii":                         true,
+	"base32":                        true,
+	"base64":                        true,
+	"base64rawurl":                  true,
+	"base64url":                     true,
+	"bcp47_language_tag":            true,
+	"bic":                           true,
+	"boolean":                       true,
+	"btc_addr":                      true,
+	"btc_addr_bech32":               true,
+	"cidr":                          true,
+	"cidrv4":                        true,
+	"cidrv6":                        true,
+	"contains":                      true,
+	"containsany":                   true,
+	"containsrune":                  true,
+	"country_code":                  true,
+	"credit_card":                   true,
+	"cron":                          true,
+	"cve":                           true,
+	"datauri":                       true,
+	"datetime":                      true,
+	"dir":                           true,
+	"dirpath":                       true,
+	"dive":                          true,
+	"dns_rfc1035_label":             true,
+	"e164":                          true,
+	"ein":                           true,
+	"email":                         true,
+	"endkeys":                       true,
+	"endsnotwith":                   true,
+	"endswith":                      true,
+	"eq":                            true,
+	"eq_ignore_case":                true,
+	"eqcsfield":                     true,
+	"eqfield":                       true,
+	"eth_addr":                      true,
+	"eth_addr_checksum":             true,
+	"eu_country_code":               true,
+	"excluded_if":                   true,
+	"excluded_unless":               true,
+	"excluded_with":                 true,
+	"excluded_with_all":             true,
+	"excluded_without":              true,
+	"excluded_without_all":          true,
+	"excludes":                      true,
+	"excludesall":                   true,
+	"excludesrune":                  true,
+	"fieldcontains":                 true,
+	"fieldexcludes":                 true,
+	"file":                          true,
+	"filepath":                      true,
+	"fqdn":                          true,
+	"gt":                            true,
+	"gtcsfield":                     true,
+	"gte":                           true,
+	"gtecsfield":                    true,
+	"gtefield":                      true,
+	"gtfield":                       true,
+	"hexadecimal":                   true,
+	"hexcolor":                      true,
+	"hostname":                      true,
+	"hostname_port":                 true,
+	"hostname_rfc1123":              true,
+	"hsl":                           true,
+	"hsla":                          true,
+	"html":                          true,
+	"html_encoded":                  true,
+	"http_url":                      true,
+	"image":                         true,
+	"ip":                            true,
+	"ip4_addr":                      true,
+	"ip6_addr":                      true,
+	"ip_addr":                       true,
+	"ipv4":                          true,
+	"ipv6":                          true,
+	"isbn":                          true,
+	"isbn10":                        true,
+	"isbn13":                        true,
+	"iscolor":                       true,
+	"isdefault":                     true,
+	"iso3166_1_alpha2":              true,
+	"iso3166_1_alpha2_eu":           true,
+	"iso3166_1_alpha3":              true,
+	"iso3166_1_alpha3_eu":           true,
+	"iso3166_1_alpha_numeric":       true,
+	"iso3166_1_alpha_numeric_eu":    true,
+	"iso3166_2":                     true,
+	"iso4217":                       true,
+	"iso4217_numeric":               true,
+	"issn":                          true,
+	"json":                          true,
+	"jwt":                           true,
+	"keys":                          true,
+	"latitude":                      true,
+	"len":                           true,
+	"longitude":                     true,
+	"lowercase":                     true,
+	"lt":                            true,
+	"ltcsfield":                     true,
+	"lte":                           true,
+	"ltecsfield":                    true,
+	"ltefield":                      true,
+	"ltfield":                       true,
+	"luhn_checksum":                 true,
+	"mac":                           true,
+	"max":                           true,
+	"md4":                           true,
+	"md5":                           true,
+	"min":                           true,
+	"mongodb":                       true,
+	"mongodb_connection_string":     true,
+	"multibyte":                     true,
+	"ne":                            true,
+	"ne_ignore_case":                true,
+	"necsfield":                     true,
+	"nefield":                       true,
+	"nostructlevel":                 true,
+	"number":                        true,
+	"numeric":                       true,
+	"omitempty":                     true,
+	"omitnil":                       true,
+	"omitzero":                      true,
+	"oneof":                         true,
+	"oneofci":                       true,
+	"port":                          true,
+	"postcode_iso3166_alpha2":       true,
+	"postcode_iso3166_alpha2_field": true,
+	"printascii":                    true,
+	"required":                      true,
+	"required_if":                   true,
+	"required_unless":               true,
+	"required_with":                 true,
+	"required_with_all":             true,
+	"required_without":              true,
+	"required_without_all":          true,
+	"rgb":                           true,
+	"rgba":                          true,
+	"ripemd128":                     true,
+	"ripemd160":                     true,
+	"semver":                        true,
+	"sha256":                        true,
+	"sha384":                        true,
+	"sha512":                        true,
+	"skip_unless":                   true,
+	"spicedb":                       true,
+	"ssn":                           true,
+	"startsnotwith":                 true,
+	"startswith":                    true,
+	"structonly":                    true,
+	"tcp4_addr":                     true,
+	"tcp6_addr":                     true,
+	"tcp_addr":                      true,
+	"tiger128":                      true,
+	"tiger160":                      true,
+	"tiger192":                      true,
+	"timezone":                      true,
+	"udp4_addr":                     true,
+	"udp6_addr":                     true,
+	"udp_addr":                      true,
+	"ulid":                          true,
+	"unique":                        true,
+	"unix_addr":                     true,
+	"uppercase":                     true,
+	"uri":                           true,
+	"url":                           true,
+	"url_encoded":                   true,
+	"urn_rfc2141":                   true,
+	"uuid":                          true,
+	"uuid3":                         true,
+	"uuid3_rfc4122":                 true,
+	"uuid4":                         true,
+	"uuid4_rfc4122":                 true,
+	"uuid5":                         true,
+	"uuid5_rfc4122":                 true,
+	"uuid_rfc4122":                  true,
+}
@@ -0,0 +1,85 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// controlTags 不是验证函数的控制标签，不能通过Var单独使用
+var controlTags = map[string]bool{
+	"-": true, "dive": true, "keys": true, "endkeys": true, "omitempty": true, "omitnil": true, "omitzero": true,
+	"structonly": true, "nostructlevel": true, "isdefault": true,
+}
+
+func TestBuiltInTagsRegistered(t *testing.T) {
+	// 内置标签表必须与go.mod中的validator版本一致，表中有而validator中没有的标签在运行时panic
+	v := validator.New()
+	for tag := range goPlaygroundTags {
+		if controlTags[tag] {
+			continue
+		}
+		t.Run(tag, func(t *testing.T) {
+			defer func() {
+				if r := recover(); r != nil && strings.Contains(fmt.Sprint(r), "Undefined validation function") {
+					t.Errorf("validator does not define the built-in tag %q: %v", tag, r)
+				}
+			}()
+			_ = v.Var("", tag)
+		})
+	}
+}
+
+func TestIsBuiltInValidator(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want bool
+	}{
+		{"required", true},
+		{"min=10", true},
+		{"hexcolor|rgb", true},
+		{"mobile", true},
+		{"numstr_gt=0", true},
+		{"age_range", false},
+		{"hexcolor|age_range", false},
+		{"noneof=a b", false},
+	}
+	for _, tt := range tests {
+		if got := isBuiltInValidator(tt.tag); got != tt.want {
+			t.Errorf("isBuiltInValidator(%q) = %v, want %v", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestIPFamilyTagsBuiltIn(t *testing.T) {
+	tests := []string{"ip", "ipv4", "ipv6", "ip_addr", "ip4_addr", "ip6_addr", "cidr", "cidrv4", "cidrv6", "tcp_addr", "udp_addr", "mac", "hostname_port"}
+	for _, tag := range tests {
+		if !isBuiltInValidator(tag) {
+			t.Errorf("isBuiltInValidator(%q) = false, want true", tag)
+		}
+	}
+}
+
+func TestIPFamilyTagsGenerateNoStubs(t *testing.T) {
+	src := "package types\n\n" +
+		"type ServerReq struct {\n" +
+		"\tAddr4  string `json:\"addr4\" validate:\"ip4_addr\"`\n" +
+		"\tAddr6  string `json:\"addr6\" validate:\"ip6_addr\"`\n" +
+		"\tSubnet string `json:\"subnet\" validate:\"omitempty,cidr\"`\n" +
+		"}\n"
+	result, err := Generate([]byte(src), "", Options{EnableCustomValidation: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// IP地址族标签由validator内置实现，不作为自定义标签生成空的验证方法
+	if len(result.CustomTags) != 0 {
+		t.Errorf("CustomTags = %v, want none", result.CustomTags)
+	}
+	for _, stub := range []string{"validateIp4Addr", "validateIp6Addr", "validateCidr"} {
+		if strings.Contains(string(result.ValidationFile), stub) {
+			t.Errorf("validation file generates the stub %s:\n%s", stub, result.ValidationFile)
+		}
+	}
+}
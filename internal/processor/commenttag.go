@@ -0,0 +1,105 @@
+package processor
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CommentTagPrefix 字段注释中验证规则的前缀，字段没有validate标签时按注释中的规则添加标签
+// 例如: Phone string // validate: required,mobile
+const CommentTagPrefix = "validate:"
+
+// commentTagEdit 替换源码中一段内容的修改
+type commentTagEdit struct {
+	start, end int
+	text       string
+}
+
+// injectCommentTags 为没有validate标签但注释中写有验证规则的字段添加validate标签
+// 返回修改后的源码，没有需要添加的标签时返回nil
+func injectCommentTags(fset *token.FileSet, f *ast.File, src []byte) ([]byte, error) {
+	var edits []commentTagEdit
+	var err error
+	ast.Inspect(f, func(n ast.Node) bool {
+		structType, ok := n.(*ast.StructType)
+		if !ok || err != nil {
+			return err == nil
+		}
+		for _, field := range structType.Fields.List {
+			rule := commentRule(field)
+			if rule == "" {
+				continue
+			}
+			var edit commentTagEdit
+			if edit, ok, err = commentTagFieldEdit(fset, field, rule); err != nil {
+				return false
+			}
+			if ok {
+				edits = append(edits, edit)
+			}
+		}
+		return true
+	})
+	if err != nil || len(edits) == 0 {
+		return nil, err
+	}
+
+	// 从后向前替换，前面的偏移量不受影响
+	slices.SortFunc(edits, func(a, b commentTagEdit) int { return b.start - a.start })
+	content := append([]byte(nil), src...)
+	for _, edit := range edits {
+		content = slices.Concat(content[:edit.start], []byte(edit.text), content[edit.end:])
+	}
+	return content, nil
+}
+
+// commentRule 获取字段文档注释或行尾注释中的验证规则
+func commentRule(field *ast.Field) string {
+	for _, doc := range []*ast.CommentGroup{field.Doc, field.Comment} {
+		if doc == nil {
+			continue
+		}
+		for _, comment := range doc.List {
+			text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+			if rule, ok := strings.CutPrefix(text, CommentTagPrefix); ok {
+				return strings.TrimSpace(rule)
+			}
+		}
+	}
+	return ""
+}
+
+// commentTagFieldEdit 生成为字段添加validate标签的修改，字段已有validate标签时不修改
+func commentTagFieldEdit(fset *token.FileSet, field *ast.Field, rule string) (commentTagEdit, bool, error) {
+	tag := "validate:" + strconv.Quote(rule)
+	if field.Tag == nil {
+		end := fset.Position(field.Type.End()).Offset
+		return commentTagEdit{start: end, end: end, text: " " + tagLiteral(tag)}, true, nil
+	}
+
+	value, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return commentTagEdit{}, false, fmt.Errorf("解析字段标签%s失败: %w", field.Tag.Value, err)
+	}
+	if _, ok := reflect.StructTag(value).Lookup("validate"); ok {
+		return commentTagEdit{}, false, nil
+	}
+	return commentTagEdit{
+		start: fset.Position(field.Tag.Pos()).Offset,
+		end:   fset.Position(field.Tag.End()).Offset,
+		text:  tagLiteral(strings.TrimSpace(value + " " + tag)),
+	}, true, nil
+}
+
+// tagLiteral 生成结构体标签的字面量，标签中包含反引号时使用双引号
+func tagLiteral(tag string) string {
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
@@ -0,0 +1,220 @@
+package processor
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidatorConfig 配置文件中定义的自定义验证器
+type ValidatorConfig struct {
+	// 验证标签名称
+	Tag string `yaml:"tag"`
+	// 字段需要匹配的正则表达式
+	Regex string `yaml:"regex"`
+	// 验证失败时的翻译文本，{0}为字段名，{1}为标签参数
+	Message string `yaml:"message"`
+	// 按语言定义的翻译文本，如zh、en，优先于Message
+	Messages map[string]string `yaml:"messages"`
+	// 验证失败时的错误码，仅在生成错误码时使用
+	Code int `yaml:"code"`
+}
+
+// Config 验证器配置文件，支持YAML和JSON格式
+type Config struct {
+	Validators []ValidatorConfig `yaml:"validators"`
+	// 验证标签别名，键为别名，值为插件内置或配置文件定义的验证标签，如phone: mobile
+	Aliases map[string]string `yaml:"aliases"`
+	// 验证规则别名，通过RegisterAlias注册，键为别名，值为别名代表的验证规则，如username_rule: required,alphanum,min=3,max=20
+	RuleAliases map[string]string `yaml:"rule_aliases"`
+}
+
+// ruleAliasRestrictedChars 验证规则别名中不允许出现的字符，与validator的限制一致
+const ruleAliasRestrictedChars = ".[],|=+()`~!@#$%^&*\\\"/?<>{} "
+
+// ConfigValidationFuncTemplate 配置文件定义的正则验证方法模板
+const ConfigValidationFuncTemplate = `
+// 验证%[1]s（配置文件定义）
+func %[2]s(fl validator.FieldLevel) bool {
+	match, _ := regexp.MatchString(%[3]s, fl.Field().String())
+	return match
+}
+`
+
+// regexPluginValidations 只通过正则表达式验证的插件内置标签，配置文件中可以通过regex覆盖其正则表达式
+// 覆盖后生成的验证函数保持原函数名及注册，如validateMobile
+var regexPluginValidations = map[string]bool{
+	"mobile":      true,
+	"idcard":      true,
+	"chinesename": true,
+	"vsemver":     true,
+}
+
+// LoadConfig 读取并校验验证器配置文件
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取配置文件失败: %w", err)
+	}
+
+	var config Config
+	// JSON是YAML的子集，两种格式都使用YAML解析
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("解析配置文件失败: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, v := range config.Validators {
+		if v.Tag == "" {
+			return nil, fmt.Errorf("配置文件中的验证器缺少tag")
+		}
+		if seen[v.Tag] {
+			return nil, fmt.Errorf("配置文件中的验证标签重复: %s", v.Tag)
+		}
+		seen[v.Tag] = true
+		for lang := range v.Messages {
+			if _, ok := translationLanguages[lang]; !ok {
+				return nil, fmt.Errorf("验证标签 %s 的翻译语言不支持: %s", v.Tag, lang)
+			}
+		}
+		// 插件内置的验证标签只能覆盖翻译，只通过正则表达式验证的标签还可以覆盖正则表达式
+		if isPluginValidation(v.Tag) {
+			if regexPluginValidations[v.Tag] && v.Code != 0 {
+				return nil, fmt.Errorf("插件内置的验证标签 %s 只能配置regex、message和messages", v.Tag)
+			}
+			if !regexPluginValidations[v.Tag] && (v.Regex != "" || v.Code != 0) {
+				return nil, fmt.Errorf("插件内置的验证标签 %s 只能配置message和messages", v.Tag)
+			}
+		} else if isBuiltInValidator(v.Tag) {
+			return nil, fmt.Errorf("配置文件中的验证标签与内置验证标签冲突: %s", v.Tag)
+		}
+		if _, err := regexp.Compile(v.Regex); err != nil {
+			return nil, fmt.Errorf("验证标签 %s 的正则表达式无效: %w", v.Tag, err)
+		}
+	}
+	for alias, target := range config.Aliases {
+		if alias == "" || target == "" {
+			return nil, fmt.Errorf("配置文件中的验证标签别名不能为空")
+		}
+		if seen[alias] || isBuiltInValidator(alias) {
+			return nil, fmt.Errorf("配置文件中的验证标签别名与已有验证标签冲突: %s", alias)
+		}
+		if !seen[target] && !isPluginValidation(target) && config.Aliases[target] == "" {
+			return nil, fmt.Errorf("验证标签别名 %s 指向的 %s 不是插件内置或配置文件定义的验证标签", alias, target)
+		}
+	}
+	for alias, rule := range config.RuleAliases {
+		if alias == "" || rule == "" {
+			return nil, fmt.Errorf("配置文件中的验证规则别名及其验证规则不能为空")
+		}
+		if strings.ContainsAny(alias, ruleAliasRestrictedChars) {
+			return nil, fmt.Errorf("配置文件中的验证规则别名包含不允许的字符: %s", alias)
+		}
+		if _, ok := config.Aliases[alias]; seen[alias] || ok || isBuiltInValidator(alias) {
+			return nil, fmt.Errorf("配置文件中的验证规则别名与已有验证标签冲突: %s", alias)
+		}
+	}
+	return &config, nil
+}
+
+// pluginValidations 获取插件内置及配置文件定义的所有验证方法，配置文件定义的验证方法排在内置验证方法之后
+func pluginValidations(options Options) ([]builtInValidation, error) {
+	validations := append([]builtInValidation(nil), builtInValidations...)
+	if options.ConfigPath == "" {
+		return validations, nil
+	}
+
+	config, err := LoadConfig(options.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range config.Validators {
+		// 覆盖插件内置验证方法的翻译及正则表达式
+		if i := slices.IndexFunc(validations, func(b builtInValidation) bool { return b.Tag == v.Tag }); i >= 0 {
+			validations[i].Message = v.Message
+			validations[i].Messages = v.Messages
+			if v.Regex != "" {
+				validations[i].Code = fmt.Sprintf(ConfigValidationFuncTemplate, v.Tag, validations[i].Func, strconv.Quote(v.Regex))
+				validations[i].RegexOverridden = true
+			}
+			continue
+		}
+		funcName := validationFuncName(v.Tag)
+		validations = append(validations, builtInValidation{
+			Tag:       v.Tag,
+			Func:      funcName,
+			Comment:   v.Tag,
+			Code:      fmt.Sprintf(ConfigValidationFuncTemplate, v.Tag, funcName, strconv.Quote(v.Regex)),
+			Message:   v.Message,
+			Messages:  v.Messages,
+			ErrorCode: v.Code,
+		})
+	}
+	// 别名按名称排序，保证注册顺序稳定
+	aliases := slices.Sorted(maps.Keys(config.Aliases))
+	for _, alias := range aliases {
+		target, err := resolveAlias(config.Aliases, alias)
+		if err != nil {
+			return nil, err
+		}
+		i := slices.IndexFunc(validations, func(v builtInValidation) bool { return v.Tag == target })
+		canonical := validations[i]
+		validations = append(validations, builtInValidation{
+			Tag:      alias,
+			Func:     canonical.Func,
+			Comment:  target + "的别名",
+			Message:  canonical.Message,
+			Messages: canonical.Messages,
+			Alias:    target,
+		})
+	}
+	return validations, nil
+}
+
+// validationTags 获取验证方法对应的标签集合
+func validationTags(validations []builtInValidation) map[string]bool {
+	tags := make(map[string]bool)
+	for _, v := range validations {
+		tags[v.Tag] = true
+	}
+	return tags
+}
+
+// validationMessage 获取验证方法在指定语言下的翻译
+// 依次使用配置文件中该语言的翻译、配置文件中的翻译、该语言的默认翻译、配置文件中的英文翻译
+func validationMessage(v builtInValidation, lang string) string {
+	if msg := v.Messages[lang]; msg != "" {
+		return msg
+	}
+	if v.Message != "" {
+		return v.Message
+	}
+	tag := cmp.Or(v.Alias, v.Tag)
+	if msg, ok := translationLanguages[lang][tag]; ok {
+		return msg
+	}
+	if msg := v.Messages["en"]; msg != "" {
+		return msg
+	}
+	return translationMessage(lang, tag)
+}
+
+// resolveAlias 获取别名最终指向的验证标签，别名可以指向另一个别名
+func resolveAlias(aliases map[string]string, alias string) (string, error) {
+	tag := alias
+	for range len(aliases) + 1 {
+		target, ok := aliases[tag]
+		if !ok {
+			return tag, nil
+		}
+		tag = target
+	}
+	return "", fmt.Errorf("验证标签别名 %s 存在循环引用", alias)
+}
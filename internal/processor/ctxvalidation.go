@@ -0,0 +1,136 @@
+package processor
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+const (
+	// CustomCtxValidationFuncTemplate 通过RegisterValidationCtx注册的自定义验证方法定义模板
+	// %[1]s 为验证标签，%[2]s 为验证函数名的后缀，%[3]s 为验证逻辑
+	// validate%[2]s 包装为validator.Func，供registerValidation映射及结构体专属验证器注册
+	CustomCtxValidationFuncTemplate = `
+// 自定义验证方法: %[1]s，registerValidation中注册的包装，使用context.Background()调用validate%[2]sCtx
+func validate%[2]s(fl validator.FieldLevel) bool {
+	return validate%[2]sCtx(context.Background(), fl)
+}
+
+// 自定义验证方法: %[1]s，通过RegisterValidationCtx注册
+// 使用ValidateCtx验证时ctx为调用方传入的上下文，使用Validate验证时为context.Background()
+// 验证失败时可以通过setValidationMessage记录错误信息，启用翻译器时翻译该字段的验证错误优先使用，例如:
+// if balance < amount {
+// 	setValidationMessage(fl, fmt.Sprintf("余额不足，当前余额%%d", balance))
+// 	return false
+// }
+func validate%[2]sCtx(ctx context.Context, fl validator.FieldLevel) bool {
+%[3]s}
+
+// 使用读取context的验证方法覆盖registerValidation中的注册
+func init() {
+	_ = validate.RegisterValidationCtx(%[1]q, validate%[2]sCtx)
+}
+`
+
+	// customCtxValidationBody 自定义验证方法的验证逻辑，%s 为验证标签
+	customCtxValidationBody = `	// 在这里实现 %s 的验证逻辑
+	return true
+`
+
+	// customCtxParamValidationBody 带参数的自定义验证方法的验证逻辑，%s 为验证标签
+	customCtxParamValidationBody = `	// 验证标签的参数，如within=10中的10
+	param := fl.Param()
+	_ = param
+	// 在这里实现 %s 的验证逻辑
+	return true
+`
+
+	// ValidationMessageFuncs 自定义验证方法记录验证失败的错误信息
+	ValidationMessageFuncs = `
+// validationMessages 自定义验证方法记录的最近一次验证失败的错误信息
+// key: 验证标签.字段名，如balance.amount
+var validationMessages sync.Map
+
+// setValidationMessage 记录自定义验证方法验证失败的错误信息，翻译该字段的验证错误时优先使用
+func setValidationMessage(fl validator.FieldLevel, msg string) {
+	validationMessages.Store(fl.GetTag()+"."+fl.FieldName(), msg)
+}
+
+// validationMessage 获取自定义验证方法为字段记录的错误信息
+func validationMessage(fe validator.FieldError) (string, bool) {
+	msg, ok := validationMessages.Load(fe.Tag() + "." + fe.Field())
+	if !ok {
+		return "", false
+	}
+	return msg.(string), true
+}
+`
+
+	// translatorValidationMessage 翻译字段验证错误时优先使用自定义验证方法记录的错误信息
+	translatorValidationMessage = `	if msg, ok := validationMessage(fe); ok {
+		return msg
+	}
+`
+)
+
+// customCtxValidationFunc 生成通过RegisterValidationCtx注册的自定义验证方法
+func customCtxValidationFunc(tag string, hasParam bool) string {
+	body := customCtxValidationBody
+	if hasParam {
+		body = customCtxParamValidationBody
+	}
+	return fmt.Sprintf(CustomCtxValidationFuncTemplate, tag, exportName(tag), fmt.Sprintf(body, tag))
+}
+
+// ctxValidationOptions 检查自定义验证方法通过RegisterValidationCtx注册时的选项
+// 记录的错误信息由同一包中的translateField读取，不能使用共享翻译器包
+func ctxValidationOptions(options Options) error {
+	if !options.CustomValidationCtx {
+		return nil
+	}
+	if !options.EnableCustomValidation {
+		return fmt.Errorf("--custom-ctx需要同时启用--custom")
+	}
+	if options.EnableTranslator && options.SharedTranslatorPackage != "" {
+		return fmt.Errorf("--custom-ctx不能与--shared-translator同时使用")
+	}
+	return nil
+}
+
+// withValidationMessageFuncs 验证文件缺少记录错误信息的函数时追加到文件末尾，并补充使用的导入
+func withValidationMessageFuncs(filePath string, content []byte) ([]byte, error) {
+	if !strings.Contains(string(content), "func setValidationMessage(") {
+		content = append(append([]byte(nil), content...), ValidationMessageFuncs...)
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filePath, content, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("解析验证文件失败: %w", err)
+	}
+	var imports []importSpec
+	for _, pkg := range []string{"context", "sync"} {
+		if strings.Contains(string(content), pkg+".") {
+			imports = append(imports, importSpec{Path: pkg})
+		}
+	}
+	return addImports(fset, f, imports)
+}
+
+// withTranslatorValidationMessage translateField缺少读取自定义验证方法记录的错误信息时补充
+func withTranslatorValidationMessage(content []byte) []byte {
+	code := string(content)
+	if strings.Contains(code, "validationMessage(fe)") {
+		return content
+	}
+	start := strings.Index(code, "func translateField(")
+	if start < 0 {
+		return content
+	}
+	i := strings.Index(code[start:], "\treturn fe.Translate(t)\n")
+	if i < 0 {
+		return content
+	}
+	i += start
+	return []byte(code[:i] + translatorValidationMessage + code[i:])
+}
@@ -0,0 +1,271 @@
+package processor
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// EnumsFileName 枚举常量文件名
+const EnumsFileName = "enums.go"
+
+// oneofValueRegex 匹配oneof参数中的单个值，与validator一致，单引号括起的值可以包含空格
+var oneofValueRegex = regexp.MustCompile(`'[^']*'|\S+`)
+
+// enumNumericTypes 枚举值不加引号的数值类型
+var enumNumericTypes = map[string]bool{
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true,
+}
+
+// enumGroup 单个字段oneof标签中的枚举值，生成为一组常量
+type enumGroup struct {
+	// 结构体名
+	Struct string
+	// 字段名
+	Field string
+	// 字段的类型，常量使用相同的类型，如string、int
+	Type string
+	// oneof标签中的值，已去掉单引号
+	Values []string
+}
+
+// constName 获取枚举值的常量名，prefix为true时带有结构体名，如StatusPending、OrderStatusPending
+func (g enumGroup) constName(value string, prefix bool) string {
+	name := g.Field + enumValueName(value)
+	if prefix {
+		name = g.Struct + name
+	}
+	return name
+}
+
+// literal 获取枚举值的常量字面量，数值类型不加引号
+func (g enumGroup) literal(value string) string {
+	if enumNumericTypes[g.Type] {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+// enumValueName 将枚举值转换为常量名的后缀，分隔符后的字母大写，如in_progress -> InProgress，-1 -> Neg1，1.5 -> 1_5
+func enumValueName(value string) string {
+	var name strings.Builder
+	upper := true
+	for i, r := range value {
+		switch {
+		case r == '-' && i == 0:
+			name.WriteString("Neg")
+		case r == '.':
+			name.WriteString("_")
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if upper {
+				r = unicode.ToUpper(r)
+				upper = false
+			}
+			name.WriteRune(r)
+		default:
+			upper = true
+		}
+	}
+	return name.String()
+}
+
+// enumFieldType 获取字段类型中可以声明常量的基础类型，指针及切片使用元素类型，其他类型返回空字符串
+func enumFieldType(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return enumFieldType(t.X)
+	case *ast.ArrayType:
+		return enumFieldType(t.Elt)
+	case *ast.Ident:
+		if t.Name == "string" || enumNumericTypes[t.Name] {
+			return t.Name
+		}
+	}
+	return ""
+}
+
+// oneofValues 获取验证规则中oneof标签的值，没有oneof标签时返回nil
+func oneofValues(rule string) []string {
+	for _, v := range splitValidateTag(rule) {
+		tag, param, ok := strings.Cut(v, "=")
+		if !ok || tag != "oneof" {
+			continue
+		}
+		var values []string
+		for _, value := range oneofValueRegex.FindAllString(param, -1) {
+			values = append(values, strings.Trim(value, "'"))
+		}
+		return values
+	}
+	return nil
+}
+
+// enumFile 同一包中一个文件的枚举值
+type enumFile struct {
+	// 文件名
+	Name string
+	// 文件中的枚举值，按声明顺序排列
+	Groups []enumGroup
+}
+
+// packageEnumFiles 收集目录中除指定文件外其他Go文件的枚举值，按文件名排列
+// 同一包中的多个types文件共用一个enums.go，生成时需要合并所有文件的枚举值
+func packageEnumFiles(dirPath string, excludes ...string) []enumFile {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil
+	}
+	excluded := make(map[string]bool)
+	for _, exclude := range excludes {
+		excluded[filepath.Base(exclude)] = true
+	}
+	var files []enumFile
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || excluded[name] || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dirPath, name))
+		if err != nil {
+			continue
+		}
+		groups, err := enumGroups(name, content)
+		if err != nil || len(groups) == 0 {
+			continue
+		}
+		files = append(files, enumFile{Name: name, Groups: groups})
+	}
+	return files
+}
+
+// enumGroups 收集types文件中结构体字段oneof标签的枚举值，按声明顺序排列
+// 只收集string及数值类型（包括其指针和切片）的字段，值无法转换为常量名或与数值类型不符时跳过该字段
+func enumGroups(filePath string, src []byte) ([]enumGroup, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filePath, src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("解析文件失败: %w", err)
+	}
+
+	var groups []enumGroup
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			for _, field := range structFields(structType) {
+				if field.Tag == nil || len(field.Names) == 0 {
+					continue
+				}
+				fieldType := enumFieldType(field.Type)
+				values := oneofValues(extractValidateTag(field.Tag.Value))
+				if fieldType == "" || !validEnumValues(fieldType, values) {
+					continue
+				}
+				for _, name := range field.Names {
+					groups = append(groups, enumGroup{Struct: typeSpec.Name.Name, Field: name.Name, Type: fieldType, Values: values})
+				}
+			}
+		}
+	}
+	return groups, nil
+}
+
+// validEnumValues 判断枚举值是否都可以生成常量
+func validEnumValues(fieldType string, values []string) bool {
+	if len(values) == 0 {
+		return false
+	}
+	names := make(map[string]bool)
+	for _, value := range values {
+		// 不同的值转换为相同的常量名时无法生成，如in-progress和in_progress
+		name := enumValueName(value)
+		if name == "" || names[name] {
+			return false
+		}
+		names[name] = true
+		if enumNumericTypes[fieldType] {
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// generateEnumsFile 生成枚举常量文件，每个使用oneof标签的字段生成一组常量，常量名为<字段名><值>
+// 不同结构体中同名字段的枚举值相同时只生成一次，常量名冲突时带有结构体名
+// others为同一包中其他文件的枚举值，与当前文件一起按文件名顺序合并，生成结果与处理顺序无关
+// 没有枚举值且文件不存在时返回nil
+func generateEnumsFile(packageName, filePath string, src []byte, others []enumFile, exists bool, options Options) ([]byte, error) {
+	current, err := enumGroups(filePath, src)
+	if err != nil {
+		return nil, err
+	}
+	files := append(slices.Clone(others), enumFile{Name: filepath.Base(filePath), Groups: current})
+	slices.SortStableFunc(files, func(a, b enumFile) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	var groups []enumGroup
+	for _, file := range files {
+		groups = append(groups, file.Groups...)
+	}
+	if len(groups) == 0 && !exists {
+		return nil, nil
+	}
+
+	var content strings.Builder
+	content.WriteString(GeneratedHeader)
+	content.WriteString(fmt.Sprintf("package %s\n", packageName))
+
+	// 已生成的常量名及其类型和值
+	declared := make(map[string]string)
+	for _, g := range groups {
+		prefix := false
+		duplicate := true
+		for _, value := range g.Values {
+			if v, ok := declared[g.constName(value, false)]; !ok || v != g.Type+" "+g.literal(value) {
+				duplicate = false
+			}
+			if _, ok := declared[g.constName(value, false)]; ok {
+				prefix = true
+			}
+		}
+		if duplicate {
+			continue
+		}
+
+		content.WriteString(fmt.Sprintf("\n// %s.%s 的可选值\nconst (\n", g.Struct, g.Field))
+		for _, value := range g.Values {
+			name := g.constName(value, prefix)
+			declared[name] = g.Type + " " + g.literal(value)
+			content.WriteString(fmt.Sprintf("\t%s %s = %s\n", name, g.Type, g.literal(value)))
+		}
+		content.WriteString(")\n")
+	}
+
+	formatted, err := formatSource([]byte(content.String()), options)
+	if err != nil {
+		return nil, fmt.Errorf("格式化%s代码失败: %w", EnumsFileName, err)
+	}
+	return formatted, nil
+}
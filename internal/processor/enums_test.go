@@ -0,0 +1,59 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnumsMergeTypesFiles(t *testing.T) {
+	orderSrc := "package types\n\n" +
+		"type OrderReq struct {\n" +
+		"\tStatus string `json:\"status\" validate:\"oneof=pending paid\"`\n" +
+		"}\n"
+	shipSrc := "package types\n\n" +
+		"type ShipReq struct {\n" +
+		"\tMode int `json:\"mode\" validate:\"oneof=1 2\"`\n" +
+		"}\n"
+
+	// 同一包中的types文件无论按什么顺序处理，enums.go都包含所有文件的枚举常量
+	var results []string
+	for _, order := range [][]string{{"order.go", "ship.go"}, {"ship.go", "order.go"}} {
+		dir := t.TempDir()
+		files := map[string]string{
+			"order.go": writeTypesFile(t, dir, "order.go", orderSrc),
+			"ship.go":  writeTypesFile(t, dir, "ship.go", shipSrc),
+		}
+		if err := processFiles(t, Options{GenerateEnums: true}, files[order[0]], files[order[1]]); err != nil {
+			t.Fatal(err)
+		}
+		enums := readFile(t, dir, EnumsFileName)
+		for _, want := range []string{`StatusPending string = "pending"`, `StatusPaid string = "paid"`, "Mode1 int = 1", "Mode2 int = 2"} {
+			if !containsCode(enums, want) {
+				t.Errorf("processing %v: %s does not contain %q:\n%s", order, EnumsFileName, want, enums)
+			}
+		}
+		results = append(results, enums)
+	}
+	if results[0] != results[1] {
+		t.Errorf("%s depends on the processing order:\n%s\n---\n%s", EnumsFileName, results[0], results[1])
+	}
+}
+
+func TestEnumValueName(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"pending", "Pending"},
+		{"in_progress", "InProgress"},
+		{"in-progress", "InProgress"},
+		{"-1", "Neg1"},
+		{"1.5", "1_5"},
+		{"'a b'", "AB"},
+	}
+	for _, tt := range tests {
+		if got := enumValueName(strings.Trim(tt.value, "'")); got != tt.want {
+			t.Errorf("enumValueName(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package processor
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// errorCodeEntry 验证标签与错误码常量的对应关系
+type errorCodeEntry struct {
+	Tag   string
+	Const string
+	Code  int
+}
+
+// errorCodeTable 内置的错误码表，生成的ErrCodes映射可以在业务代码中继续扩展
+var errorCodeTable = []errorCodeEntry{
+	{Tag: "required", Const: "ErrCodeRequired", Code: 10001},
+	{Tag: "mobile", Const: "ErrCodeMobile", Code: 10002},
+	{Tag: "email", Const: "ErrCodeEmail", Code: 10003},
+	{Tag: "idcard", Const: "ErrCodeIDCard", Code: 10004},
+}
+
+const (
+	// ErrorCodeFileName 错误码文件名
+	ErrorCodeFileName = "errcode.go"
+
+	// ErrorCodeTypes 聚合验证错误类型
+	ErrorCodeTypes = `
+// ErrCodes 验证标签对应的错误码，未配置的标签使用ErrCodeInvalid
+// 可以在业务代码的init中添加自定义标签的错误码
+var ErrCodes = map[string]int{
+%[1]s}
+
+// ValidationError 单个字段的验证错误
+type ValidationError struct {
+	Field   string // 字段名
+	Tag     string // 验证标签
+	Code    int    // 错误码
+	Message string // 翻译后的错误信息
+}
+
+// ValidationErrors 聚合的验证错误
+type ValidationErrors struct {
+	Errors []ValidationError
+}
+
+// Error 实现error接口，返回所有错误信息
+func (e *ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Message)
+	}
+	return strings.Join(msgs, ", ")
+}
+
+// First 返回第一个验证错误
+func (e *ValidationErrors) First() *ValidationError {
+	if e == nil || len(e.Errors) == 0 {
+		return nil
+	}
+	return &e.Errors[0]
+}
+
+// FirstCode 返回第一个验证错误的错误码
+func (e *ValidationErrors) FirstCode() int {
+	if first := e.First(); first != nil {
+		return first.Code
+	}
+	return 0
+}
+
+// newValidationErrors 将验证器返回的错误转换为*ValidationErrors
+func newValidationErrors(err error) error {
+	if err == nil {
+		return nil
+	}
+	var es validator.ValidationErrors
+	if !errors.As(err, &es) {
+		return err
+	}
+	result := &ValidationErrors{Errors: make([]ValidationError, 0, len(es))}
+	for _, fe := range es {
+		code, ok := ErrCodes[fe.Tag()]
+		if !ok {
+			code = ErrCodeInvalid
+		}
+		result.Errors = append(result.Errors, ValidationError{
+			Field:   fe.Field(),
+			Tag:     fe.Tag(),
+			Code:    code,
+			Message: %[2]s,
+		})
+	}
+	return result
+}
+`
+
+	// ErrorCodeValidateMethod 返回聚合验证错误的Validate方法
+	ErrorCodeValidateMethod = `
+func (%[3]s *%[1]s) Validate() error {
+	return newValidationErrors(%[2]s.Struct(%[3]s))
+}
+`
+)
+
+// generateErrorCodeFile 生成错误码文件，文件已存在时不再生成以保留用户的修改
+// 配置文件中设置了错误码的验证器同样生成错误码常量，tr不为nil时使用翻译器翻译错误信息
+func generateErrorCodeFile(packageName string, validations []builtInValidation, tr *translatorRef, options Options) ([]byte, error) {
+
+	var content strings.Builder
+	content.WriteString(GeneratedHeader)
+	content.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+	content.WriteString("import (\n")
+	content.WriteString("\t\"errors\"\n")
+	content.WriteString("\t\"strings\"\n\n")
+	content.WriteString("\t" + ValidateImport + "\n")
+	if tr.shared() {
+		content.WriteString(fmt.Sprintf("\t%q\n", tr.Import))
+	}
+	content.WriteString(")\n\n")
+
+	// 错误码常量
+	entries := append([]errorCodeEntry(nil), errorCodeTable...)
+	for _, v := range validations {
+		if v.ErrorCode != 0 && v.Alias == "" {
+			entries = append(entries, errorCodeEntry{Tag: v.Tag, Const: "ErrCode" + exportName(v.Tag), Code: v.ErrorCode})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Code < entries[j].Code
+	})
+	content.WriteString("// 验证错误码\n")
+	content.WriteString("const (\n")
+	content.WriteString("\tErrCodeInvalid = 10000 // 未配置错误码的验证标签\n")
+	for _, entry := range entries {
+		content.WriteString(fmt.Sprintf("\t%s = %d // %s\n", entry.Const, entry.Code, entry.Tag))
+	}
+	content.WriteString(")\n")
+
+	// 标签与错误码的映射
+	var codes strings.Builder
+	for _, entry := range entries {
+		codes.WriteString(fmt.Sprintf("\t%q: %s,\n", entry.Tag, entry.Const))
+	}
+	// 别名使用指向的验证标签的错误码
+	for _, v := range validations {
+		if v.Alias == "" {
+			continue
+		}
+		if i := slices.IndexFunc(entries, func(e errorCodeEntry) bool { return e.Tag == v.Alias }); i >= 0 {
+			codes.WriteString(fmt.Sprintf("\t%q: %s,\n", v.Tag, entries[i].Const))
+		}
+	}
+	translateExpr := "fe.Translate(trans)"
+	if tr != nil {
+		translateExpr = tr.fieldExpr("fe")
+	}
+	content.WriteString(fmt.Sprintf(ErrorCodeTypes, codes.String(), translateExpr))
+
+	formatted, err := formatSource([]byte(content.String()), options)
+	if err != nil {
+		return nil, fmt.Errorf("格式化%s代码失败: %w", ErrorCodeFileName, err)
+	}
+	return formatted, nil
+}
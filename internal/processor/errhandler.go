@@ -0,0 +1,79 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// ErrorHandlerFileName 错误处理函数文件名
+	ErrorHandlerFileName = "errhandler.go"
+
+	// ErrorHandlerFunc 将验证错误转换为go-zero响应的错误处理函数
+	// %[1]s 为获取验证错误信息的代码，%[2]s 为聚合验证错误的处理代码
+	ErrorHandlerFunc = `
+// ErrorResponse 错误响应
+type ErrorResponse struct {
+	Code int    ` + "`json:\"code\"`" + `
+	Msg  string ` + "`json:\"msg\"`" + `
+}
+
+// ValidationErrorHandler 将验证错误转换为HTTP 400响应，其他错误返回HTTP 500
+// 使用方式: httpx.SetErrorHandler(types.ValidationErrorHandler)
+func ValidationErrorHandler(err error) (int, any) {
+%[2]s	var es validator.ValidationErrors
+	if errors.As(err, &es) {
+		return http.StatusBadRequest, ErrorResponse{Code: http.StatusBadRequest, Msg: %[1]s}
+	}
+	return http.StatusInternalServerError, ErrorResponse{Code: http.StatusInternalServerError, Msg: err.Error()}
+}
+`
+
+	// ErrorHandlerCodesBranch 聚合验证错误使用第一个错误的错误码
+	ErrorHandlerCodesBranch = `	var ve *ValidationErrors
+	if errors.As(err, &ve) {
+		return http.StatusBadRequest, ErrorResponse{Code: ve.FirstCode(), Msg: ve.Error()}
+	}
+`
+
+	// ErrorHandlerValidateMethod 返回原始验证错误的Validate方法，由ValidationErrorHandler负责翻译
+	ErrorHandlerValidateMethod = `
+func (%[3]s *%[1]s) Validate() error {
+	return %[2]s.Struct(%[3]s)
+}
+`
+)
+
+// generateErrorHandlerFile 生成错误处理函数文件，文件已存在时不再生成以保留用户的修改
+// tr不为nil时使用翻译器翻译错误信息
+func generateErrorHandlerFile(packageName string, options Options, tr *translatorRef) ([]byte, error) {
+
+	var content strings.Builder
+	content.WriteString(GeneratedHeader)
+	content.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+	content.WriteString("import (\n")
+	content.WriteString("\t\"errors\"\n")
+	content.WriteString("\t\"net/http\"\n\n")
+	content.WriteString("\t" + ValidateImport + "\n")
+	if tr.shared() {
+		content.WriteString(fmt.Sprintf("\t%q\n", tr.Import))
+	}
+	content.WriteString(")\n")
+
+	// 启用翻译器时使用翻译后的错误信息
+	message := "err.Error()"
+	if tr != nil {
+		message = tr.translateFunc() + "(err).Error()"
+	}
+	codesBranch := ""
+	if options.GenerateErrorCodes {
+		codesBranch = ErrorHandlerCodesBranch
+	}
+	content.WriteString(fmt.Sprintf(ErrorHandlerFunc, message, codesBranch))
+
+	formatted, err := formatSource([]byte(content.String()), options)
+	if err != nil {
+		return nil, fmt.Errorf("格式化%s代码失败: %w", ErrorHandlerFileName, err)
+	}
+	return formatted, nil
+}
@@ -0,0 +1,321 @@
+package processor
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// parseExistingFile 解析已生成的文件，用于检查已存在的声明，保证重复执行插件时不会重复生成代码
+func parseExistingFile(filePath string, content []byte) (*ast.File, error) {
+	return parser.ParseFile(token.NewFileSet(), filePath, content, parser.ParseComments)
+}
+
+// declaredFuncs 获取文件中声明的所有函数名（不包括方法）
+func declaredFuncs(f *ast.File) map[string]bool {
+	funcs := make(map[string]bool)
+	for _, decl := range f.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil {
+			funcs[funcDecl.Name.Name] = true
+		}
+	}
+	return funcs
+}
+
+// funcSources 获取文件中声明的函数（包括文档注释）的源码，key为函数名
+func funcSources(filePath string, content []byte) (map[string]string, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filePath, content, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	sources := make(map[string]string)
+	for _, decl := range f.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv != nil {
+			continue
+		}
+		start := funcDecl.Pos()
+		if funcDecl.Doc != nil {
+			start = funcDecl.Doc.Pos()
+		}
+		sources[funcDecl.Name.Name] = string(content[fset.Position(start).Offset:fset.Position(funcDecl.End()).Offset])
+	}
+	return sources, nil
+}
+
+// funcBodyEnd 获取文件中函数体结束的大括号的偏移量，函数不存在时返回false
+// 通过AST定位，不受字符串及注释中的大括号（如翻译文本中的{0}）影响
+func funcBodyEnd(filePath string, content []byte, name string) (int, bool, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filePath, content, parser.ParseComments)
+	if err != nil {
+		return 0, false, err
+	}
+	for _, decl := range f.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if ok && funcDecl.Recv == nil && funcDecl.Name.Name == name && funcDecl.Body != nil {
+			return fset.Position(funcDecl.Body.Rbrace).Offset, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// parsePackageFiles 解析目录中除指定文件外的Go文件（测试文件除外），无法读取或解析的文件跳过，不影响生成
+func parsePackageFiles(dirPath string, excludes ...string) []*ast.File {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil
+	}
+	excluded := make(map[string]bool)
+	for _, exclude := range excludes {
+		excluded[filepath.Base(exclude)] = true
+	}
+	var files []*ast.File
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || excluded[name] || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		filePath := filepath.Join(dirPath, name)
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			continue
+		}
+		f, err := parseExistingFile(filePath, content)
+		if err != nil {
+			continue
+		}
+		files = append(files, f)
+	}
+	return files
+}
+
+// packageFuncs 获取目录中除指定文件外其他Go文件声明的函数，用于避免与用户自行实现的函数重复声明
+func packageFuncs(dirPath string, excludes ...string) map[string]bool {
+	funcs := make(map[string]bool)
+	for _, f := range parsePackageFiles(dirPath, excludes...) {
+		for funcName := range declaredFuncs(f) {
+			funcs[funcName] = true
+		}
+	}
+	return funcs
+}
+
+// packageVars 获取目录中除指定文件外其他Go文件声明的包级变量
+func packageVars(dirPath string, excludes ...string) map[string]bool {
+	vars := make(map[string]bool)
+	for _, f := range parsePackageFiles(dirPath, excludes...) {
+		maps.Copy(vars, fileVars(f))
+	}
+	return vars
+}
+
+// fileVars 获取文件声明的包级变量
+func fileVars(f *ast.File) map[string]bool {
+	vars := make(map[string]bool)
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			for _, name := range spec.(*ast.ValueSpec).Names {
+				vars[name.Name] = true
+			}
+		}
+	}
+	return vars
+}
+
+// packageMethods 获取目录中除指定文件外其他Go文件声明了指定方法的接收者类型名，键为方法名
+// 用户在其他文件中自行实现的Validate等方法，无论指针还是值接收者，都不再重复生成
+func packageMethods(dirPath string, methods []string, excludes ...string) map[string]map[string]bool {
+	receivers := make(map[string]map[string]bool)
+	files := parsePackageFiles(dirPath, excludes...)
+	for _, method := range methods {
+		receivers[method] = make(map[string]bool)
+		for _, f := range files {
+			maps.Copy(receivers[method], methodReceivers(f, method))
+		}
+	}
+	return receivers
+}
+
+// validationFuncCode 生成插件内置及配置文件定义的验证函数代码
+// sources中用户修改过的函数优先保留，declared中已在其他文件声明的函数不再生成
+func validationFuncCode(validations []builtInValidation, sources map[string]string, declared map[string]bool) string {
+	var code strings.Builder
+	written := make(map[string]bool)
+	for _, v := range validations {
+		// 别名与指向的验证标签共用验证函数，只生成一次
+		if declared[v.Func] || written[v.Func] {
+			continue
+		}
+		written[v.Func] = true
+		if source, ok := sources[v.Func]; ok {
+			code.WriteString("\n" + source + "\n")
+			continue
+		}
+		code.WriteString(v.Code)
+	}
+	return code.String()
+}
+
+// funcReturnsError 判断文件中声明的函数是否只返回一个error
+func funcReturnsError(f *ast.File, name string) bool {
+	for _, decl := range f.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv != nil || funcDecl.Name.Name != name {
+			continue
+		}
+		results := funcDecl.Type.Results
+		if results == nil || len(results.List) != 1 || len(results.List[0].Names) > 1 {
+			return false
+		}
+		ident, ok := results.List[0].Type.(*ast.Ident)
+		return ok && ident.Name == "error"
+	}
+	return false
+}
+
+// methodReceivers 获取文件中声明了指定方法的接收者类型名，指针和值接收者都会被统计
+func methodReceivers(f *ast.File, method string) map[string]bool {
+	receivers := make(map[string]bool)
+	for _, decl := range f.Decls {
+		funcDecl, ok := decl.(*ast.FuncDecl)
+		if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 || funcDecl.Name.Name != method {
+			continue
+		}
+		recvType := funcDecl.Recv.List[0].Type
+		if star, ok := recvType.(*ast.StarExpr); ok {
+			recvType = star.X
+		}
+		if ident, ok := recvType.(*ast.Ident); ok {
+			receivers[ident.Name] = true
+		}
+	}
+	return receivers
+}
+
+// registeredValidations 获取registerValidation映射中已注册的验证标签
+// key为验证标签，value为验证函数名，注册的不是函数名时为空字符串
+func registeredValidations(f *ast.File) map[string]string {
+	tags := make(map[string]string)
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			valueSpec, ok := spec.(*ast.ValueSpec)
+			if !ok {
+				continue
+			}
+			for i, name := range valueSpec.Names {
+				if name.Name != "registerValidation" || i >= len(valueSpec.Values) {
+					continue
+				}
+				lit, ok := valueSpec.Values[i].(*ast.CompositeLit)
+				if !ok {
+					continue
+				}
+				for _, elt := range lit.Elts {
+					kv, ok := elt.(*ast.KeyValueExpr)
+					if !ok {
+						continue
+					}
+					tag, ok := stringLiteral(kv.Key)
+					if !ok {
+						continue
+					}
+					tags[tag] = ""
+					if fn, ok := kv.Value.(*ast.Ident); ok {
+						tags[tag] = fn.Name
+					}
+				}
+			}
+		}
+	}
+	return tags
+}
+
+// registeredTranslationTags 获取通过RegisterTranslation注册过翻译的标签
+func registeredTranslationTags(f *ast.File) map[string]bool {
+	tags := make(map[string]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "RegisterTranslation" {
+			return true
+		}
+		if tag, ok := stringLiteral(call.Args[0]); ok {
+			tags[tag] = true
+		}
+		return true
+	})
+	return tags
+}
+
+// registerValidationCalls 获取旧格式的验证文件中通过validate.RegisterValidation("tag", fn)注册的验证方法
+// key为验证标签，value为验证函数名
+func registerValidationCalls(f *ast.File) map[string]string {
+	calls := make(map[string]string)
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) < 2 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "RegisterValidation" {
+			return true
+		}
+		tag, ok := stringLiteral(call.Args[0])
+		if !ok {
+			return true
+		}
+		if fn, ok := call.Args[1].(*ast.Ident); ok {
+			calls[tag] = fn.Name
+		}
+		return true
+	})
+	return calls
+}
+
+// fileImports 获取文件的导入，Name为导入时指定的别名
+func fileImports(f *ast.File) []importSpec {
+	var imports []importSpec
+	for _, imp := range f.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		spec := importSpec{Path: path}
+		if imp.Name != nil {
+			spec.Name = imp.Name.Name
+		}
+		imports = append(imports, spec)
+	}
+	return imports
+}
+
+// stringLiteral 获取字符串字面量的值
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", false
+	}
+	return value, true
+}
@@ -0,0 +1,142 @@
+package processor
+
+import (
+	"fmt"
+	"go/ast"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FieldMessageTag 字段自定义验证错误信息的结构体标签
+	FieldMessageTag = "msg"
+
+	// FieldMessageFuncs 翻译器中按字段查找自定义错误信息的代码
+	FieldMessageFuncs = `
+// fieldMessages 字段通过msg标签自定义的验证错误信息
+// key: 去掉下标的结构体命名空间，如CreateItemReq.Items.Name
+var fieldMessages = map[string]string{}
+
+// registerFieldMessages 注册字段自定义的验证错误信息
+func registerFieldMessages(messages map[string]string) {
+	for field, msg := range messages {
+		fieldMessages[field] = msg
+	}
+}
+
+// translateField 翻译单个字段的验证错误，字段自定义了错误信息时优先使用
+func translateField(fe validator.FieldError, t ut.Translator) string {
+	if msg, ok := fieldMessages[fieldMessageKey(fe.StructNamespace())]; ok {
+		return msg
+	}
+	return fe.Translate(t)
+}
+
+// fieldMessageKey 去掉结构体命名空间中切片、数组和map的下标
+func fieldMessageKey(namespace string) string {
+	var key strings.Builder
+	depth := 0
+	for _, r := range namespace {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case depth == 0:
+			key.WriteRune(r)
+		}
+	}
+	return key.String()
+}
+`
+
+	// FieldMessageRegisterTemplate 注册结构体字段自定义错误信息的初始化函数
+	FieldMessageRegisterTemplate = `
+func init() {
+	%s(map[string]string{
+%s	})
+}
+`
+)
+
+// fieldMessage 字段自定义的验证错误信息
+type fieldMessage struct {
+	// 去掉下标的结构体命名空间
+	Key string
+	// 错误信息
+	Message string
+}
+
+// fieldMessages 获取结构体及其引用的同文件结构体中通过msg标签自定义的错误信息，按字段声明顺序排列
+func fieldMessages(name string, localStructs map[string]*ast.StructType) []fieldMessage {
+	var messages []fieldMessage
+	visiting := make(map[string]bool)
+	var visit func(prefix, typeName string)
+	var visitFields func(prefix string, structType *ast.StructType)
+	visit = func(prefix, typeName string) {
+		structType, ok := localStructs[typeName]
+		if !ok || visiting[typeName] {
+			return
+		}
+		visiting[typeName] = true
+		defer delete(visiting, typeName)
+		visitFields(prefix, structType)
+	}
+	visitFields = func(prefix string, structType *ast.StructType) {
+		for _, field := range structType.Fields.List {
+			// 匿名嵌入字段在命名空间中使用类型名
+			var names []string
+			for _, ident := range field.Names {
+				names = append(names, ident.Name)
+			}
+			if len(field.Names) == 0 {
+				names = append(names, embeddedTypeName(field.Type))
+			}
+			for _, fieldName := range names {
+				if fieldName == "" {
+					continue
+				}
+				namespace := prefix + "." + fieldName
+				if msg := structTagValue(field, FieldMessageTag); msg != "" {
+					messages = append(messages, fieldMessage{Key: namespace, Message: msg})
+				}
+				for _, ref := range fieldTypeNames(field.Type) {
+					visit(namespace, ref)
+				}
+				// 匿名结构体的字段在命名空间中位于该字段之下
+				for _, inline := range inlineStructTypes(field.Type) {
+					visitFields(namespace, inline)
+				}
+			}
+		}
+	}
+	visit(name, name)
+	return messages
+}
+
+// structTagValue 获取字段结构体标签中指定键的值
+func structTagValue(field *ast.Field, key string) string {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return ""
+	}
+	return reflect.StructTag(tag).Get(key)
+}
+
+// fieldMessageCode 生成注册结构体字段自定义错误信息的代码，没有自定义错误信息时返回空字符串
+// registerFunc为注册错误信息的函数
+func fieldMessageCode(name string, localStructs map[string]*ast.StructType, registerFunc string) string {
+	messages := fieldMessages(name, localStructs)
+	if len(messages) == 0 {
+		return ""
+	}
+	var entries strings.Builder
+	for _, m := range messages {
+		entries.WriteString(fmt.Sprintf("\t\t%q: %q,\n", m.Key, m.Message))
+	}
+	return fmt.Sprintf(FieldMessageRegisterTemplate, registerFunc, entries.String())
+}
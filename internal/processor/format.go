@@ -0,0 +1,37 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"go/scanner"
+	"strings"
+)
+
+// formatSource 格式化生成的代码，失败时错误信息中带有出错的行号及该行代码
+// 调试模式下同时带有完整的待格式化代码，便于排查拼接代码产生的语法错误
+func formatSource(src []byte, options Options) ([]byte, error) {
+	formatted, err := format.Source(src)
+	if err == nil {
+		return formatted, nil
+	}
+	var list scanner.ErrorList
+	if !errors.As(err, &list) || len(list) == 0 {
+		return nil, err
+	}
+
+	lines := strings.Split(string(src), "\n")
+	line := list[0].Pos.Line
+	if line < 1 || line > len(lines) {
+		return nil, err
+	}
+	err = fmt.Errorf("%w\n第%d行: %s", err, line, strings.TrimSpace(lines[line-1]))
+	if options.DebugMode {
+		var numbered strings.Builder
+		for i, l := range lines {
+			numbered.WriteString(fmt.Sprintf("%4d| %s\n", i+1, l))
+		}
+		err = fmt.Errorf("%w\n格式化前的代码:\n%s", err, numbered.String())
+	}
+	return nil, err
+}
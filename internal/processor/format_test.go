@@ -0,0 +1,36 @@
+package processor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatSourceError(t *testing.T) {
+	src := "package types\n\nfunc validateTag(fl validator.FieldLevel) bool {\n\treturn true\n\nvar x = 1\n"
+	tests := []struct {
+		name      string
+		debug     bool
+		wantDump  bool
+		wantLines []string
+	}{
+		{"default", false, false, []string{"第6行: var x = 1"}},
+		// 调试模式下附带格式化前带行号的完整代码
+		{"debug", true, true, []string{"第6行: var x = 1", "格式化前的代码:", "   3| func validateTag(fl validator.FieldLevel) bool {"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := formatSource([]byte(src), Options{DebugMode: tt.debug})
+			if err == nil {
+				t.Fatal("formatSource() error = nil, want a syntax error")
+			}
+			for _, want := range tt.wantLines {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("formatSource() error = %q, want it to contain %q", err, want)
+				}
+			}
+			if got := strings.Contains(err.Error(), "格式化前的代码"); got != tt.wantDump {
+				t.Errorf("formatSource() error contains the source = %v, want %v", got, tt.wantDump)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+//go:build ignore
+
+// gen_builtin_tags 根据go.mod中的go-playground/validator版本生成builtin_tags.go
+// 读取validator源码baked_in.go中的bakedInValidators、bakedInAliases和restrictedTags，
+// restrictedTags的键是validator_instance.go中定义的常量，需要解析常量值
+//
+// 使用方法：升级validator后在internal/processor目录执行go generate
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const validatorModule = "github.com/go-playground/validator/v10"
+
+// skipConsts restrictedTags中不是验证标签的常量，用于转义标签中的逗号和竖线
+var skipConsts = map[string]bool{
+	"utf8HexComma": true,
+	"utf8Pipe":     true,
+}
+
+func main() {
+	out, err := exec.Command("go", "list", "-m", "-f", "{{.Version}} {{.Dir}}", validatorModule).Output()
+	if err != nil {
+		log.Fatalf("查找%s失败: %v", validatorModule, err)
+	}
+	version, dir, _ := strings.Cut(strings.TrimSpace(string(out)), " ")
+
+	fset := token.NewFileSet()
+	consts, err := parseConsts(fset, filepath.Join(dir, "validator_instance.go"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	bakedIn, err := parser.ParseFile(fset, filepath.Join(dir, "baked_in.go"), nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	tags := map[string]bool{}
+	for _, name := range []string{"restrictedTags", "bakedInAliases", "bakedInValidators"} {
+		lit := findMapLiteral(bakedIn, name)
+		if lit == nil {
+			log.Fatalf("baked_in.go中没有找到%s", name)
+		}
+		for _, elt := range lit.Elts {
+			switch key := elt.(*ast.KeyValueExpr).Key.(type) {
+			case *ast.BasicLit:
+				tag, err := strconv.Unquote(key.Value)
+				if err != nil {
+					log.Fatal(err)
+				}
+				tags[tag] = true
+			case *ast.Ident:
+				if skipConsts[key.Name] {
+					continue
+				}
+				tag, ok := consts[key.Name]
+				if !ok {
+					log.Fatalf("validator_instance.go中没有找到常量%s", key.Name)
+				}
+				tags[tag] = true
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(tags))
+	for tag := range tags {
+		sorted = append(sorted, tag)
+	}
+	slices.Sort(sorted)
+
+	var code bytes.Buffer
+	fmt.Fprintf(&code, "// Code generated from %s@%s baked_in.go and validator_instance.go. DO NOT EDIT.\n\n", validatorModule, version)
+	code.WriteString("package processor\n\n")
+	code.WriteString("// goPlaygroundTags go-playground/validator v10内置的验证标签、别名及控制标签\n")
+	code.WriteString("var goPlaygroundTags = map[string]bool{\n")
+	for _, tag := range sorted {
+		fmt.Fprintf(&code, "\t%q: true,\n", tag)
+	}
+	code.WriteString("}\n")
+
+	formatted, err := format.Source(code.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("builtin_tags.go", formatted, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseConsts 解析文件中的字符串常量
+func parseConsts(fset *token.FileSet, path string) (map[string]string, error) {
+	file, err := parser.ParseFile(fset, path, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	consts := map[string]string{}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if i >= len(vs.Values) {
+					continue
+				}
+				if lit, ok := vs.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+					consts[name.Name], _ = strconv.Unquote(lit.Value)
+				}
+			}
+		}
+	}
+	return consts, nil
+}
+
+// findMapLiteral 查找包级变量的map字面量
+func findMapLiteral(file *ast.File, name string) *ast.CompositeLit {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, n := range vs.Names {
+				if n.Name != name || i >= len(vs.Values) {
+					continue
+				}
+				if lit, ok := vs.Values[i].(*ast.CompositeLit); ok {
+					return lit
+				}
+			}
+		}
+	}
+	return nil
+}
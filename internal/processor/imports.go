@@ -0,0 +1,135 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// importSpec 需要添加的导入，Name为空时不使用别名
+type importSpec struct {
+	Name string
+	Path string
+}
+
+// usedName 获取导入在代码中使用的包名
+func (imp importSpec) usedName() string {
+	if imp.Name != "" {
+		return imp.Name
+	}
+	return imp.Path[strings.LastIndex(imp.Path, "/")+1:]
+}
+
+// String 返回导入声明中的写法
+func (imp importSpec) String() string {
+	if imp.Name != "" {
+		return fmt.Sprintf("%s %q", imp.Name, imp.Path)
+	}
+	return strconv.Quote(imp.Path)
+}
+
+// typesImportOptions types.go中需要添加的导入
+type typesImportOptions struct {
+	// 验证器
+	Validator bool
+	// 验证器包在文件中的导入名，与默认包名不同时使用别名导入
+	ValidatorName string
+	// 翻译器及默认翻译，用于声明验证器变量
+	Translations bool
+	// Validate方法使用的fmt
+	Fmt bool
+	// ValidateCtx方法使用的context
+	Context bool
+	// 进程内共享验证器的库包
+	SharedValidator bool
+}
+
+// typesImports 获取types.go中Validate方法及验证器变量需要的导入
+func typesImports(lang string, opts typesImportOptions) []importSpec {
+	var imports []importSpec
+	if opts.Context {
+		imports = append(imports, importSpec{Path: "context"})
+	}
+	if opts.Fmt {
+		imports = append(imports, importSpec{Path: "fmt"})
+	}
+	if opts.Validator {
+		imp := importSpec{Path: validatorImportPath}
+		if opts.ValidatorName != "" && opts.ValidatorName != imp.usedName() {
+			imp.Name = opts.ValidatorName
+		}
+		imports = append(imports, imp)
+	}
+	if opts.SharedValidator {
+		imports = append(imports, importSpec{Name: SharedValidatorName, Path: SharedValidatorImport})
+	}
+	if opts.Translations {
+		imports = append(imports,
+			importSpec{Path: "github.com/go-playground/locales/" + lang},
+			importSpec{Name: "ut", Path: "github.com/go-playground/universal-translator"},
+			importSpec{Name: lang + "Translations", Path: "github.com/go-playground/validator/v10/translations/" + lang},
+		)
+	}
+	return imports
+}
+
+// validatorImportPath 验证器包的导入路径
+const validatorImportPath = "github.com/go-playground/validator/v10"
+
+// validatorPkgRef 生成代码中对验证器包的引用
+var validatorPkgRef = regexp.MustCompile(`\bvalidator\.`)
+
+// validatorImportName 获取文件中验证器包的导入名，文件使用别名导入时返回别名，否则返回validator
+func validatorImportName(f *ast.File) string {
+	for _, imp := range f.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || path != validatorImportPath || imp.Name == nil {
+			continue
+		}
+		// 匿名导入和点导入无法通过包名引用
+		if imp.Name.Name != "_" && imp.Name.Name != "." {
+			return imp.Name.Name
+		}
+	}
+	return "validator"
+}
+
+// renameValidatorPkg 将生成代码中对验证器包的引用替换为文件中使用的导入名
+func renameValidatorPkg(code, name string) string {
+	if name == "validator" {
+		return code
+	}
+	return validatorPkgRef.ReplaceAllString(code, name+".")
+}
+
+// contextMethod 根据生成的Validate方法生成对应的ValidateCtx方法，receiver为方法的接收者名称
+func contextMethod(method, receiver string) string {
+	method = strings.Replace(method, ") Validate() error {", ") ValidateCtx(ctx context.Context) error {", 1)
+	method = strings.Replace(method, ".Struct("+receiver+")", ".StructCtx(ctx, "+receiver+")", 1)
+	// 启用独立验证包时调用验证包的ValidateCtx
+	return strings.Replace(method, ".Validate("+receiver+")", ".ValidateCtx(ctx, "+receiver+")", 1)
+}
+
+// addImports 通过AST将导入合并到文件已有的导入分组中，返回格式化后的文件内容
+func addImports(fset *token.FileSet, f *ast.File, imports []importSpec) ([]byte, error) {
+	for _, imp := range imports {
+		if imp.Name == "" {
+			astutil.AddImport(fset, f, imp.Path)
+		} else {
+			astutil.AddNamedImport(fset, f, imp.Name, imp.Path)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, f); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
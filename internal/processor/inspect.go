@@ -0,0 +1,237 @@
+package processor
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// TagKind 验证标签的类别
+type TagKind string
+
+const (
+	// TagBuiltIn validator内置的验证标签
+	TagBuiltIn TagKind = "built-in"
+	// TagPlugin 插件内置的验证标签，如mobile、idcard
+	TagPlugin TagKind = "built-in (plugin)"
+	// TagConfig 配置文件定义的验证标签
+	TagConfig TagKind = "config"
+	// TagCustom 自定义验证标签，启用--custom时生成验证方法桩
+	TagCustom TagKind = "custom"
+)
+
+// Inspection 扫描目录得到的结构体及其验证标签
+type Inspection struct {
+	Structs []StructInspection
+}
+
+// StructInspection 包含验证标签的结构体
+type StructInspection struct {
+	// 结构体所在的文件
+	File   string
+	Name   string
+	Fields []FieldInspection
+}
+
+// FieldInspection 结构体字段的验证标签
+type FieldInspection struct {
+	Name string
+	// 字段完整的validate标签
+	Rule string
+	Tags []TagInspection
+}
+
+// TagInspection 单个验证标签及其类别
+type TagInspection struct {
+	Name  string
+	Param string
+	Kind  TagKind
+}
+
+// InspectDir 扫描目录下的.go文件，列出包含验证标签的结构体及各标签的类别，不写入任何文件
+// 字段注释中的验证规则同样参与扫描，options仅用于读取配置文件定义的验证标签
+func InspectDir(dir string, options Options) (*Inspection, error) {
+	validations, err := pluginValidations(options)
+	if err != nil {
+		return nil, err
+	}
+	knownTags := validationTags(validations)
+	// 验证规则别名不生成验证方法，与配置文件定义的验证标签归为一类
+	aliases, err := ruleAliases(options)
+	if err != nil {
+		return nil, err
+	}
+	for alias := range aliases {
+		knownTags[alias] = true
+	}
+
+	inspection := &Inspection{}
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// 跳过隐藏目录和vendor目录
+			if path != dir && (strings.HasPrefix(d.Name(), ".") || d.Name() == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		structs, err := inspectFile(path, knownTags)
+		if err != nil {
+			return err
+		}
+		inspection.Structs = append(inspection.Structs, structs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return inspection, nil
+}
+
+// inspectFile 扫描单个文件中包含验证标签的结构体
+func inspectFile(path string, knownTags map[string]bool) ([]StructInspection, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("解析文件%s失败: %w", path, err)
+	}
+
+	var structs []StructInspection
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			fields := inspectFields(structType, knownTags)
+			if len(fields) == 0 {
+				continue
+			}
+			structs = append(structs, StructInspection{File: path, Name: typeSpec.Name.Name, Fields: fields})
+		}
+	}
+	return structs, nil
+}
+
+// inspectFields 获取结构体（包括字段中的匿名结构体）中包含验证标签的字段，字段没有validate标签时使用注释中的验证规则
+func inspectFields(structType *ast.StructType, knownTags map[string]bool) []FieldInspection {
+	var fields []FieldInspection
+	for _, field := range structFields(structType) {
+		var rule string
+		if field.Tag != nil {
+			rule = extractValidateTag(field.Tag.Value)
+		}
+		// 字段已有validate标签（包括跳过该字段的validate:"-"）时与生成代码时一致，不使用注释中的规则
+		if rule == "" && (field.Tag == nil || !strings.Contains(field.Tag.Value, `validate:"`)) {
+			rule = commentRule(field)
+		}
+		if rule == "" {
+			continue
+		}
+
+		var tags []TagInspection
+		for _, v := range splitValidateTag(rule) {
+			name, param, _ := parseValidator(v)
+			if name == "" {
+				continue
+			}
+			tags = append(tags, TagInspection{Name: name, Param: param, Kind: tagKind(name, knownTags)})
+		}
+
+		name := fieldTypeName(field.Type)
+		if len(field.Names) > 0 {
+			name = field.Names[0].Name
+		}
+		fields = append(fields, FieldInspection{Name: name, Rule: rule, Tags: tags})
+	}
+	return fields
+}
+
+// fieldTypeName 获取匿名嵌入字段的类型名称
+func fieldTypeName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return fieldTypeName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// tagKind 判断验证标签的类别
+func tagKind(tag string, knownTags map[string]bool) TagKind {
+	switch {
+	case isPluginValidation(tag):
+		return TagPlugin
+	case knownTags[tag]:
+		return TagConfig
+	case isBuiltInValidator(tag):
+		return TagBuiltIn
+	}
+	return TagCustom
+}
+
+// CustomTags 获取所有会生成验证方法桩的自定义验证标签，按字母顺序排列
+func (i *Inspection) CustomTags() []string {
+	var tags []string
+	for _, s := range i.Structs {
+		for _, field := range s.Fields {
+			for _, tag := range field.Tags {
+				if tag.Kind == TagCustom && !slices.Contains(tags, tag.Name) {
+					tags = append(tags, tag.Name)
+				}
+			}
+		}
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+// String 返回扫描结果的文本格式，自定义验证标签标记为stub
+func (i *Inspection) String() string {
+	var b strings.Builder
+	for _, s := range i.Structs {
+		b.WriteString(fmt.Sprintf("%s (%s)\n", s.Name, s.File))
+		for _, field := range s.Fields {
+			b.WriteString(fmt.Sprintf("  %s `validate:%q`\n", field.Name, field.Rule))
+			for _, tag := range field.Tags {
+				name := tag.Name
+				if tag.Param != "" {
+					name += "=" + tag.Param
+				}
+				kind := string(tag.Kind)
+				if tag.Kind == TagCustom {
+					kind += ", stub"
+				}
+				b.WriteString(fmt.Sprintf("    %-20s %s\n", name, kind))
+			}
+		}
+	}
+	customTags := i.CustomTags()
+	b.WriteString(fmt.Sprintf("自定义验证标签(%d):", len(customTags)))
+	for _, tag := range customTags {
+		b.WriteString(" " + tag)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
@@ -0,0 +1,56 @@
+package processor
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestInspectDir(t *testing.T) {
+	dir := t.TempDir()
+	writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type CreateUserReq struct {\n"+
+		"\tMobile string `json:\"mobile\" validate:\"required,mobile\"`\n"+
+		"\tCode   string `json:\"code\" validate:\"foo=1\"`\n"+
+		"\t// validate: min=2\n"+
+		"\tName string `json:\"name\"`\n"+
+		"}\n\n"+
+		"type CreateUserResp struct {\n"+
+		"\tID int `json:\"id\"`\n"+
+		"}\n")
+	before := snapshotDir(t, dir)
+	inspection, err := InspectDir(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inspection.Structs) != 1 || inspection.Structs[0].Name != "CreateUserReq" {
+		t.Fatalf("Structs = %+v, want CreateUserReq only", inspection.Structs)
+	}
+	kinds := make(map[string]TagKind)
+	for _, field := range inspection.Structs[0].Fields {
+		for _, tag := range field.Tags {
+			kinds[tag.Name] = tag.Kind
+		}
+	}
+	want := map[string]TagKind{"required": TagBuiltIn, "mobile": TagPlugin, "foo": TagCustom, "min": TagBuiltIn}
+	for tag, kind := range want {
+		if kinds[tag] != kind {
+			t.Errorf("kind of %s = %q, want %q", tag, kinds[tag], kind)
+		}
+	}
+	if got := inspection.CustomTags(); !slices.Equal(got, []string{"foo"}) {
+		t.Errorf("CustomTags() = %v, want [foo]", got)
+	}
+	// 会生成验证方法桩的标签标记为stub
+	out := inspection.String()
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, "stub") != strings.HasPrefix(strings.TrimSpace(line), "foo=1") {
+			t.Errorf("String() marks stubs wrongly:\n%s", out)
+			break
+		}
+	}
+	if after := snapshotDir(t, dir); !maps.Equal(after, before) {
+		t.Errorf("InspectDir changed files on disk: %v", slices.Sorted(maps.Keys(after)))
+	}
+}
@@ -0,0 +1,38 @@
+package processor
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// LabelTag 字段显示名称的标签，启用后错误信息中的字段名优先使用该标签
+// 例如: Name string `json:"name" validate:"required" label:"用户名"` -> 用户名为必填字段
+const LabelTag = "label"
+
+// jsonTagNameRegex 匹配注册字段名函数中读取json标签的语句
+var jsonTagNameRegex = regexp.MustCompile(`(RegisterTagNameFunc\(func\(field reflect\.StructField\) string \{\n)(\t+)(name := strings\.SplitN\(field\.Tag\.Get\("json"\))`)
+
+// labelTagNameOptions 检查使用label标签作为字段名时的选项
+// ValidateJSON以字段名作为json名称返回错误信息，字段名改为label后不再是json名称
+func labelTagNameOptions(options Options) error {
+	if !options.UseLabelTag {
+		return nil
+	}
+	if !options.EnableTranslator {
+		return fmt.Errorf("--label需要同时启用--translator")
+	}
+	if options.GenerateJSONMethod {
+		return fmt.Errorf("--label不能与--json同时使用")
+	}
+	return nil
+}
+
+// withLabelTagName 注册字段名的函数优先使用label标签，没有label标签时使用json标签，都没有时使用字段名
+// 已经读取label标签的函数不再修改
+func withLabelTagName(content []byte) []byte {
+	return jsonTagNameRegex.ReplaceAll(content, []byte(`${1}${2}// 优先使用label标签作为字段名，便于在错误信息中显示字段的中文名称
+${2}if label := field.Tag.Get("`+LabelTag+`"); label != "" {
+${2}	return label
+${2}}
+${2}${3}`))
+}
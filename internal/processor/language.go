@@ -0,0 +1,255 @@
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultTranslationLanguage 默认的翻译语言
+const DefaultTranslationLanguage = "zh"
+
+// translationLanguages 支持的翻译语言及自定义标签的默认翻译
+// key: 语言代码(同时也是locales和translations的包名)，value: 标签对应的翻译，空标签为未知标签的默认翻译
+var translationLanguages = map[string]map[string]string{
+	"zh": {
+		"mobile":      "{0}手机号码格式不正确",
+		"idcard":      "{0}身份证号码格式不正确",
+		"bankcard":    "{0}必须是有效的银行卡号",
+		"chinesename": "{0}必须是有效的中文姓名",
+		"vsemver":     "{0}必须是有效的语义化版本号",
+		"emails":      "{0}必须是有效的邮箱列表",
+		"runelen":     "{0}长度必须是{1}个字符",
+		"runemin":     "{0}长度必须至少为{1}个字符",
+		"runemax":     "{0}长度不能超过{1}个字符",
+		"numstr_gt":   "{0}必须大于{1}",
+		"numstr_gte":  "{0}必须大于或等于{1}",
+		"numstr_lt":   "{0}必须小于{1}",
+		"date":        "{0}日期格式不正确",
+		"time":        "{0}日期格式不正确",
+		"":            "{0}格式不符合要求",
+	},
+	"en": {
+		"mobile":      "{0} must be a valid mobile number",
+		"idcard":      "{0} must be a valid ID card number",
+		"bankcard":    "{0} must be a valid bank card number",
+		"chinesename": "{0} must be a valid Chinese name",
+		"vsemver":     "{0} must be a valid semantic version",
+		"emails":      "{0} must be a valid list of email addresses",
+		"runelen":     "{0} must be {1} characters in length",
+		"runemin":     "{0} must be at least {1} characters in length",
+		"runemax":     "{0} must be at most {1} characters in length",
+		"numstr_gt":   "{0} must be greater than {1}",
+		"numstr_gte":  "{0} must be greater than or equal to {1}",
+		"numstr_lt":   "{0} must be less than {1}",
+		"date":        "{0} must be a valid date",
+		"time":        "{0} must be a valid date",
+		"":            "{0} is invalid",
+	},
+	"ja": {
+		"mobile":      "{0}は有効な携帯電話番号でなければなりません",
+		"idcard":      "{0}は有効な身分証番号でなければなりません",
+		"bankcard":    "{0}は有効な銀行カード番号でなければなりません",
+		"chinesename": "{0}は有効な中国語の氏名でなければなりません",
+		"vsemver":     "{0}は有効なセマンティックバージョンでなければなりません",
+		"emails":      "{0}は有効なメールアドレスのリストでなければなりません",
+		"runelen":     "{0}の長さは{1}文字でなければなりません",
+		"runemin":     "{0}の長さは少なくとも{1}文字でなければなりません",
+		"runemax":     "{0}の長さは最大{1}文字でなければなりません",
+		"numstr_gt":   "{0}は{1}より大きくなければなりません",
+		"numstr_gte":  "{0}は{1}以上でなければなりません",
+		"numstr_lt":   "{0}は{1}より小さくなければなりません",
+		"date":        "{0}は有効な日付でなければなりません",
+		"time":        "{0}は有効な日付でなければなりません",
+		"":            "{0}の形式が正しくありません",
+	},
+	"ko": {
+		"mobile":      "{0}은(는) 유효한 휴대폰 번호여야 합니다",
+		"idcard":      "{0}은(는) 유효한 신분증 번호여야 합니다",
+		"bankcard":    "{0}은(는) 유효한 은행 카드 번호여야 합니다",
+		"chinesename": "{0}은(는) 유효한 중국어 이름이어야 합니다",
+		"vsemver":     "{0}은(는) 유효한 시맨틱 버전이어야 합니다",
+		"emails":      "{0}은(는) 유효한 이메일 목록이어야 합니다",
+		"runelen":     "{0}의 길이는 {1}자여야 합니다",
+		"runemin":     "{0}의 길이는 최소 {1}자여야 합니다",
+		"runemax":     "{0}의 길이는 최대 {1}자여야 합니다",
+		"numstr_gt":   "{0}은(는) {1}보다 커야 합니다",
+		"numstr_gte":  "{0}은(는) {1} 이상이어야 합니다",
+		"numstr_lt":   "{0}은(는) {1}보다 작아야 합니다",
+		"date":        "{0}은(는) 유효한 날짜여야 합니다",
+		"time":        "{0}은(는) 유효한 날짜여야 합니다",
+		"":            "{0}의 형식이 올바르지 않습니다",
+	},
+}
+
+// enumTranslationMessages 枚举类自定义标签的默认翻译，{1}为标签参数中的可选值，与validator中oneof的翻译一致
+var enumTranslationMessages = map[string]string{
+	"zh": "{0}必须是[{1}]中的一个",
+	"en": "{0} must be one of [{1}]",
+	"ja": "{0}は[{1}]のうちのいずれかでなければなりません",
+	"ko": "{0}은(는) [{1}] 중 하나여야 합니다",
+}
+
+// resolveLanguage 获取翻译语言，未设置时使用默认语言，不支持的语言返回错误
+func resolveLanguage(lang string) (string, error) {
+	if lang == "" {
+		return DefaultTranslationLanguage, nil
+	}
+	if _, ok := translationLanguages[lang]; !ok {
+		var supported []string
+		for l := range translationLanguages {
+			supported = append(supported, l)
+		}
+		sort.Strings(supported)
+		return "", fmt.Errorf("不支持的翻译语言: %s，可选值: %s", lang, strings.Join(supported, ", "))
+	}
+	return lang, nil
+}
+
+// resolveLanguages 获取所有翻译语言，第一个语言为默认语言
+func resolveLanguages(options Options) ([]string, error) {
+	if len(options.TranslationLanguages) == 0 {
+		lang, err := resolveLanguage(options.TranslationLanguage)
+		if err != nil {
+			return nil, err
+		}
+		return []string{lang}, nil
+	}
+
+	var langs []string
+	seen := make(map[string]bool)
+	for _, l := range options.TranslationLanguages {
+		lang, err := resolveLanguage(strings.TrimSpace(l))
+		if err != nil {
+			return nil, err
+		}
+		if !seen[lang] {
+			seen[lang] = true
+			langs = append(langs, lang)
+		}
+	}
+	return langs, nil
+}
+
+// translatorImports 生成翻译器文件的导入
+func translatorImports(langs []string) string {
+	var imports strings.Builder
+	imports.WriteString("\n")
+	// 单语言时英文作为后备语言
+	if len(langs) == 1 && langs[0] != "en" {
+		imports.WriteString("\t\"github.com/go-playground/locales/en\"\n")
+	}
+	for _, lang := range langs {
+		imports.WriteString(fmt.Sprintf("\t\"github.com/go-playground/locales/%s\"\n", lang))
+	}
+	imports.WriteString("\tut \"github.com/go-playground/universal-translator\"\n")
+	for _, lang := range langs {
+		imports.WriteString(fmt.Sprintf("\t%sTrans \"github.com/go-playground/validator/v10/translations/%s\"\n", lang, lang))
+	}
+	return imports.String()
+}
+
+// translatorLocales 生成创建通用翻译器的代码
+// 单语言时英文作为后备语言，多语言时第一个语言作为后备语言
+func translatorLocales(langs []string) string {
+	if len(langs) == 1 {
+		if langs[0] == "en" {
+			return "\tenLocale := en.New()\n\tuni = ut.New(enLocale, enLocale)\n"
+		}
+		return fmt.Sprintf("\tenLocale := en.New()\n\t%[1]sLocale := %[1]s.New()\n\tuni = ut.New(enLocale, %[1]sLocale)\n", langs[0])
+	}
+
+	var code strings.Builder
+	var locales []string
+	for _, lang := range langs {
+		code.WriteString(fmt.Sprintf("\t%[1]sLocale := %[1]s.New()\n", lang))
+		locales = append(locales, lang+"Locale")
+	}
+	code.WriteString(fmt.Sprintf("\tuni = ut.New(%s, %s)\n", locales[0], strings.Join(locales, ", ")))
+	return code.String()
+}
+
+// multiTranslatorInit 生成多语言翻译器的初始化函数
+// 每个语言都注册默认翻译和自定义翻译，非默认语言覆盖为本语言的自定义翻译文本
+func multiTranslatorInit(langs []string, validations []builtInValidation, customTags []string, enumTags map[string]bool) string {
+	var code strings.Builder
+	code.WriteString("// 初始化翻译器\n")
+	code.WriteString("func init() {\n")
+	code.WriteString("\t// 初始化翻译器，第一个语言作为默认语言\n")
+	code.WriteString(translatorLocales(langs))
+	code.WriteString("\n")
+	code.WriteString(TranslatorTagNameFunc)
+	for i, lang := range langs {
+		code.WriteString(fmt.Sprintf("\n\t// 注册%s翻译\n", lang))
+		code.WriteString(fmt.Sprintf("\t%sTranslator, _ := uni.GetTranslator(\"%s\")\n", lang, lang))
+		code.WriteString(fmt.Sprintf("\t_ = %sTrans.RegisterDefaultTranslations(validate, %sTranslator)\n", lang, lang))
+		code.WriteString(fmt.Sprintf("\tregisterCustomTranslations(validate, %sTranslator)\n", lang))
+		if i > 0 {
+			code.WriteString(localizedTranslations(lang, validations, customTags, enumTags, "\t"))
+		}
+	}
+	code.WriteString(fmt.Sprintf("\n\ttrans, _ = uni.GetTranslator(\"%s\")\n", langs[0]))
+	code.WriteString("}\n")
+	return code.String()
+}
+
+// localizedTranslations 生成将自定义翻译覆盖为指定语言文本的代码
+// 插件内置及配置文件定义的验证方法使用该语言的翻译，配置文件中按语言定义的翻译优先
+func localizedTranslations(lang string, validations []builtInValidation, customTags []string, enumTags map[string]bool, indent string) string {
+	var code strings.Builder
+	for _, v := range validations {
+		code.WriteString(fmt.Sprintf("%s_ = %sTranslator.Add(\"%s\", %q, true)\n", indent, lang, v.Tag, validationMessage(v, lang)))
+	}
+	for _, tag := range customTags {
+		code.WriteString(fmt.Sprintf("%s_ = %sTranslator.Add(\"%s\", %q, true)\n", indent, lang, tag, customTagMessage(lang, tag, enumTags)))
+	}
+	return code.String()
+}
+
+// translatorStructSetup 生成为结构体专属验证器注册字段名和翻译的代码
+func translatorStructSetup(langs []string, validations []builtInValidation, customTags []string, enumTags map[string]bool) string {
+	var code strings.Builder
+	code.WriteString("\n\t// 结构体专属的验证器同样注册字段名和翻译\n")
+	code.WriteString("\tvalidatorSetups = append(validatorSetups, func(v *validator.Validate) {\n")
+	code.WriteString(strings.ReplaceAll(TranslatorTagNameFunc, "validate.RegisterTagNameFunc", "v.RegisterTagNameFunc"))
+	if len(langs) == 1 {
+		code.WriteString(fmt.Sprintf("\t\t_ = %sTrans.RegisterDefaultTranslations(v, trans)\n", langs[0]))
+		code.WriteString("\t\tregisterCustomTranslations(v, trans)\n")
+	} else {
+		for i, lang := range langs {
+			code.WriteString(fmt.Sprintf("\t\t%sTranslator, _ := uni.GetTranslator(\"%s\")\n", lang, lang))
+			code.WriteString(fmt.Sprintf("\t\t_ = %sTrans.RegisterDefaultTranslations(v, %sTranslator)\n", lang, lang))
+			code.WriteString(fmt.Sprintf("\t\tregisterCustomTranslations(v, %sTranslator)\n", lang))
+			// 重新注册自定义翻译会覆盖为默认语言的文本，需要恢复为本语言的文本
+			if i > 0 {
+				code.WriteString(localizedTranslations(lang, validations, customTags, enumTags, "\t\t"))
+			}
+		}
+	}
+	code.WriteString("\t})\n")
+	return code.String()
+}
+
+// translationMessage 获取标签在指定语言下的默认翻译
+// 该语言没有提供标签的翻译时使用英文翻译，都没有时使用该语言未知标签的默认翻译
+func translationMessage(lang, tag string) string {
+	messages := translationLanguages[lang]
+	if msg, ok := messages[tag]; ok {
+		return msg
+	}
+	if msg, ok := translationLanguages["en"][tag]; ok && tag != "" {
+		return msg
+	}
+	return messages[""]
+}
+
+// customTagMessage 获取自定义标签在指定语言下的默认翻译
+// 枚举类标签（参数为空格分隔的多个值）没有专门的翻译时使用列出可选值的翻译
+func customTagMessage(lang, tag string, enumTags map[string]bool) string {
+	_, ok := translationLanguages[lang][tag]
+	_, enOK := translationLanguages["en"][tag]
+	if !enumTags[tag] || ok || enOK {
+		return translationMessage(lang, tag)
+	}
+	return enumTranslationMessages[lang]
+}
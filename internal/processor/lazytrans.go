@@ -0,0 +1,63 @@
+package processor
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// LazyTranslatorFuncs 延迟初始化翻译器时包初始化只注册字段名，翻译器在第一次使用时创建
+	// %s 为注册字段名的代码
+	LazyTranslatorFuncs = `// 使用json标签作为字段名，验证错误中的字段名在验证时确定，需要在包初始化时注册
+func init() {
+%s}
+
+// translator 获取翻译器，第一次调用时初始化，并发调用是安全的
+func translator() ut.Translator {
+	translatorOnce.Do(initTranslator)
+	return trans
+}
+
+`
+
+	// lazyTranslatorInitHeader 延迟初始化时翻译器初始化函数的声明
+	lazyTranslatorInitHeader = "// initTranslator 初始化翻译器，第一次翻译验证错误时通过translator调用\nfunc initTranslator() {"
+)
+
+// lazyTranslatorOptions 检查延迟初始化翻译器时的选项
+// 共享翻译器包及独立验证包的翻译器通过Register注册到各验证器，结构体专属验证器创建时需要已初始化的翻译器，都不支持延迟初始化
+func lazyTranslatorOptions(options Options) error {
+	if !options.LazyTranslator {
+		return nil
+	}
+	if !options.EnableTranslator {
+		return fmt.Errorf("--lazy-translator需要同时启用--translator")
+	}
+	conflicts := []struct {
+		enabled bool
+		flag    string
+	}{
+		{options.SharedTranslatorPackage != "", "--shared-translator"},
+		{options.ValidatorPackage != "", "--validator-package"},
+		{options.PerStructValidator, "--per-struct"},
+	}
+	for _, c := range conflicts {
+		if c.enabled {
+			return fmt.Errorf("--lazy-translator不能与%s同时使用", c.flag)
+		}
+	}
+	return nil
+}
+
+// lazyTranslatorInit 将翻译器的init函数改为第一次使用时调用的initTranslator，字段名仍在包初始化时注册
+func lazyTranslatorInit(initFunc string) string {
+	initFunc = strings.Replace(initFunc, TranslatorTagNameFunc, "", 1)
+	initFunc = strings.Replace(initFunc, "// 初始化翻译器\nfunc init() {", lazyTranslatorInitHeader, 1)
+	return fmt.Sprintf(LazyTranslatorFuncs, TranslatorTagNameFunc) + initFunc
+}
+
+// lazyTranslateWith 按语言翻译前初始化翻译器
+func lazyTranslateWith(translateWith string) string {
+	return strings.Replace(translateWith, "\tt, found := uni.GetTranslator(locale)\n",
+		"\ttranslatorOnce.Do(initTranslator)\n\tt, found := uni.GetTranslator(locale)\n", 1)
+}
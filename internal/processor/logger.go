@@ -0,0 +1,54 @@
+package processor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Logger 输出生成过程中的日志，调试日志只在启用调试模式时输出
+type Logger interface {
+	// Debugf 输出调试日志
+	Debugf(format string, args ...any)
+	// Warnf 输出警告日志
+	Warnf(format string, args ...any)
+}
+
+// writerLogger 按级别前缀将日志写入io.Writer，默认写入标准错误，不与标准输出中的差异及统计混在一起
+type writerLogger struct {
+	w io.Writer
+}
+
+// NewWriterLogger 创建将日志写入w的Logger，每条日志带有级别前缀
+func NewWriterLogger(w io.Writer) Logger {
+	return writerLogger{w: w}
+}
+
+func (l writerLogger) Debugf(format string, args ...any) {
+	l.logf("DEBUG", format, args...)
+}
+
+func (l writerLogger) Warnf(format string, args ...any) {
+	l.logf("WARN", format, args...)
+}
+
+func (l writerLogger) logf(level, format string, args ...any) {
+	msg := strings.TrimSuffix(fmt.Sprintf(format, args...), "\n")
+	fmt.Fprintf(l.w, "[%s] %s\n", level, msg)
+}
+
+// Log 获取选项中的Logger，未设置时写入标准错误
+func (o Options) Log() Logger {
+	if o.Logger != nil {
+		return o.Logger
+	}
+	return NewWriterLogger(os.Stderr)
+}
+
+// debugf 启用调试模式时输出调试日志
+func (o Options) debugf(format string, args ...any) {
+	if o.DebugMode {
+		o.Log().Debugf(format, args...)
+	}
+}
@@ -0,0 +1,46 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+// captureLogger 记录调试及警告日志
+type captureLogger struct {
+	debugs   []string
+	warnings []string
+}
+
+func (l *captureLogger) Debugf(format string, args ...any) {
+	l.debugs = append(l.debugs, fmt.Sprintf(format, args...))
+}
+
+func (l *captureLogger) Warnf(format string, args ...any) {
+	l.warnings = append(l.warnings, fmt.Sprintf(format, args...))
+}
+
+func TestDebugLogs(t *testing.T) {
+	for _, debug := range []bool{false, true} {
+		dir := t.TempDir()
+		file := writeTypesFile(t, dir, "types.go", mobileTypesSrc)
+		logger := &captureLogger{}
+		if err := processFiles(t, Options{DebugMode: debug, Logger: logger}, file); err != nil {
+			t.Fatal(err)
+		}
+		// 调试日志只在调试模式中输出到注入的Logger
+		if got := len(logger.debugs) > 0; got != debug {
+			t.Errorf("DebugMode = %v: debug logs = %q", debug, logger.debugs)
+		}
+	}
+}
+
+func TestWriterLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewWriterLogger(&buf)
+	logger.Debugf("处理文件: %s\n", "types.go")
+	logger.Warnf("未找到%s", "types")
+	if want := "[DEBUG] 处理文件: types.go\n[WARN] 未找到types\n"; buf.String() != want {
+		t.Errorf("NewWriterLogger() wrote %q, want %q", buf.String(), want)
+	}
+}
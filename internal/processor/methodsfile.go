@@ -0,0 +1,100 @@
+package processor
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+)
+
+// processMethodsFile 处理types文件，Validate等方法写入同一目录中单独的验证方法文件，types文件保持不变
+// 验证方法文件每次根据types文件完整重新生成，goctl重新生成types文件时不会覆盖这些方法
+func processMethodsFile(genFlag bool, filePath string, src []byte, options Options, summary *Summary) (bool, error) {
+	methodsPath, err := methodsFilePath(filePath, options)
+	if err != nil {
+		return false, err
+	}
+	existing, err := readExistingFile(methodsPath)
+	if err != nil {
+		return false, fmt.Errorf("读取现有验证方法文件失败: %w", err)
+	}
+	srcDecls, err := declNames(filePath, src)
+	if err != nil {
+		return false, err
+	}
+
+	in := generateInput{FilePath: methodsPath, Src: src, GenFlag: genFlag}
+	gen := func(in generateInput, options Options) (*GenerateResult, error) {
+		result, err := generate(in, options)
+		if err != nil || result.TypesFile == nil {
+			return result, err
+		}
+		// 去掉types文件中原有的声明，只保留生成的验证器变量和方法
+		result.TypesFile, err = stripDecls(in.FilePath, result.TypesFile, func(decl ast.Decl) bool {
+			return srcDecls[declName(decl)]
+		})
+		return result, err
+	}
+	return processTypes(in, methodsPath, existing, options, summary, gen)
+}
+
+// methodsFilePath 获取types文件对应的验证方法文件路径，types.go对应validation_methods.go，其他文件如user.go对应user_validation_methods.go
+// 文件名按命名风格格式化，types.go所在目录中已有按默认文件名生成的文件时继续使用该文件
+func methodsFilePath(typesPath string, options Options) (string, error) {
+	dir, name := filepath.Split(typesPath)
+	base := strings.TrimSuffix(name, ".go")
+	if base == "types" {
+		return apiMethodsFilePath(dir, options)
+	}
+	fileName, err := styledFileName(base+"_"+APIMethodsFileName, options)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, fileName), nil
+}
+
+// declNames 获取文件中除导入外的顶层声明的名称，用于从生成的文件中去掉原有的声明
+func declNames(filePath string, src []byte) (map[string]bool, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), filePath, src, 0)
+	if err != nil {
+		return nil, fmt.Errorf("解析文件失败: %w", err)
+	}
+	names := make(map[string]bool)
+	for _, decl := range f.Decls {
+		if name := declName(decl); name != "" {
+			names[name] = true
+		}
+	}
+	return names, nil
+}
+
+// declName 获取顶层声明的名称，方法带有接收者类型（如*Req.Validate），声明多个名称时以逗号连接，导入声明返回空字符串
+func declName(decl ast.Decl) string {
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil || len(d.Recv.List) == 0 {
+			return d.Name.Name
+		}
+		return types.ExprString(d.Recv.List[0].Type) + "." + d.Name.Name
+	case *ast.GenDecl:
+		if d.Tok == token.IMPORT {
+			return ""
+		}
+		var names []string
+		for _, spec := range d.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				names = append(names, s.Name.Name)
+			case *ast.ValueSpec:
+				for _, name := range s.Names {
+					names = append(names, name.Name)
+				}
+			}
+		}
+		return d.Tok.String() + " " + strings.Join(names, ",")
+	}
+	return ""
+}
@@ -0,0 +1,178 @@
+package processor
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/zeromicro/go-zero/tools/goctl/util/format"
+)
+
+// DefaultReceiverName 生成的方法默认的接收者名称
+const DefaultReceiverName = "r"
+
+// DefaultIncludeSuffixes 未包含验证标签时也生成Validate方法的结构体名称后缀
+var DefaultIncludeSuffixes = []string{"Req"}
+
+// reservedReceiverNames 生成的方法中使用的标识符，不能作为接收者名称
+// 从各个方法模板生成的代码中收集，新增模板或修改模板中的局部变量时无需手动维护
+var reservedReceiverNames = methodIdentifiers()
+
+// methodIdentifiers 收集所有方法模板生成的方法体及参数中使用的标识符，按字母顺序排列
+// 模板参数使用各种选项下的验证器及翻译表达式，字段和方法选择器（如err.Field）及结构体字面量的字段名不会与接收者冲突，不收集
+func methodIdentifiers() []string {
+	const structName, receiver, pkg = "T", "receiver", "pkg"
+	var methods []string
+	for _, validatorExpr := range []string{"validate", structValidatorCall(structName, nil)} {
+		for _, translateExpr := range []string{"err.Translate(trans)", "translateField(err, trans)", "translateField(err, translator())"} {
+			methods = append(methods,
+				fmt.Sprintf(ValidateMethod, structName, validatorExpr, translateExpr, receiver),
+				fmt.Sprintf(ValidateJSONMethod, structName, validatorExpr, translateExpr, receiver),
+				fmt.Sprintf(ValidateFieldsMethod, structName, validatorExpr, translateExpr, receiver),
+			)
+		}
+		methods = append(methods,
+			fmt.Sprintf(ErrorCodeValidateMethod, structName, validatorExpr, receiver),
+			fmt.Sprintf(ErrorHandlerValidateMethod, structName, validatorExpr, receiver),
+		)
+	}
+	methods = append(methods,
+		fmt.Sprintf(MustValidateMethod, structName, receiver),
+		fmt.Sprintf(ValidatorPackageValidateMethod, structName, receiver, pkg),
+		fmt.Sprintf(ValidatorPackageValidateJSONMethod, structName, receiver, pkg),
+		fmt.Sprintf(ValidatorPackageValidateFieldsMethod, structName, receiver, pkg),
+	)
+	// 启用ValidateCtx时根据Validate方法生成的方法
+	for _, method := range slices.Clone(methods) {
+		if ctxMethod := contextMethod(method, receiver); ctxMethod != method {
+			methods = append(methods, ctxMethod)
+		}
+	}
+
+	f, err := parser.ParseFile(token.NewFileSet(), "", "package p\n"+strings.Join(methods, "\n"), 0)
+	if err != nil {
+		panic(fmt.Sprintf("解析方法模板失败: %v", err))
+	}
+	idents := make(map[string]bool)
+	var collect func(n ast.Node) bool
+	collect = func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			ast.Inspect(n.X, collect)
+			return false
+		case *ast.KeyValueExpr:
+			ast.Inspect(n.Value, collect)
+			return false
+		case *ast.Ident:
+			idents[n.Name] = true
+		}
+		return true
+	}
+	for _, decl := range f.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok {
+			ast.Inspect(funcDecl.Type.Params, collect)
+			ast.Inspect(funcDecl.Body, collect)
+		}
+	}
+	for _, name := range []string{structName, receiver, pkg} {
+		delete(idents, name)
+	}
+	return slices.Sorted(maps.Keys(idents))
+}
+
+// receiverName 获取生成的方法的接收者名称，未设置时使用默认名称
+func receiverName(options Options) (string, error) {
+	name := cmp.Or(options.ReceiverName, DefaultReceiverName)
+	if !token.IsIdentifier(name) || name == "_" {
+		return "", fmt.Errorf("接收者名称不是合法的Go标识符: %s", name)
+	}
+	if slices.Contains(reservedReceiverNames, name) {
+		return "", fmt.Errorf("接收者名称与生成代码中使用的标识符冲突: %s", name)
+	}
+	return name, nil
+}
+
+// valueReceiverMethods 将生成的方法的指针接收者替换为值接收者，值接收者每次调用都会复制结构体
+func valueReceiverMethods(code, structName, receiver string) string {
+	return strings.ReplaceAll(code, fmt.Sprintf("func (%s *%s) ", receiver, structName), fmt.Sprintf("func (%s %s) ", receiver, structName))
+}
+
+// hasIncludeSuffix 判断结构体名称是否以需要生成Validate方法的后缀结尾，未设置时使用默认后缀
+func hasIncludeSuffix(name string, options Options) bool {
+	suffixes := options.IncludeSuffixes
+	if suffixes == nil {
+		suffixes = DefaultIncludeSuffixes
+	}
+	return slices.ContainsFunc(suffixes, func(suffix string) bool {
+		return suffix != "" && strings.HasSuffix(name, suffix)
+	})
+}
+
+// exportName 将验证标签转换为导出的标识符，去掉非标识符字符并将每一段首字母大写
+// 例如: new_tag1 -> NewTag1, age-range -> AgeRange, uuid4 -> Uuid4
+func exportName(tag string) string {
+	segments := strings.FieldsFunc(tag, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var name strings.Builder
+	for _, segment := range segments {
+		runes := []rune(segment)
+		runes[0] = unicode.ToUpper(runes[0])
+		name.WriteString(string(runes))
+	}
+	return name.String()
+}
+
+// validationFuncName 获取验证标签对应的验证函数名
+func validationFuncName(tag string) string {
+	return "validate" + exportName(tag)
+}
+
+// hasValidationFunc 判断验证标签对应的验证函数是否已声明
+// 兼容旧版本仅将首字母大写生成的函数名，如validateNew_tag1
+func hasValidationFunc(funcs map[string]bool, tag string) bool {
+	if funcs[validationFuncName(tag)] {
+		return true
+	}
+	return tag != "" && funcs[legacyValidationFuncName(tag)]
+}
+
+// registeredFuncDeclared 判断registerValidation映射中为标签注册的验证函数是否已在包中声明
+// 手动注册到其他函数名的标签（如"custom_validation": customValidation）视为已有验证函数，不再生成validate<Tag>
+func registeredFuncDeclared(fn string, funcs ...map[string]bool) bool {
+	if fn == "" {
+		return false
+	}
+	for _, declared := range funcs {
+		if declared[fn] {
+			return true
+		}
+	}
+	return false
+}
+
+// legacyValidationFuncName 获取旧版本生成的验证函数名，只将标签首字母大写，如new_tag1对应validateNew_tag1
+func legacyValidationFuncName(tag string) string {
+	return "validate" + strings.ToUpper(tag[:1]) + tag[1:]
+}
+
+// styledFileName 按goctl的命名风格（如go_zero、goZero）格式化生成的文件名，name为下划线分隔的默认文件名
+// 未设置命名风格或文件名只有一个单词时保持不变
+func styledFileName(name string, options Options) (string, error) {
+	base := strings.TrimSuffix(name, ".go")
+	if options.NamingStyle == "" || !strings.Contains(base, "_") {
+		return name, nil
+	}
+	styled, err := format.FileNamingFormat(options.NamingStyle, base)
+	if err != nil {
+		return "", fmt.Errorf("不支持的命名风格%s: %w", options.NamingStyle, err)
+	}
+	return styled + ".go", nil
+}
@@ -0,0 +1,112 @@
+package processor
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestReceiverName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", DefaultReceiverName, false},
+		{"r", "r", false},
+		{"req", "req", false},
+		{"user", "user", false},
+		{"_", "", true},
+		{"1r", "", true},
+		// 生成的方法中使用的局部变量及标识符
+		{"err", "", true},
+		{"errs", "", true},
+		{"ok", "", true},
+		{"fields", "", true},
+		{"ctx", "", true},
+		{"trans", "", true},
+		{"translator", "", true},
+		{"validate", "", true},
+		{"FieldError", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := receiverName(Options{ReceiverName: tt.name})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("receiverName(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("receiverName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReservedReceiverNamesExcludeSelectors(t *testing.T) {
+	// 字段和方法选择器及结构体字面量的字段名不会与接收者冲突
+	for _, name := range []string{"Struct", "Field", "Translate", "Namespace", "Message", "Path", "Tag"} {
+		if _, err := receiverName(Options{ReceiverName: name}); err != nil {
+			t.Errorf("receiverName(%q) error = %v, want nil", name, err)
+		}
+	}
+}
+
+func TestGeneratedMethodsWithReceiver(t *testing.T) {
+	src := "package types\n\n" +
+		"type CreateUserReq struct {\n" +
+		"\tName string `json:\"name\" validate:\"required\"`\n" +
+		"}\n"
+	tests := []struct {
+		name     string
+		receiver string
+		want     string
+	}{
+		{"default", "", DefaultReceiverName},
+		{"override", "req", "req"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := Options{
+				ReceiverName:          tt.receiver,
+				EnableTranslator:      true,
+				GenerateContextMethod: true,
+				GenerateMust:          true,
+				GenerateJSONMethod:    true,
+				GenerateFieldsMethod:  true,
+			}
+			result, err := Generate([]byte(src), "", options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := parser.ParseFile(token.NewFileSet(), "types.go", result.TypesFile, 0); err != nil {
+				t.Fatalf("generated types file does not parse: %v", err)
+			}
+			for _, method := range []string{"Validate()", "ValidateCtx(ctx context.Context)", "MustValidate()", "ValidateJSON()", "ValidateFields()"} {
+				if want := "func (" + tt.want + " *CreateUserReq) " + method; !strings.Contains(string(result.TypesFile), want) {
+					t.Errorf("generated types file does not declare %s:\n%s", want, result.TypesFile)
+				}
+			}
+		})
+	}
+}
+
+func TestExportName(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"new_tag1", "NewTag1"},
+		{"age-range", "AgeRange"},
+		{"ageRange", "AgeRange"},
+		{"uuid4", "Uuid4"},
+	}
+	for _, tt := range tests {
+		if got := exportName(tt.tag); got != tt.want {
+			t.Errorf("exportName(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+		if got := validationFuncName(tt.tag); got != "validate"+tt.want {
+			t.Errorf("validationFuncName(%q) = %q, want %q", tt.tag, got, "validate"+tt.want)
+		}
+	}
+}
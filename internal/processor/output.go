@@ -0,0 +1,67 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// outputRoot 获取启用输出目录时映射文件路径的基准目录，即types文件所在的模块根目录，未设置输出目录时返回空字符串
+func outputRoot(dirPath string, options Options) (string, error) {
+	if options.OutputDir == "" {
+		return "", nil
+	}
+	moduleRoot, _, err := findModule(dirPath)
+	if err != nil {
+		return "", fmt.Errorf("--output-dir需要在Go模块中执行: %w", err)
+	}
+	return moduleRoot, nil
+}
+
+// outputPath 获取文件在输出目录中的路径，保持文件相对于模块根目录的目录结构，root为空时返回原路径
+func outputPath(root, filePath string, options Options) (string, error) {
+	if root == "" {
+		return filePath, nil
+	}
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, absPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s 不在模块目录 %s 中，无法写入输出目录", filePath, root)
+	}
+	return filepath.Join(options.OutputDir, rel), nil
+}
+
+// readOutputFile 读取文件在输出目录中已生成的内容，输出目录中不存在时读取原文件，都不存在时返回nil
+// 同一包中的多个types文件依次合并到输出目录中的生成文件，重复执行时与上次生成的结果比较
+func readOutputFile(root, filePath string, options Options) ([]byte, error) {
+	if root == "" {
+		return readExistingFile(filePath)
+	}
+	outPath, err := outputPath(root, filePath, options)
+	if err != nil {
+		return nil, err
+	}
+	content, err := readExistingFile(outPath)
+	if content != nil || err != nil {
+		return content, err
+	}
+	return readExistingFile(filePath)
+}
+
+// outputFileExists 判断文件在输出目录或原目录中是否已存在
+func outputFileExists(root, filePath string, options Options) (bool, error) {
+	outPath, err := outputPath(root, filePath, options)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range []string{outPath, filePath} {
+		if _, err := os.Stat(p); err == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,60 @@
+package processor
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+)
+
+// registeredTags 获取结构体字段中需要通过registerValidation注册的验证标签
+// knownTags为插件内置及配置文件定义的验证标签，验证规则别名按其代表的验证器注册
+func registeredTags(structType *ast.StructType, options Options, knownTags map[string]bool, aliases map[string]string) map[string]bool {
+	tags := make(map[string]bool)
+	for _, field := range structFields(structType) {
+		if field.Tag == nil {
+			continue
+		}
+		for _, v := range expandRuleAliases(splitValidateTag(extractValidateTag(field.Tag.Value)), aliases) {
+			v, _, _ = parseValidator(v)
+			if _, ok := aliases[v]; ok {
+				continue
+			}
+			// 插件内置的验证方法始终注册，自定义验证方法仅在启用时注册
+			if knownTags[v] || (options.EnableCustomValidation && !isBuiltInValidator(v)) {
+				tags[v] = true
+			}
+		}
+	}
+	return tags
+}
+
+// collectStructTags 汇总结构体及其引用的同文件结构体使用的注册验证标签，按字母顺序返回
+func collectStructTags(name string, structTags map[string]map[string]bool, structRefs map[string][]string) []string {
+	tags := make(map[string]bool)
+	visited := make(map[string]bool)
+	var visit func(string)
+	visit = func(n string) {
+		if visited[n] {
+			return
+		}
+		visited[n] = true
+		for tag := range structTags[n] {
+			tags[tag] = true
+		}
+		for _, ref := range structRefs[n] {
+			visit(ref)
+		}
+	}
+	visit(name)
+
+	return sortedTags(tags)
+}
+
+// structValidatorCall 生成获取结构体专属验证器的调用代码
+func structValidatorCall(name string, tags []string) string {
+	args := []string{fmt.Sprintf("%q", name)}
+	for _, tag := range tags {
+		args = append(args, fmt.Sprintf("%q", tag))
+	}
+	return fmt.Sprintf("structValidator(%s)", strings.Join(args, ", "))
+}
@@ -2,20 +2,21 @@ package processor
 
 import (
 	"bytes"
+	"cmp"
 	"fmt"
 	"go/ast"
-	"go/format"
 	"go/parser"
 	"go/token"
+	"io"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
-	"reflect"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
-
-	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
-	"github.com/zeromicro/go-zero/tools/goctl/plugin"
 )
 
 // Options 定义处理器的选项
@@ -24,15 +25,168 @@ type Options struct {
 	EnableCustomValidation bool
 	// 是否启用调试模式
 	DebugMode bool
+	// 输出调试及警告日志，为nil时写入标准错误
+	Logger Logger
+	// 写入前处理生成的文件内容，如使用goimports整理导入或添加文件头，为nil时不处理
+	PostProcess func(path string, content []byte) ([]byte, error)
 	// 是否启用翻译器功能
 	EnableTranslator bool
+	// 翻译器是否在第一次翻译验证错误时才初始化，而不是在包初始化时创建
+	LazyTranslator bool
+	// 错误信息中的字段名是否优先使用label标签，没有label标签时使用json标签，需要启用翻译器
+	UseLabelTag bool
+	// 是否生成同时使用多语言翻译中前两种语言的TranslateBilingual函数，需要启用翻译器并指定至少两种语言
+	Bilingual bool
+	// 翻译语言，为空时使用默认语言(zh)
+	TranslationLanguage string
+	// 多语言翻译，设置后覆盖TranslationLanguage，第一个语言为默认语言
+	TranslationLanguages []string
+	// 是否为每个结构体使用独立的验证器，只注册该结构体用到的验证方法
+	PerStructValidator bool
+	// types文件所在目录，为空时使用默认目录(internal/types/)
+	TypesDir string
+	// 额外的types文件目录列表，与TypesDir一起参与匹配
+	TypesDirs []string
+	// 没有验证标签时也生成Validate方法的结构体名称后缀，为nil时使用默认后缀(Req)
+	IncludeSuffixes []string
+	// 是否生成带错误码的聚合验证错误，Validate返回*ValidationErrors
+	GenerateErrorCodes bool
+	// 是否生成将验证错误转换为HTTP 400响应的ValidationErrorHandler
+	GenerateErrorHandler bool
+	// 是否根据字段的oneof标签生成枚举常量文件enums.go，常量名为<字段名><值>
+	GenerateEnums bool
+	// 是否只打印将要修改的内容的差异，而不写入文件
+	DryRun bool
+	// 是否只检查生成的代码是否为最新，不写入文件，需要修改的文件打印差异并记录到Summary.FilesOutdated
+	CheckOnly bool
+	// 自定义验证标签的验证方法仍为生成的空方法（始终返回true）时是否返回错误，调试模式下只输出警告
+	Strict bool
+	// 自定义验证器配置文件路径(YAML/JSON)，配置的验证器根据正则表达式生成验证方法和翻译
+	ConfigPath string
+	// 插件内置及配置文件定义的验证函数是否在验证前去掉字段值首尾的空白，不影响validator内置的验证标签
+	TrimSpace bool
+	// 是否同时生成使用context的ValidateCtx方法
+	GenerateContextMethod bool
+	// 验证方法文件名，为空时使用默认文件名(validation.go)
+	ValidationFileName string
+	// 翻译器文件名，为空时使用默认文件名(translator.go)
+	TranslatorFileName string
+	// 生成的Validate等方法的接收者名称，为空时使用默认名称(r)
+	ReceiverName string
+	// 是否同时生成验证失败时panic的MustValidate方法
+	GenerateMust bool
+	// 自定义验证方法是否通过RegisterValidationCtx注册，验证方法可以读取ValidateCtx传入的上下文并记录验证失败的错误信息
+	CustomValidationCtx bool
+	// 是否同时生成返回各字段json名称到翻译后错误信息映射的ValidateJSON方法，需要启用翻译器
+	GenerateJSONMethod bool
+	// 是否同时生成返回所有字段验证错误及其完整路径的ValidateFields方法
+	GenerateFieldsMethod bool
+	// 共享翻译器包相对于模块根目录的路径，如internal/validatetrans，设置后翻译器只生成到该包中，各types包引用该包翻译
+	SharedTranslatorPackage string
+	// 是否不生成Validate等方法，只生成验证文件和翻译器文件，types.go保持不变
+	SkipMethodGeneration bool
+	// 是否忽略已存在的验证文件和翻译器文件，根据当前的验证标签完整重新生成
+	Force bool
+	// 是否清理验证文件和翻译器文件中已没有结构体使用的自定义标签的注册及翻译
+	Prune bool
+	// 是否使用进程内共享的验证器，生成的各包通过goctl-validate/validate包的Default()获取同一个验证器
+	SharedValidator bool
+	// 是否在写入前对生成的文件所在的包进行类型检查，检查失败时不写入文件
+	TypeCheck bool
+	// 生成的方法是否使用值接收者，值接收者每次调用都会复制结构体
+	ValueReceiver bool
+	// 是否生成验证方法的测试文件validation_test.go，已存在时只追加缺少的测试函数
+	GenerateTests bool
+	// 独立验证包相对于模块根目录的路径，如internal/validate，设置后验证文件和翻译器文件生成到该包中，types包的Validate方法调用该包验证
+	ValidatorPackage string
+	// 生成的多单词文件名的命名风格，与goctl的--style一致（如gozero、go_zero、goZero），为空时使用下划线分隔
+	NamingStyle string
+	// 是否直接根据api文件中的类型定义生成，Validate等方法写入validation_methods.go，不读取types.go
+	FromAPI bool
+	// 是否将Validate等方法写入types文件所在目录中单独的validation_methods.go，types文件保持不变
+	MethodsInSeparateFile bool
+	// 生成文件的输出目录，设置后所有生成及修改的文件（包括types.go）按相对于模块根目录的路径写入该目录，项目中的文件保持不变
+	OutputDir string
+	// 是否在验证文件中生成以结构体封装的验证器Validator及其构造函数New，便于通过依赖注入使用
+	StructStyle bool
+}
+
+// DefaultTypesDir 默认的types文件目录
+const DefaultTypesDir = "internal/types/"
+
+// 默认生成的文件名
+const (
+	DefaultValidationFileName = "validation.go"
+	DefaultTranslatorFileName = "translator.go"
+)
+
+// outputFileNames 获取验证方法文件和翻译器文件的文件名，未设置时使用默认文件名
+func outputFileNames(options Options) (validationFileName, translatorFileName string, err error) {
+	validationFileName = cmp.Or(options.ValidationFileName, DefaultValidationFileName)
+	translatorFileName = cmp.Or(options.TranslatorFileName, DefaultTranslatorFileName)
+	for _, name := range []string{validationFileName, translatorFileName} {
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == ".go" {
+			return "", "", fmt.Errorf("生成的文件名必须以.go结尾且不能是测试文件: %s", name)
+		}
+		if strings.ContainsAny(name, `/\`) {
+			return "", "", fmt.Errorf("生成的文件名不能包含路径分隔符: %s", name)
+		}
+	}
+	if validationFileName == translatorFileName {
+		return "", "", fmt.Errorf("验证方法文件与翻译器文件不能同名: %s", validationFileName)
+	}
+	for _, name := range []string{ErrorCodeFileName, ErrorHandlerFileName, EnumsFileName} {
+		if validationFileName == name || translatorFileName == name {
+			return "", "", fmt.Errorf("生成的文件名与%s冲突", name)
+		}
+	}
+	return validationFileName, translatorFileName, nil
+}
+
+// IsGeneratedFile 判断types目录中的文件是否为插件生成的文件或测试文件，查找types文件时跳过
+// 按生成的文件名及goctl-validate的生成代码标记判断，goctl生成的types.go带有goctl自己的标记，不会被跳过
+func IsGeneratedFile(filePath string, options Options) bool {
+	name := filepath.Base(filePath)
+	if strings.HasSuffix(name, "_test.go") {
+		return true
+	}
+	names := []string{ErrorCodeFileName, ErrorHandlerFileName, EnumsFileName, APIMethodsFileName}
+	if validationFileName, translatorFileName, err := outputFileNames(options); err == nil {
+		names = append(names, validationFileName, translatorFileName)
+	}
+	if methodsFileName, err := styledFileName(APIMethodsFileName, options); err == nil {
+		names = append(names, methodsFileName)
+	}
+	if slices.Contains(names, name) {
+		return true
+	}
+	// 单独的验证方法文件（如user_validation_methods.go）等其他生成的文件带有生成代码标记
+	f, err := os.Open(filePath)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	header := make([]byte, len(GeneratedHeader))
+	n, _ := io.ReadFull(f, header)
+	return string(header[:n]) == GeneratedHeader
 }
 
 // 验证器常量
 const (
+	// GeneratedHeader 新建的生成文件开头的标准生成代码标记，供工具识别生成的文件
+	// validation.go中需要实现自定义验证方法，不添加该标记
+	GeneratedHeader = "// Code generated by goctl-validate. DO NOT EDIT.\n\n"
+
 	ValidateImport = `"github.com/go-playground/validator/v10"`
 	ValidateVar    = `var validate = validator.New()`
 
+	// SharedValidatorImport 进程内共享验证器的库包导入路径
+	SharedValidatorImport = "github.com/xs-cw/goctl-validate/validate"
+	// SharedValidatorName 生成代码中共享验证器库包的导入名，避免与验证器变量validate冲突
+	SharedValidatorName = "sharedvalidate"
+	// SharedValidateVar 使用进程内共享验证器的验证器变量声明
+	SharedValidateVar = `var validate = sharedvalidate.Default()`
+
 	// 验证方法映射注释和开始部分
 	ValidationRegisterComment = `// registerValidation 存储所有的验证方法
 // key: 验证标签名称，value: 对应的验证函数`
@@ -41,21 +195,141 @@ const (
 	ValidateRegisterMap = `var registerValidation = map[string]validator.Func{
 	"mobile": validateMobile, // 手机号验证
 	"idcard": validateIdCard, // 身份证号验证
+	"bankcard": validateBankcard, // 银行卡号验证
+	"chinesename": validateChinesename, // 中文姓名验证
+	"vsemver": validateVsemver, // 语义化版本号验证（允许v前缀）
+	"emails": validateEmails, // 逗号分隔的邮箱列表验证
+	"runelen": validateRunelen, // 字符数等于参数
+	"runemin": validateRunemin, // 字符数不少于参数
+	"runemax": validateRunemax, // 字符数不超过参数
+	"numstr_gt": validateNumstrGt, // 数字字符串的值大于参数
+	"numstr_gte": validateNumstrGte, // 数字字符串的值大于或等于参数
+	"numstr_lt": validateNumstrLt, // 数字字符串的值小于参数
 `
 
 	// 自定义验证方法映射模板
 	CustomValidationMapTemplate = `	"%s": validate%s, // %s
 `
 
-	// 验证方法注册初始化
+	// 自定义验证方法映射注释，验证方法仍为生成的空方法时提示实现
+	CustomValidationMapComment     = "自定义验证: %s"
+	CustomValidationMapStubComment = "自定义验证: %s (请实现)"
+
+	// 验证方法注册初始化，通过sync.Once保证验证方法只在验证器上注册一次
 	ValidateInitFunc = `
+// validateOnce 保证验证方法只注册一次
+var validateOnce sync.Once
+
 // 初始化并注册所有验证方法
+func init() {
+	initValidate()
+}
+
+// initValidate 在验证器上注册所有验证方法，多次或并发调用时只注册一次
+func initValidate() {
+	validateOnce.Do(func() {
+		// 遍历注册所有验证方法
+		for tag, handler := range registerValidation {
+			_ = validate.RegisterValidation(tag, handler)
+		}
+	})
+}
+`
+
+	// 旧版本生成的验证方法注册初始化，更新验证文件时替换为ValidateInitFunc
+	legacyValidateInitFunc = `// 初始化并注册所有验证方法
 func init() {
 	// 遍历注册所有验证方法
 	for tag, handler := range registerValidation {
 		_ = validate.RegisterValidation(tag, handler)
 	}
 }
+`
+
+	// 结构体专属验证器
+	// 每个结构体首次验证时创建独立的验证器，只注册该结构体用到的验证方法，避免不同来源的同名标签互相覆盖
+	// 代价是每个结构体都会多一份验证器实例和缓存，且翻译需要通过validatorSetups为每个验证器重复注册
+	PerStructValidatorFunc = `
+// structValidators 按结构体名称缓存的专属验证器
+var structValidators sync.Map
+
+// validatorSetups 创建结构体专属验证器时执行的额外初始化，如注册翻译
+var validatorSetups []func(v *validator.Validate)
+
+// structValidator 获取结构体专属的验证器，首次调用时创建并只注册该结构体用到的验证方法
+func structValidator(name string, tags ...string) *validator.Validate {
+	if v, ok := structValidators.Load(name); ok {
+		return v.(*validator.Validate)
+	}
+
+	v := validator.New()
+	for _, tag := range tags {
+		if handler, ok := registerValidation[tag]; ok {
+			_ = v.RegisterValidation(tag, handler)
+		}
+	}
+	for _, setup := range validatorSetups {
+		setup(v)
+	}
+
+	actual, _ := structValidators.LoadOrStore(name, v)
+	return actual.(*validator.Validate)
+}
+` + SharedTranslatorType
+
+	// 在多个验证器上共享翻译器时使用的翻译器包装
+	SharedTranslatorType = `
+// sharedTranslator 在多个验证器上注册同一翻译器时忽略翻译文本已存在的错误
+// 结构体值可比较，同一翻译器包装后作为翻译函数的键保持一致
+type sharedTranslator struct {
+	ut.Translator
+}
+
+func (t sharedTranslator) Add(key interface{}, text string, override bool) error {
+	_ = t.Translator.Add(key, text, override)
+	return nil
+}
+
+func (t sharedTranslator) AddCardinal(key interface{}, text string, rule locales.PluralRule, override bool) error {
+	_ = t.Translator.AddCardinal(key, text, rule, override)
+	return nil
+}
+
+func (t sharedTranslator) AddOrdinal(key interface{}, text string, rule locales.PluralRule, override bool) error {
+	_ = t.Translator.AddOrdinal(key, text, rule, override)
+	return nil
+}
+
+func (t sharedTranslator) AddRange(key interface{}, text string, rule locales.PluralRule, override bool) error {
+	_ = t.Translator.AddRange(key, text, rule, override)
+	return nil
+}
+`
+
+	// ValidateMethod 返回第一个字段翻译后的验证错误的Validate方法，%[1]s 为结构体名，%[2]s 为验证器表达式，%[3]s 为翻译单个字段验证错误的表达式，%[4]s 为接收者名称
+	ValidateMethod = `
+func (%[4]s *%[1]s) Validate() error {
+    err := %[2]s.Struct(%[4]s)
+	if err != nil {
+		es, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return err
+		}
+		for _, err := range es {
+			return fmt.Errorf("%%s", %[3]s)
+		}
+	}
+	return err
+}
+`
+
+	// 验证失败时panic的MustValidate方法
+	MustValidateMethod = `
+func (%[2]s *%[1]s) MustValidate() {
+	if err := %[2]s.Validate(); err != nil {
+		panic(err)
+	}
+}
 `
 
 	// 自定义验证方法定义模板
@@ -67,8 +341,25 @@ func validate%s(fl validator.FieldLevel) bool {
 }
 `
 
-	// 内置验证方法
-	BuiltInValidationFunc = `
+	// 带参数的自定义验证方法定义模板
+	CustomParamValidationFuncTemplate = `
+// 自定义验证方法: %s
+func validate%s(fl validator.FieldLevel) bool {
+	// 验证标签的参数，如within=10中的10
+	param := fl.Param()
+	// 数值参数可以通过strconv转换（需导入strconv），例如:
+	// limit, err := strconv.Atoi(param)
+	// if err != nil {
+	// 	return false
+	// }
+	_ = param
+	// 在这里实现 %s 的验证逻辑
+	return true
+}
+`
+
+	// 内置手机号验证方法
+	MobileValidationFunc = `
 // 验证手机号
 func validateMobile(fl validator.FieldLevel) bool {
 	mobile := fl.Field().String()
@@ -76,7 +367,10 @@ func validateMobile(fl validator.FieldLevel) bool {
 	match, _ := regexp.MatchString("^1[3-9]\\d{9}$", mobile)
 	return match
 }
+`
 
+	// 内置身份证号验证方法
+	IdCardValidationFunc = `
 // 验证身份证号
 func validateIdCard(fl validator.FieldLevel) bool {
 	idCard := fl.Field().String()
@@ -86,220 +380,858 @@ func validateIdCard(fl validator.FieldLevel) bool {
 }
 `
 
-	// 翻译器相关导入
-	TranslatorImports = `
-	"github.com/go-playground/locales/en"
-	"github.com/go-playground/locales/zh"
-	ut "github.com/go-playground/universal-translator"
-	zhTrans "github.com/go-playground/validator/v10/translations/zh"
+	// 内置银行卡号验证方法
+	BankcardValidationFunc = `
+// 验证银行卡号
+func validateBankcard(fl validator.FieldLevel) bool {
+	cardNo := fl.Field().String()
+	// 银行卡号为13到19位数字
+	if match, _ := regexp.MatchString("^\\d{13,19}$", cardNo); !match {
+		return false
+	}
+	// 使用Luhn算法校验，从右向左每隔一位乘以2
+	sum := 0
+	double := false
+	for i := len(cardNo) - 1; i >= 0; i-- {
+		digit := int(cardNo[i] - '0')
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
+`
+
+	// 内置中文姓名验证方法
+	ChineseNameValidationFunc = `
+// 验证中文姓名
+func validateChinesename(fl validator.FieldLevel) bool {
+	name := fl.Field().String()
+	// 2到16个汉字，少数民族姓名可包含间隔号
+	match, _ := regexp.MatchString("^[\\p{Han}·]{2,16}$", name)
+	return match
+}
+`
+
+	// 内置语义化版本号验证方法
+	VsemverValidationFunc = `
+// 验证语义化版本号，允许v前缀
+func validateVsemver(fl validator.FieldLevel) bool {
+	version := fl.Field().String()
+	// MAJOR.MINOR.PATCH，可带-预发布版本及+构建元数据，如1.2.3、v1.2.3-rc.1
+	match, _ := regexp.MatchString("^v?(0|[1-9]\\d*)\\.(0|[1-9]\\d*)\\.(0|[1-9]\\d*)(-[0-9A-Za-z-]+(\\.[0-9A-Za-z-]+)*)?(\\+[0-9A-Za-z-]+(\\.[0-9A-Za-z-]+)*)?$", version)
+	return match
+}
+`
+
+	// 内置邮箱列表验证方法
+	EmailsValidationFunc = `
+// 验证逗号分隔的邮箱列表，列表为空或任意一项不是有效邮箱时验证失败
+func validateEmails(fl validator.FieldLevel) bool {
+	// 每一项去掉首尾空白后使用validator内置的email规则验证
+	for _, email := range strings.Split(fl.Field().String(), ",") {
+		if validate.Var(strings.TrimSpace(email), "required,email") != nil {
+			return false
+		}
+	}
+	return true
+}
+`
+
+	// 内置按字符数验证长度的方法，汉字等多字节字符计为1个字符，参数不是整数时验证失败
+	RunelenValidationFunc = `
+// 验证字符串的字符数等于参数，如runelen=6
+func validateRunelen(fl validator.FieldLevel) bool {
+	n, err := strconv.Atoi(fl.Param())
+	return err == nil && utf8.RuneCountInString(fl.Field().String()) == n
+}
+`
+
+	RuneminValidationFunc = `
+// 验证字符串的字符数不少于参数，如runemin=2
+func validateRunemin(fl validator.FieldLevel) bool {
+	n, err := strconv.Atoi(fl.Param())
+	return err == nil && utf8.RuneCountInString(fl.Field().String()) >= n
+}
+`
+
+	RunemaxValidationFunc = `
+// 验证字符串的字符数不超过参数，如runemax=20
+func validateRunemax(fl validator.FieldLevel) bool {
+	n, err := strconv.Atoi(fl.Param())
+	return err == nil && utf8.RuneCountInString(fl.Field().String()) <= n
+}
+`
+
+	// 内置按数值比较数字字符串的方法，gt等标签用于字符串时比较的是长度，字段值或参数不是数字时验证失败
+	NumstrGtValidationFunc = `
+// 验证数字字符串的值大于参数，如numstr_gt=0
+func validateNumstrGt(fl validator.FieldLevel) bool {
+	n, err := strconv.ParseFloat(fl.Field().String(), 64)
+	param, paramErr := strconv.ParseFloat(fl.Param(), 64)
+	return err == nil && paramErr == nil && n > param
+}
+`
+
+	NumstrGteValidationFunc = `
+// 验证数字字符串的值大于或等于参数，如numstr_gte=0.01
+func validateNumstrGte(fl validator.FieldLevel) bool {
+	n, err := strconv.ParseFloat(fl.Field().String(), 64)
+	param, paramErr := strconv.ParseFloat(fl.Param(), 64)
+	return err == nil && paramErr == nil && n >= param
+}
+`
+
+	NumstrLtValidationFunc = `
+// 验证数字字符串的值小于参数，如numstr_lt=10000
+func validateNumstrLt(fl validator.FieldLevel) bool {
+	n, err := strconv.ParseFloat(fl.Field().String(), 64)
+	param, paramErr := strconv.ParseFloat(fl.Param(), 64)
+	return err == nil && paramErr == nil && n < param
+}
+`
+
+	// 内置验证方法
+	BuiltInValidationFunc = MobileValidationFunc + IdCardValidationFunc + BankcardValidationFunc + ChineseNameValidationFunc + VsemverValidationFunc + EmailsValidationFunc +
+		RunelenValidationFunc + RuneminValidationFunc + RunemaxValidationFunc + NumstrGtValidationFunc + NumstrGteValidationFunc + NumstrLtValidationFunc
+
+	// 翻译器初始化函数
+	TranslatorInitFunc = `// 初始化翻译器
+func init() {
+	// 初始化翻译器
+%[1]s
+	trans, _ = uni.GetTranslator("%[2]s")
+
+%[3]s
+	// 注册默认翻译
+	_ = %[2]sTrans.RegisterDefaultTranslations(validate, trans)
+
+	// 注册自定义翻译
+	registerCustomTranslations(validate, trans)
+}
+`
+	// 使用json标签作为字段名，与接口返回的字段保持一致
+	TranslatorTagNameFunc = `	// 使用json标签作为字段名，与接口返回的字段保持一致
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+` + ProtobufTagNameFallback + `		if name == "-" || name == "" {
+			return field.Name
+		}
+		return name
+	})
+`
+
+	// 按语言翻译验证错误
+	TranslateWithFunc = `
+// TranslateWith 使用指定语言翻译验证错误，未知的语言使用默认语言
+func TranslateWith(err error, locale string) error {
+	if err == nil {
+		return nil
+	}
+
+	var errs validator.ValidationErrors
+	if ok := errors.As(err, &errs); !ok {
+		return err
+	}
+
+	t, found := uni.GetTranslator(locale)
+	if !found {
+		t = trans
+	}
+
+	var errMsgs []string
+	for _, e := range errs {
+		errMsgs = append(errMsgs, translateField(e, t))
+	}
+	return errors.New(strings.Join(errMsgs, ", "))
+}
+`
+	// GetValidateErrorMsgFunc 获取翻译后的验证错误信息，便于直接作为响应消息使用
+	GetValidateErrorMsgFunc = `
+// GetValidateErrorMsg 获取翻译后的验证错误信息，err为nil时返回空字符串
+func GetValidateErrorMsg(err error) string {
+	if err = Translate(err); err != nil {
+		return err.Error()
+	}
+	return ""
+}
 `
-	// 自定义标签翻译注册模板
+	// 自定义标签翻译注册模板，翻译文本中{0}为字段名，{1}为标签参数，如within=10中的10
 	CustomTranslationTemplate = `
-	_ = trans.Add("%s", "{0}%s", false)
+	_ = trans.Add("%s", %q, true)
 	_ = validate.RegisterTranslation("%s", trans, func(ut ut.Translator) error {
 		return nil
 	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("%s", fe.Field())
+		t, _ := ut.T("%s", fe.Field(), fe.Param())
 		return t
 	})
 `
 )
 
-// ProcessTypesAPI 根据api文件直接处理
-func ProcessTypesAPI(p *plugin.Plugin, options Options) error {
-	// 寻找所有的请求结构体并生成验证方法
-	validateStructs := make([]spec.DefineStruct, 0)
-	// 收集所有请求结构体和自定义验证标签
-	for _, decl := range p.Api.Types {
-		switch st := decl.(type) {
-		case spec.DefineStruct:
-			for _, field := range st.Members {
-				validateTag := extractValidateTag(field.Tag)
-				if validateTag != "" {
-					validateStructs = append(validateStructs, st)
-					break
-				}
-			}
-		case spec.NestedStruct:
-			fmt.Println("NestedStruct", st.Name())
-		case spec.PrimitiveType:
-			fmt.Println("PrimitiveType", st.Name())
-		default:
-			fmt.Println(reflect.TypeOf(decl), decl.Name())
-		}
-	}
+// translationOverrideRegex 匹配旧版本增量添加的不覆盖已有翻译文本的自定义翻译
+var translationOverrideRegex = regexp.MustCompile(`(_ = trans\.Add\("[^"]*", "(?:[^"\\]|\\.)*", )false\)`)
+
+// translationParamRegex 匹配旧版本生成的只传入字段名的自定义翻译
+var translationParamRegex = regexp.MustCompile(`ut\.T\(("[^"]*"), fe\.Field\(\)\)`)
+
+// sharedTranslatorRegex 匹配翻译器初始化代码中获取翻译器的语句
+var sharedTranslatorRegex = regexp.MustCompile(`(\t+)(\w+), _ :?= uni\.GetTranslator\("\w+"\)\n`)
+
+// builtInValidation 插件内置的验证方法
+type builtInValidation struct {
+	// 验证标签
+	Tag string
+	// 验证函数名
+	Func string
+	// 注册映射中的注释
+	Comment string
+	// 验证函数代码
+	Code string
+	// 翻译文本，为空时使用语言的默认翻译
+	Message string
+	// 错误码，为0时不生成错误码
+	ErrorCode int
+	// 别名指向的验证标签，别名与该标签共用验证函数和翻译
+	Alias string
+	// 按语言定义的翻译文本，优先于Message
+	Messages map[string]string
+	// 是否为配置文件覆盖了正则表达式的插件内置验证方法，内置的测试用例不再适用
+	RegexOverridden bool
+}
 
-	validationFileContent := strings.Builder{}
-	validationFileContent.WriteString("package types \n\n")
-	validationFileContent.WriteString("import (\n\t\"github.com/go-playground/validator/v10\"\n)\n\n")
-	validationFileContent.WriteString("var validate = validator.New()\n\n")
-	for _, v := range validateStructs {
-		// 将结构体注册为检验方法
-		validationFileContent.WriteString(fmt.Sprintf("func (v *%s) Validate() error {\n", v.Name()))
-		validationFileContent.WriteString("return validate.Struct(v)\n")
-		validationFileContent.WriteString("}\n")
-	}
-	// 格式化验证文件内容
-	formatted, err := format.Source([]byte(validationFileContent.String()))
-	if err != nil {
-		return fmt.Errorf("格式化验证文件代码失败: %w", err)
-	}
-	filePath := p.Dir + "/internal/types/validation.go"
-	// 写入验证文件
-	if err := os.WriteFile(filePath, formatted, 0644); err != nil {
-		return fmt.Errorf("写入验证文件失败: %w", err)
-	}
+// validationFuncImports 插件内置及配置文件定义的验证函数可能使用的标准库包，按导入顺序排列
+var validationFuncImports = []string{"regexp", "strconv", "strings", "unicode/utf8"}
 
-	if options.DebugMode {
-		fmt.Printf("成功创建验证文件: %s\n", filePath)
-	}
+// usesImport 判断代码中是否使用了指定导入路径的包
+func usesImport(code, importPath string) bool {
+	return strings.Contains(code, path.Base(importPath)+".")
+}
 
-	return nil
+// builtInValidations 插件内置的验证方法，按注册顺序排列
+var builtInValidations = []builtInValidation{
+	{Tag: "mobile", Func: "validateMobile", Comment: "手机号验证", Code: MobileValidationFunc},
+	{Tag: "idcard", Func: "validateIdCard", Comment: "身份证号验证", Code: IdCardValidationFunc},
+	{Tag: "bankcard", Func: "validateBankcard", Comment: "银行卡号验证", Code: BankcardValidationFunc},
+	{Tag: "chinesename", Func: "validateChinesename", Comment: "中文姓名验证", Code: ChineseNameValidationFunc},
+	{Tag: "vsemver", Func: "validateVsemver", Comment: "语义化版本号验证（允许v前缀）", Code: VsemverValidationFunc},
+	{Tag: "emails", Func: "validateEmails", Comment: "逗号分隔的邮箱列表验证", Code: EmailsValidationFunc},
+	{Tag: "runelen", Func: "validateRunelen", Comment: "字符数等于参数", Code: RunelenValidationFunc},
+	{Tag: "runemin", Func: "validateRunemin", Comment: "字符数不少于参数", Code: RuneminValidationFunc},
+	{Tag: "runemax", Func: "validateRunemax", Comment: "字符数不超过参数", Code: RunemaxValidationFunc},
+	{Tag: "numstr_gt", Func: "validateNumstrGt", Comment: "数字字符串的值大于参数", Code: NumstrGtValidationFunc},
+	{Tag: "numstr_gte", Func: "validateNumstrGte", Comment: "数字字符串的值大于或等于参数", Code: NumstrGteValidationFunc},
+	{Tag: "numstr_lt", Func: "validateNumstrLt", Comment: "数字字符串的值小于参数", Code: NumstrLtValidationFunc},
+}
+
+// GenerateResult 生成的文件内容，为nil表示该文件不需要创建或修改
+type GenerateResult struct {
+	// 添加了Validate方法的types文件
+	TypesFile []byte
+	// 验证方法文件validation.go
+	ValidationFile []byte
+	// 翻译器文件translator.go
+	TranslatorFile []byte
+	// 错误码文件errcode.go
+	ErrorCodeFile []byte
+	// 错误处理函数文件errhandler.go
+	ErrorHandlerFile []byte
+	// 验证方法的测试文件validation_test.go
+	TestFile []byte
+	// 枚举常量文件enums.go
+	EnumsFile []byte
+	// 是否在TypesFile中声明了验证器变量，同一目录中的其他types文件不再重复声明
+	DefinedValidate bool
+	// 需要验证的结构体，按声明顺序排列
+	Structs []string
+	// 发现的自定义验证标签，按字母顺序排列
+	CustomTags []string
+}
+
+// generateInput 生成代码需要的输入，现有文件的内容为nil表示文件不存在
+type generateInput struct {
+	// types文件路径，仅用于错误信息
+	FilePath string
+	// types文件内容
+	Src []byte
+	// 包名，为空时使用types文件中的包名
+	PackageName string
+	// 目录中是否已经生成过声明变量
+	GenFlag bool
+	// 现有的validation.go
+	Validation []byte
+	// 现有的translator.go
+	Translator []byte
+	// 现有的validation_test.go
+	ValidationTest []byte
+	// errcode.go是否已存在
+	ErrorCodeExists bool
+	// errhandler.go是否已存在
+	ErrorHandlerExists bool
+	// 现有的enums.go
+	Enums []byte
+	// 同一包中其他文件的枚举值，与当前types文件的枚举值合并生成enums.go
+	PackageEnums []enumFile
+	// 包内除validation.go外其他文件声明的函数
+	PackageFuncs map[string]bool
+	// 同一包中其他文件声明了Validate等方法的接收者类型名，键为方法名
+	PackageMethods map[string]map[string]bool
+	// 同一包中其他types文件声明的包级变量，已声明验证器变量时不再重复声明
+	PackageVars map[string]bool
+	// 共享翻译器包的导入路径，为空时使用Options.SharedTranslatorPackage
+	SharedTranslatorImport string
+	// 包内所有结构体使用的验证标签，启用清理时用于判断自定义标签是否已不再使用
+	PackageTags map[string]bool
+	// 独立验证包的导入路径，为空时使用Options.ValidatorPackage
+	ValidatorImport string
+}
+
+// Generate 根据types文件的源码生成代码，返回生成的文件内容，不读写文件系统（配置文件除外）
+// pkg为空时使用源码中的包名
+func Generate(src []byte, pkg string, options Options) (*GenerateResult, error) {
+	return generate(generateInput{FilePath: "types.go", Src: src, PackageName: pkg}, options)
 }
 
 // ProcessTypesFile 处理types.go文件，添加验证逻辑
-func ProcessTypesFile(genFlag bool, filePath string, options Options) (bool, error) {
+// summary不为nil时记录处理的结构体、自定义标签及写入和跳过的文件
+func ProcessTypesFile(genFlag bool, filePath string, options Options, summary *Summary) (bool, error) {
 	// 读取文件内容
 	fileContent, err := os.ReadFile(filePath)
 	if err != nil {
 		return false, fmt.Errorf("读取文件失败: %w", err)
 	}
-	genDefineValidate := false
-	if options.DebugMode {
-		fmt.Println("============= 原始文件内容 =============")
-		fmt.Println(string(fileContent))
-		fmt.Println("=======================================")
+	if options.MethodsInSeparateFile {
+		return processMethodsFile(genFlag, filePath, fileContent, options, summary)
 	}
+	in := generateInput{
+		FilePath: filePath,
+		Src:      fileContent,
+		GenFlag:  genFlag,
+	}
+	return processTypes(in, filePath, fileContent, options, summary, generate)
+}
 
-	fset := token.NewFileSet()
-	f, err := parser.ParseFile(fset, filePath, fileContent, parser.ParseComments)
+// processTypes 读取types文件所在目录中现有的生成文件，调用gen生成代码并写入
+// typesPath为Validate等方法写入的文件，existing为该文件现有的内容
+func processTypes(in generateInput, typesPath string, existing []byte, options Options, summary *Summary, gen func(generateInput, Options) (*GenerateResult, error)) (bool, error) {
+	validationFileName, translatorFileName, err := outputFileNames(options)
 	if err != nil {
-		return false, fmt.Errorf("解析文件失败: %w", err)
+		return false, err
 	}
 
-	// 寻找所有的请求结构体并生成验证方法
-	var reqStructs []string
-	// 定义变量，但不使用，防止编译错误
-	existingValidations := make(map[string]bool)
-
-	// 检查imports
-	hasValidatorImport := false
-	for _, imp := range f.Imports {
-		if imp.Path.Value == ValidateImport {
-			hasValidatorImport = true
-			break
+	// 生成的文件与types.go在同一目录
+	dirPath := filepath.Dir(typesPath)
+	validationFilePath := filepath.Join(dirPath, validationFileName)
+	translatorFilePath := filepath.Join(dirPath, translatorFileName)
+	errorCodeFilePath := filepath.Join(dirPath, ErrorCodeFileName)
+	errorHandlerFilePath := filepath.Join(dirPath, ErrorHandlerFileName)
+	enumsFilePath := filepath.Join(dirPath, EnumsFileName)
+	in.PackageFuncs = packageFuncs(dirPath, validationFilePath)
+	in.PackageMethods = packageMethods(dirPath, generatedMethods, typesPath, validationFilePath)
+	in.PackageVars = packageVars(dirPath, typesPath, validationFilePath)
+	if options.GenerateEnums {
+		in.PackageEnums = packageEnumFiles(dirPath, typesPath, in.FilePath, EnumsFileName)
+	}
+	if options.Prune {
+		aliases, err := ruleAliases(options)
+		if err != nil {
+			return false, err
 		}
+		in.PackageTags = packageTags(dirPath, aliases)
 	}
 
-	// 提取自定义验证标签
-	customTags := make(map[string]bool)
-
-	// 收集所有请求结构体和自定义验证标签
-	for _, decl := range f.Decls {
-		genDecl, ok := decl.(*ast.GenDecl)
-		if !ok || genDecl.Tok != token.TYPE {
-			continue
+	// 启用独立验证包时，验证文件和翻译器文件生成到模块中的验证包目录
+	validatorPkg, err := validatorPackage(options)
+	if err != nil {
+		return false, err
+	}
+	if validatorPkg != "" {
+		moduleRoot, modulePath, err := findModule(dirPath)
+		if err != nil {
+			return false, err
 		}
+		pkgDir := filepath.Join(moduleRoot, filepath.FromSlash(validatorPkg))
+		validationFilePath = filepath.Join(pkgDir, validationFileName)
+		translatorFilePath = filepath.Join(pkgDir, translatorFileName)
+		in.PackageFuncs = packageFuncs(pkgDir, validationFilePath)
+		in.ValidatorImport = path.Join(modulePath, validatorPkg)
+	}
 
-		for _, spec := range genDecl.Specs {
-			typeSpec, ok := spec.(*ast.TypeSpec)
-			if !ok {
-				continue
-			}
-
-			// 如果是结构体类型
-			structType, ok := typeSpec.Type.(*ast.StructType)
-			if !ok {
-				continue
-			}
-
-			// 不再仅限于以Req结尾的结构体
-			// 检查所有结构体是否包含validate标签
-			hasValidateTag := false
-			for _, field := range structType.Fields.List {
-				if field.Tag != nil {
-					tag := field.Tag.Value
-					validateTag := extractValidateTag(tag)
-					if validateTag != "" {
-						hasValidateTag = true
-						break
-					}
-				}
+	// 启用共享翻译器包时，翻译器文件生成到模块中的共享包目录
+	sharedPkg, err := sharedTranslatorPackage(options)
+	if err != nil {
+		return false, err
+	}
+	if sharedPkg != "" && options.EnableTranslator {
+		moduleRoot, modulePath, err := findModule(dirPath)
+		if err != nil {
+			return false, err
+		}
+		translatorFilePath = filepath.Join(moduleRoot, filepath.FromSlash(sharedPkg), translatorFileName)
+		in.SharedTranslatorImport = path.Join(modulePath, sharedPkg)
+	}
+	// 启用输出目录时生成的文件写入输出目录，保持相对于模块根目录的目录结构
+	root, err := outputRoot(dirPath, options)
+	if err != nil {
+		return false, err
+	}
+	if root != "" {
+		if existing, err = readOutputFile(root, typesPath, options); err != nil {
+			return false, fmt.Errorf("读取现有types文件失败: %w", err)
+		}
+	}
+	if in.Validation, err = readOutputFile(root, validationFilePath, options); err != nil {
+		return false, fmt.Errorf("读取现有验证文件失败: %w", err)
+	}
+	if options.EnableTranslator {
+		if in.Translator, err = readOutputFile(root, translatorFilePath, options); err != nil {
+			return false, fmt.Errorf("读取现有翻译器文件失败: %w", err)
+		}
+	}
+	// 强制重新生成时忽略本次执行前已存在的验证文件和翻译器文件
+	// 同一目录（或共享翻译器包）中的其他types文件仍在本次生成的文件基础上合并
+	if options.Force {
+		for _, file := range []struct {
+			path    string
+			content *[]byte
+		}{{validationFilePath, &in.Validation}, {translatorFilePath, &in.Translator}} {
+			outPath, err := outputPath(root, file.path, options)
+			if err != nil {
+				return false, err
 			}
-
-			// 如果结构体包含验证标签或是以Req结尾，则处理
-			if hasValidateTag || strings.HasSuffix(typeSpec.Name.Name, "Req") {
-				reqStructs = append(reqStructs, typeSpec.Name.Name)
-
-				// 分析结构体字段的验证标签
-				for _, field := range structType.Fields.List {
-					if field.Tag != nil {
-						tag := field.Tag.Value
-
-						// 提取验证标签
-						validateTag := extractValidateTag(tag)
-						if validateTag != "" {
-							// 分析验证标签中的自定义验证器
-							validators := strings.Split(validateTag, ",")
-							for _, v := range validators {
-								// 跳过空验证器
-								if v == "" {
-									continue
-								}
-
-								// 如果启用了自定义验证或翻译器，添加自定义标签
-								if (options.EnableCustomValidation || options.EnableTranslator) && !isBuiltInValidator(v) {
-									// 添加自定义验证标签
-									customTags[v] = true
-
-									// 如果启用了自定义验证，检查该验证器函数是否已存在
-									if options.EnableCustomValidation {
-										if bytes.Contains(fileContent, []byte(fmt.Sprintf("func validate%s", strings.Title(v)))) {
-											existingValidations[v] = true
-										}
-									}
-								}
-							}
-						}
-					}
-				}
+			if !summary.handled(outPath) {
+				*file.content = nil
 			}
 		}
 	}
-
-	// 没有找到请求结构体，直接返回
-	if len(reqStructs) == 0 && len(customTags) == 0 {
-		return false, nil
+	testFilePath := filepath.Join(filepath.Dir(validationFilePath), validationTestFileName(validationFileName))
+	if options.GenerateTests {
+		if in.ValidationTest, err = readOutputFile(root, testFilePath, options); err != nil {
+			return false, fmt.Errorf("读取现有测试文件失败: %w", err)
+		}
+	}
+	if in.ErrorCodeExists, err = outputFileExists(root, errorCodeFilePath, options); err != nil {
+		return false, err
+	}
+	if in.ErrorHandlerExists, err = outputFileExists(root, errorHandlerFilePath, options); err != nil {
+		return false, err
+	}
+	if options.GenerateEnums {
+		if in.Enums, err = readOutputFile(root, enumsFilePath, options); err != nil {
+			return false, fmt.Errorf("读取现有枚举常量文件失败: %w", err)
+		}
 	}
 
-	// 获取文件所在的目录路径
-	dirPath := filepath.Dir(filePath)
-
-	// 验证文件的路径（与types.go在同一目录）
-	validationFilePath := filepath.Join(dirPath, "validation.go")
-
-	// 翻译器文件的路径（与types.go在同一目录）
-	translatorFilePath := ""
-	if options.EnableTranslator {
-		translatorFilePath = filepath.Join(dirPath, "translator.go")
+	result, err := gen(in, options)
+	if err != nil {
+		return false, err
 	}
 
-	// 检查验证文件是否已存在
-	validationExists := false
-	validationContent := ""
+	summary.addResult(dirPath, result)
+
+	// 写入生成的文件，enabled表示本次执行是否负责生成该文件
+	hasStructs := len(result.Structs) > 0
+	files := []struct {
+		path     string
+		content  []byte
+		existing []byte
+		desc     string
+		enabled  bool
+	}{
+		{typesPath, result.TypesFile, existing, "添加验证方法到", hasStructs},
+		{validationFilePath, result.ValidationFile, in.Validation, "写入验证文件", hasStructs},
+		{translatorFilePath, result.TranslatorFile, in.Translator, "写入翻译器文件", hasStructs && options.EnableTranslator},
+		{errorCodeFilePath, result.ErrorCodeFile, nil, "创建错误码文件", hasStructs && options.GenerateErrorCodes},
+		{errorHandlerFilePath, result.ErrorHandlerFile, nil, "创建错误处理文件", hasStructs && options.GenerateErrorHandler},
+		{testFilePath, result.TestFile, in.ValidationTest, "写入测试文件", hasStructs && options.GenerateTests},
+		{enumsFilePath, result.EnumsFile, in.Enums, "写入枚举常量文件", hasStructs && options.GenerateEnums},
+	}
 
-	if _, err := os.Stat(validationFilePath); err == nil {
-		// 验证文件已存在，读取内容
-		validationBytes, err := os.ReadFile(validationFilePath)
-		if err != nil {
-			return false, fmt.Errorf("读取现有验证文件失败: %w", err)
+	// 写入前由PostProcess处理生成的文件内容，处理后的内容与现有文件相同时不重复写入
+	if options.PostProcess != nil {
+		for i, file := range files {
+			if file.content == nil {
+				continue
+			}
+			if files[i].content, err = options.PostProcess(file.path, file.content); err != nil {
+				return false, fmt.Errorf("后处理文件%s失败: %w", file.path, err)
+			}
 		}
-		validationContent = string(validationBytes)
-		validationExists = true
+	}
 
-		// 检查现有验证文件中的验证函数
-		for tag := range customTags {
-			if bytes.Contains(validationBytes, []byte(fmt.Sprintf("func validate%s", strings.Title(tag)))) {
+	// 写入前对生成的文件所在的包进行类型检查，避免写入无法编译的代码
+	if options.TypeCheck && hasStructs {
+		overlay := make(map[string][]byte)
+		for _, file := range files {
+			if file.content != nil {
+				overlay[file.path] = file.content
+			}
+		}
+		importPaths := make(map[string]string)
+		if in.ValidatorImport != "" {
+			importPaths[filepath.Dir(validationFilePath)] = in.ValidatorImport
+		}
+		if in.SharedTranslatorImport != "" {
+			importPaths[filepath.Dir(translatorFilePath)] = in.SharedTranslatorImport
+		}
+		if err := typeCheck(dirPath, overlay, importPaths); err != nil {
+			return false, err
+		}
+	}
+
+	for _, file := range files {
+		outPath, err := outputPath(root, file.path, options)
+		if err != nil {
+			return false, err
+		}
+		if file.content == nil || bytes.Equal(file.content, file.existing) {
+			// 文件已是最新或已存在
+			if file.enabled {
+				summary.skip(outPath)
+			}
+			continue
+		}
+		if err := writeFile(outPath, file.content, options.DryRun || options.CheckOnly); err != nil {
+			return false, fmt.Errorf("写入文件%s失败: %w", outPath, err)
+		}
+		// DryRun及CheckOnly模式下未写入文件
+		if options.DryRun || options.CheckOnly {
+			summary.skip(outPath)
+			if options.CheckOnly {
+				summary.outdate(outPath)
+			}
+		} else {
+			summary.write(outPath)
+		}
+		options.debugf("成功%s: %s", file.desc, outPath)
+	}
+
+	// 检查自定义验证标签的验证方法是否仍为生成的空方法，文件写入后检查，便于先生成再实现
+	if hasStructs {
+		validation := files[1].content
+		if validation == nil {
+			validation = files[1].existing
+		}
+		if err := checkStubs(validationFilePath, validation, result.CustomTags, options); err != nil {
+			return false, err
+		}
+	}
+	return result.DefinedValidate, nil
+}
+
+// generatedMethods 为结构体生成的方法，已声明时不再重复生成
+var generatedMethods = []string{"Validate", "ValidateCtx", "MustValidate", "ValidateJSON", "ValidateFields"}
+
+// readExistingFile 读取已存在的文件，文件不存在时返回nil
+func readExistingFile(filePath string) ([]byte, error) {
+	content, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return content, err
+}
+
+// generate 根据types文件及现有的生成文件生成代码
+func generate(in generateInput, options Options) (*GenerateResult, error) {
+	result := &GenerateResult{}
+	genFlag := in.GenFlag
+	filePath := in.FilePath
+	fileContent := in.Src
+	// 获取翻译语言
+	langs, err := resolveLanguages(options)
+	if err != nil {
+		return nil, err
+	}
+	lang := langs[0]
+	// 插件内置及配置文件定义的验证方法
+	validations, err := pluginValidations(options)
+	if err != nil {
+		return nil, err
+	}
+	if options.TrimSpace {
+		validations = trimSpaceValidations(validations)
+	}
+	knownTags := validationTags(validations)
+	// 配置文件定义的验证规则别名，别名本身不生成验证方法，别名中使用的自定义验证标签仍需生成
+	aliases, err := ruleAliases(options)
+	if err != nil {
+		return nil, err
+	}
+	options.debugf("原始文件内容 %s:\n%s", filePath, fileContent)
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filePath, fileContent, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("解析文件失败: %w", err)
+	}
+
+	// 字段没有validate标签时按注释中的验证规则添加标签，写回types文件后validator才能读取
+	tagged, err := injectCommentTags(fset, f, fileContent)
+	if err != nil {
+		return nil, fmt.Errorf("处理%s中注释的验证规则失败: %w", filePath, err)
+	}
+	if tagged != nil {
+		options.debugf("按注释中的验证规则添加validate标签")
+		fileContent = tagged
+		if f, err = parser.ParseFile(fset, filePath, fileContent, parser.ParseComments); err != nil {
+			return nil, fmt.Errorf("解析添加标签后的文件失败: %w", err)
+		}
+	}
+
+	// 寻找所有的请求结构体并生成验证方法
+	var reqStructs []string
+	// 定义变量，但不使用，防止编译错误
+	existingValidations := make(map[string]bool)
+
+	// 已处理过的文件或同一包中的其他types文件已声明验证器变量时，不再重复声明
+	// 文件导入了验证器包（如用户代码中使用别名导入）不代表已声明验证器变量
+	validateDeclared := fileVars(f)["validate"] || genFlag || in.PackageVars["validate"]
+	// 文件使用别名导入验证器时，生成的代码沿用该别名，避免重复导入
+	validatorName := validatorImportName(f)
+
+	// 提取自定义验证标签
+	customTags := make(map[string]bool)
+	// 带参数的自定义验证标签，生成的验证方法读取参数
+	paramTags := make(map[string]bool)
+	// 参数为空格分隔的多个值的自定义验证标签（如status=active disabled），默认翻译中列出可选值
+	enumTags := make(map[string]bool)
+	// 结构体使用的验证规则别名
+	usedAliases := make(map[string]bool)
+
+	// types.go中已声明的函数和已有Validate方法的结构体，重复执行插件时不再重复生成
+	typesFuncs := declaredFuncs(f)
+	// 同一包中其他文件已声明的方法同样不再生成，避免重复声明
+	validateReceivers := methodReceivers(f, "Validate")
+	maps.Copy(validateReceivers, in.PackageMethods["Validate"])
+	validateCtxReceivers := methodReceivers(f, "ValidateCtx")
+	maps.Copy(validateCtxReceivers, in.PackageMethods["ValidateCtx"])
+	mustValidateReceivers := methodReceivers(f, "MustValidate")
+	maps.Copy(mustValidateReceivers, in.PackageMethods["MustValidate"])
+	validateJSONReceivers := methodReceivers(f, "ValidateJSON")
+	maps.Copy(validateJSONReceivers, in.PackageMethods["ValidateJSON"])
+	validateFieldsReceivers := methodReceivers(f, "ValidateFields")
+	maps.Copy(validateFieldsReceivers, in.PackageMethods["ValidateFields"])
+
+	// 结构体直接使用的注册验证标签及引用的类型，用于生成结构体专属的验证器
+	structTags := make(map[string]map[string]bool)
+	structRefs := make(map[string][]string)
+
+	// 文件中声明的所有结构体
+	localStructs := make(map[string]*ast.StructType)
+
+	// 标记了结构体级别验证的结构体
+	var structLevels []string
+
+	// 标记了忽略的结构体，不生成Validate方法
+	ignoredStructs := make(map[string]bool)
+
+	// 可能需要生成Validate方法的结构体，按声明顺序排列
+	var candidateStructs []string
+
+	// 收集所有请求结构体和自定义验证标签
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			typeSpec, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+
+			// 如果是结构体类型
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+
+			localStructs[typeSpec.Name.Name] = structType
+
+			isStructLevel := hasDirective(genDecl, typeSpec, StructLevelMarker)
+			if isStructLevel {
+				structLevels = append(structLevels, typeSpec.Name.Name)
+			}
+
+			if options.PerStructValidator {
+				structTags[typeSpec.Name.Name] = registeredTags(structType, options, knownTags, aliases)
+				structRefs[typeSpec.Name.Name] = referencedTypes(structType)
+			}
+
+			// 标记了不生成方法的结构体视为已有Validate方法，其验证标签仍然收集
+			if hasDirective(genDecl, typeSpec, NoMethodMarker) {
+				validateReceivers[typeSpec.Name.Name] = true
+				validateCtxReceivers[typeSpec.Name.Name] = true
+			}
+
+			// 标记了忽略的结构体不生成Validate方法
+			if hasDirective(genDecl, typeSpec, IgnoreMarker) {
+				ignoredStructs[typeSpec.Name.Name] = true
+				continue
+			}
+
+			candidateStructs = append(candidateStructs, typeSpec.Name.Name)
+		}
+	}
+
+	// 不再仅限于以Req结尾的结构体，检查所有结构体是否包含validate标签
+	// 匿名嵌入的同文件结构体的字段同样参与检查，嵌套字段的实际验证仍由validator处理
+	for _, name := range candidateStructs {
+		fields := promotedFields(name, localStructs)
+		hasValidateTag := false
+		for _, field := range fields {
+			if field.Tag != nil && extractValidateTag(field.Tag.Value) != "" {
+				hasValidateTag = true
+				break
+			}
+		}
+
+		// 如果结构体包含验证标签、以指定后缀（默认Req）结尾或标记了结构体级别验证，则处理
+		if !hasValidateTag && !slices.Contains(structLevels, name) && !hasIncludeSuffix(name, options) {
+			continue
+		}
+		reqStructs = append(reqStructs, name)
+
+		// 分析结构体字段的验证标签
+		for _, field := range fields {
+			if field.Tag == nil {
+				continue
+			}
+			// 分析验证标签及其中验证规则别名代表的自定义验证器
+			for _, v := range expandRuleAliases(splitValidateTag(extractValidateTag(field.Tag.Value)), aliases) {
+				// 带参数的验证器（如within=10）按名称注册
+				v, param, hasParam := parseValidator(v)
+				// 跳过空验证器及验证规则别名
+				if _, ok := aliases[v]; v == "" || ok {
+					usedAliases[v] = ok
+					continue
+				}
+
+				// 如果启用了自定义验证或翻译器，添加自定义标签
+				if (options.EnableCustomValidation || options.EnableTranslator) && !isBuiltInValidator(v) && !knownTags[v] {
+					// 添加自定义验证标签
+					customTags[v] = true
+					if hasParam {
+						paramTags[v] = true
+						if len(oneofValueRegex.FindAllString(param, -1)) > 1 {
+							enumTags[v] = true
+						}
+					}
+
+					// 如果启用了自定义验证，检查该验证器函数是否已存在
+					if options.EnableCustomValidation && hasValidationFunc(typesFuncs, v) {
+						existingValidations[v] = true
+					}
+				}
+			}
+		}
+	}
+
+	// 字段引用的同文件结构体（包括多层嵌套及切片、数组、map元素）同样生成验证方法
+	reqStructs = expandStructs(reqStructs, localStructs)
+	reqStructs = slices.DeleteFunc(reqStructs, func(name string) bool {
+		return ignoredStructs[name]
+	})
+	// 按结构体在文件中的声明位置排序，保证生成的方法顺序稳定
+	slices.SortFunc(reqStructs, func(a, b string) int {
+		return cmp.Compare(localStructs[a].Pos(), localStructs[b].Pos())
+	})
+
+	result.Structs = reqStructs
+	result.CustomTags = sortedTags(customTags)
+
+	// 没有找到请求结构体，直接返回
+	if len(reqStructs) == 0 && len(customTags) == 0 {
+		return result, nil
+	}
+
+	// 验证文件和翻译器文件的名称，仅用于错误信息
+	validationFilePath, translatorFilePath, err := outputFileNames(options)
+	if err != nil {
+		return nil, err
+	}
+	// 生成的方法的接收者名称
+	receiver, err := receiverName(options)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctxValidationOptions(options); err != nil {
+		return nil, err
+	}
+	if err := lazyTranslatorOptions(options); err != nil {
+		return nil, err
+	}
+	if err := labelTagNameOptions(options); err != nil {
+		return nil, err
+	}
+	if err := bilingualOptions(options, langs); err != nil {
+		return nil, err
+	}
+	// ValidateJSON方法返回翻译后的错误信息，需要翻译器注册的翻译及json字段名
+	if options.GenerateJSONMethod && !options.EnableTranslator {
+		return nil, fmt.Errorf("生成ValidateJSON方法需要同时启用--translator")
+	}
+	// 生成的代码引用翻译器的方式，未启用翻译器时为nil
+	var tr *translatorRef
+	if options.EnableTranslator {
+		sharedImport := in.SharedTranslatorImport
+		if sharedImport == "" {
+			if sharedImport, err = sharedTranslatorPackage(options); err != nil {
+				return nil, err
+			}
+		}
+		tr = &translatorRef{Import: sharedImport, Lazy: options.LazyTranslator}
+	}
+	// 启用独立验证包时，验证文件和翻译器文件属于验证包，types包通过验证包验证
+	validatorImport := in.ValidatorImport
+	if validatorImport == "" {
+		if validatorImport, err = validatorPackage(options); err != nil {
+			return nil, err
+		}
+	}
+	if validatorImport != "" {
+		if len(structLevels) > 0 {
+			return nil, fmt.Errorf("结构体级别验证需要引用types包中的结构体，不能与独立验证包同时使用")
+		}
+		if options.EnableTranslator {
+			tr = &translatorRef{Import: validatorImport}
+		}
+	}
+	if !options.EnableTranslator {
+		translatorFilePath = ""
+	}
+
+	// 检查验证文件是否已存在
+	validationExists := false
+	validationContent := ""
+	var validationFile *ast.File
+
+	if in.Validation != nil {
+		// 验证文件已存在
+		validationBytes := in.Validation
+		// 旧版本生成的验证方法名不符合Go命名规范（如validateNew_tag1），仍未实现时迁移到当前的函数名
+		if options.EnableCustomValidation {
+			if validationBytes, err = renameLegacyStubs(validationFilePath, validationBytes); err != nil {
+				return nil, err
+			}
+		}
+		validationContent = string(validationBytes)
+		validationExists = true
+
+		validationFile, err = parseExistingFile(validationFilePath, validationBytes)
+		if err != nil {
+			return nil, fmt.Errorf("解析现有验证文件失败: %w", err)
+		}
+
+		// 检查现有验证文件中的验证函数
+		validationFuncs := declaredFuncs(validationFile)
+		for _, tag := range sortedTags(customTags) {
+			if hasValidationFunc(validationFuncs, tag) {
 				existingValidations[tag] = true
 			}
 		}
@@ -308,45 +1240,99 @@ func ProcessTypesFile(genFlag bool, filePath string, options Options) (bool, err
 	// 检查翻译器文件是否已存在
 	translatorExists := false
 
-	if options.EnableTranslator && translatorFilePath != "" {
-		if _, err := os.Stat(translatorFilePath); err == nil {
-			translatorExists = true
+	if options.EnableTranslator && in.Translator != nil {
+		translatorExists = true
+		// 已有翻译器文件的初始化方式与选项不一致时，生成的方法无法正确引用翻译器
+		if !tr.shared() {
+			existing, err := parseExistingFile(translatorFilePath, in.Translator)
+			if err != nil {
+				return nil, fmt.Errorf("解析现有翻译器文件失败: %w", err)
+			}
+			if lazy := declaredFuncs(existing)["translator"]; lazy != options.LazyTranslator {
+				return nil, fmt.Errorf("已有翻译器文件的初始化方式与--lazy-translator选项不一致，需要使用--force重新生成")
+			}
 		}
 	}
 
 	// 获取包名
 	packageName := f.Name.Name
+	if in.PackageName != "" {
+		packageName = in.PackageName
+	}
+	// 验证文件所在的包名
+	validationPackage := packageName
+	if validatorImport != "" {
+		validationPackage = path.Base(validatorImport)
+	}
 
 	// 生成验证文件内容
 	var validationFileContent strings.Builder
 
 	// 如果文件不存在，添加基本结构
 	if !validationExists {
-		validationFileContent.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+		// 用户在包内其他文件中自行实现的内置验证函数不再生成，只保留注册
+		funcCode := validationFuncCode(validations, nil, in.PackageFuncs)
+
+		validationFileContent.WriteString(fmt.Sprintf("package %s\n\n", validationPackage))
 
 		// 添加导入
 		validationFileContent.WriteString("import (\n")
-		validationFileContent.WriteString("\t\"regexp\"\n")
+		for _, imp := range validationFuncImports {
+			if usesImport(funcCode, imp) {
+				validationFileContent.WriteString("\t" + strconv.Quote(imp) + "\n")
+			}
+		}
+		validationFileContent.WriteString("\t\"sync\"\n")
+		if options.PerStructValidator {
+			validationFileContent.WriteString("\t\"github.com/go-playground/locales\"\n")
+			validationFileContent.WriteString("\tut \"github.com/go-playground/universal-translator\"\n")
+		}
 		validationFileContent.WriteString("\t" + ValidateImport + "\n")
+		// 不生成Validate方法时types.go保持不变，验证器变量声明在验证文件中
+		switch {
+		case validatorImport != "":
+			// 独立验证包中声明验证器变量及供types包调用的验证函数
+			imports := []importSpec{{Path: "context"}, {Path: "fmt"}}
+			imports = append(imports, typesImports(lang, typesImportOptions{
+				Translations:    !options.EnableTranslator,
+				SharedValidator: options.SharedValidator,
+			})...)
+			for _, imp := range imports {
+				validationFileContent.WriteString("\t" + imp.String() + "\n")
+			}
+		case options.SkipMethodGeneration:
+			imports := typesImports(lang, typesImportOptions{
+				Translations:    !options.EnableTranslator,
+				SharedValidator: options.SharedValidator,
+			})
+			if tr.shared() {
+				imports = append(imports, importSpec{Path: tr.Import})
+			}
+			for _, imp := range imports {
+				validationFileContent.WriteString("\t" + imp.String() + "\n")
+			}
+		}
 		validationFileContent.WriteString(")\n\n")
+		switch {
+		case validatorImport != "":
+			validationFileContent.WriteString(validatorPackageCode(lang, options) + "\n")
+		case options.SkipMethodGeneration:
+			validationFileContent.WriteString(validatorVarDecl(lang, options, tr) + "\n")
+		}
 
 		// 添加验证方法映射注释
 		validationFileContent.WriteString(ValidationRegisterComment + "\n")
 
 		// 添加验证方法映射开始
 		validationFileContent.WriteString(ValidateRegisterMap)
-
-		// 按字母顺序排序标签，确保生成顺序一致
-		var sortedTags []string
-		for tag := range customTags {
-			sortedTags = append(sortedTags, tag)
+		for _, v := range validations[len(builtInValidations):] {
+			validationFileContent.WriteString(fmt.Sprintf("\t\"%s\": %s, // %s\n", v.Tag, v.Func, v.Comment))
 		}
-		sort.Strings(sortedTags)
 
-		// 如果启用了自定义验证，添加自定义验证标签
+		// 如果启用了自定义验证，按字母顺序添加自定义验证标签，确保生成顺序一致
 		if options.EnableCustomValidation && len(customTags) > 0 {
-			for _, tag := range sortedTags {
-				validationFileContent.WriteString(fmt.Sprintf(CustomValidationMapTemplate, tag, strings.Title(tag), tag))
+			for _, tag := range sortedTags(customTags) {
+				validationFileContent.WriteString(fmt.Sprintf(CustomValidationMapTemplate, tag, exportName(tag), fmt.Sprintf(CustomValidationMapStubComment, tag)))
 			}
 		}
 
@@ -356,52 +1342,66 @@ func ProcessTypesFile(genFlag bool, filePath string, options Options) (bool, err
 		// 添加init函数
 		validationFileContent.WriteString(ValidateInitFunc + "\n")
 
+		// 添加结构体专属验证器
+		if options.PerStructValidator {
+			validationFileContent.WriteString(PerStructValidatorFunc + "\n")
+		}
+
 		// 添加内置验证函数
-		validationFileContent.WriteString(BuiltInValidationFunc + "\n")
+		validationFileContent.WriteString(funcCode + "\n")
 
 		// 如果启用了自定义验证，添加自定义验证函数
 		if options.EnableCustomValidation && len(customTags) > 0 {
-			// 按字母顺序添加验证函数
-			for _, tag := range sortedTags {
-				if !existingValidations[tag] {
-					validationFileContent.WriteString(fmt.Sprintf(CustomValidationFuncTemplate, tag, strings.Title(tag), tag))
+			// 按字母顺序添加验证函数，不同标签（如age-range和ageRange）可能对应同一个函数名
+			generatedFuncs := make(map[string]bool)
+			for _, tag := range sortedTags(customTags) {
+				if !existingValidations[tag] && !generatedFuncs[validationFuncName(tag)] {
+					generatedFuncs[validationFuncName(tag)] = true
+					validationFileContent.WriteString(customValidationFunc(tag, paramTags[tag], options.CustomValidationCtx))
 				}
 			}
 		}
+
+		// 添加结构体级别验证方法
+		for _, structName := range structLevels {
+			validationFileContent.WriteString(structLevelCode(structName, options.PerStructValidator))
+		}
 	} else {
 		// 文件已存在，需要更新
+		// 未启用自定义验证时与新建文件一致，不为新的自定义标签生成注册和验证方法，已注册的标签保留
+		pendingTags := customTags
+		if !options.EnableCustomValidation {
+			pendingTags = nil
+		}
+
 		// 1. 提取现有的验证函数和注册
-		existingFuncs := make(map[string]bool)
+		existingFuncs := declaredFuncs(validationFile)
 		existingRegs := make(map[string]bool)
-		existingRegLines := make(map[string]string) // 存储原始的注册行，用于保持注释一致性
-
-		// 提取文件中所有的验证函数和注册信息
-		funcRegex := regexp.MustCompile(`func validate(\w+)\(fl validator\.FieldLevel\) bool`)
-		regRegex := regexp.MustCompile(`\t"(\w+)":\s*validate\w+,.*`)
-
-		// 查找所有的验证函数
-		funcMatches := funcRegex.FindAllStringSubmatch(validationContent, -1)
-		for _, match := range funcMatches {
-			if len(match) > 1 {
-				// 提取函数名，如AgeRange，变为小写作为tag
-				funcName := match[1]
-				if funcName != "Mobile" && funcName != "IdCard" { // 跳过内置函数
-					tag := strings.ToLower(funcName[0:1]) + funcName[1:]
-					existingFuncs[tag] = true
-				}
+		// 已注册的验证函数名，重新生成映射时沿用
+		existingRegFuncs := make(map[string]string)
+
+		// 查找所有已注册的tag
+		for tag, fn := range registeredValidations(validationFile) {
+			if !knownTags[tag] { // 跳过内置标签
+				existingRegs[tag] = true
+				existingRegFuncs[tag] = fn
 			}
 		}
 
-		// 查找所有的注册行和对应的tag
-		regMatches := regRegex.FindAllStringSubmatchIndex(validationContent, -1)
-		for _, matchIndex := range regMatches {
-			if len(matchIndex) >= 4 {
-				startOfLine := validationContent[matchIndex[0]:matchIndex[1]]
-				tag := validationContent[matchIndex[2]:matchIndex[3]]
+		// 旧格式的验证文件在init中通过validate.RegisterValidation注册，迁移到registerValidation映射中
+		for tag, fn := range registerValidationCalls(validationFile) {
+			if !knownTags[tag] && !existingRegs[tag] {
+				existingRegs[tag] = true
+				existingRegFuncs[tag] = fn
+			}
+		}
 
-				if tag != "mobile" && tag != "idcard" { // 跳过内置标签
-					existingRegs[tag] = true
-					existingRegLines[tag] = startOfLine // 保存整行内容
+		// 启用清理时删除包内已没有结构体使用的自定义标签的注册，验证函数保留
+		if options.Prune {
+			for tag := range existingRegs {
+				if !customTags[tag] && !in.PackageTags[tag] {
+					options.debugf("清理未使用的验证标签: %s", tag)
+					delete(existingRegs, tag)
 				}
 			}
 		}
@@ -410,25 +1410,45 @@ func ProcessTypesFile(genFlag bool, filePath string, options Options) (bool, err
 		var allTags []string
 
 		// 添加内置标签(固定顺序)
-		allTags = append(allTags, "mobile", "idcard")
+		for _, builtIn := range validations {
+			allTags = append(allTags, builtIn.Tag)
+		}
 
 		// 收集所有自定义标签
-		for tag := range customTags {
-			if tag != "mobile" && tag != "idcard" {
+		for _, tag := range sortedTags(pendingTags) {
+			if !knownTags[tag] {
 				allTags = append(allTags, tag)
 			}
 		}
 
-		// 收集现有但不在customTags中的标签
+		// 收集现有但不在pendingTags中的标签
 		for tag := range existingRegs {
-			if tag != "mobile" && tag != "idcard" && !customTags[tag] {
+			if !knownTags[tag] && !pendingTags[tag] {
 				allTags = append(allTags, tag)
 			}
 		}
 
 		// 除了内置标签外，对自定义标签按字母排序
-		if len(allTags) > 2 {
-			sort.Strings(allTags[2:])
+		sort.Strings(allTags[len(validations):])
+
+		// 标签是否已有验证函数，按标签生成的函数名已声明或映射中注册的函数已在包中声明
+		hasFunc := func(tag string) bool {
+			return hasValidationFunc(existingFuncs, tag) || registeredFuncDeclared(existingRegFuncs[tag], existingFuncs, in.PackageFuncs)
+		}
+
+		// 验证方法仍为生成的空方法或即将生成的标签，映射注释中提示实现
+		stubTags := make(map[string]bool)
+		stubs, err := unimplementedStubs(validationFilePath, []byte(validationContent), allTags[len(validations):])
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range stubs {
+			stubTags[tag] = true
+		}
+		for _, tag := range sortedTags(pendingTags) {
+			if !hasFunc(tag) {
+				stubTags[tag] = true
+			}
 		}
 
 		// 3. 生成新的验证方法映射
@@ -438,19 +1458,18 @@ func ProcessTypesFile(genFlag bool, filePath string, options Options) (bool, err
 		newMapContent.WriteString("var registerValidation = map[string]validator.Func{\n")
 
 		// 按排序后的标签顺序添加
-		for _, tag := range allTags {
-			if tag == "mobile" {
-				newMapContent.WriteString("\t\"mobile\": validateMobile, // 手机号验证\n")
-			} else if tag == "idcard" {
-				newMapContent.WriteString("\t\"idcard\": validateIdCard, // 身份证号验证\n")
+		for i, tag := range allTags {
+			if i < len(validations) {
+				builtIn := validations[i]
+				newMapContent.WriteString(fmt.Sprintf("\t\"%s\": %s, // %s\n", builtIn.Tag, builtIn.Func, builtIn.Comment))
 			} else {
-				// 如果存在原始的注册行，使用它保持格式一致
-				if line, exists := existingRegLines[tag]; exists {
-					newMapContent.WriteString(line + "\n")
-				} else {
-					// 否则使用标准格式
-					newMapContent.WriteString(fmt.Sprintf(CustomValidationMapTemplate, tag, strings.Title(tag), tag))
+				// 统一使用标准格式，已注册的标签沿用原有的验证函数，避免格式化后的映射反复变化
+				fn := cmp.Or(existingRegFuncs[tag], validationFuncName(tag))
+				comment := CustomValidationMapComment
+				if stubTags[tag] {
+					comment = CustomValidationMapStubComment
 				}
+				newMapContent.WriteString(fmt.Sprintf("\t%q: %s, // %s\n", tag, fn, fmt.Sprintf(comment, tag)))
 			}
 		}
 
@@ -462,16 +1481,44 @@ func ProcessTypesFile(genFlag bool, filePath string, options Options) (bool, err
 		var missingTags []string
 
 		// 收集所有需要验证函数但尚未存在的标签
-		for tag := range customTags {
-			if !existingFuncs[tag] {
+		for _, tag := range sortedTags(pendingTags) {
+			if !hasFunc(tag) {
 				missingTags = append(missingTags, tag)
 			}
 		}
 
-		// 按字母顺序添加验证函数
+		// 按字母顺序添加验证函数，对应同一个函数名的标签只生成一次
 		sort.Strings(missingTags)
+		uniqueTags := missingTags[:0]
 		for _, tag := range missingTags {
-			missingFuncContent.WriteString(fmt.Sprintf(CustomValidationFuncTemplate, tag, strings.Title(tag), tag))
+			if !existingFuncs[validationFuncName(tag)] {
+				existingFuncs[validationFuncName(tag)] = true
+				uniqueTags = append(uniqueTags, tag)
+			}
+		}
+		missingTags = uniqueTags
+		for _, tag := range missingTags {
+			missingFuncContent.WriteString(customValidationFunc(tag, paramTags[tag], options.CustomValidationCtx))
+		}
+
+		// 旧版本生成的文件可能缺少新增的内置验证函数或配置文件中新增的验证函数
+		otherFuncs := in.PackageFuncs
+		for _, builtIn := range validations {
+			if !existingFuncs[builtIn.Func] && !otherFuncs[builtIn.Func] {
+				missingFuncContent.WriteString(builtIn.Code)
+			}
+		}
+
+		// 独立验证包中后续启用的ValidateJSON函数
+		if validatorImport != "" && options.GenerateJSONMethod && !existingFuncs["ValidateJSON"] {
+			missingFuncContent.WriteString(ValidatorPackageValidateJSONFunc)
+		}
+
+		// 新标记的结构体级别验证方法
+		for _, structName := range structLevels {
+			if !existingFuncs[structName+"StructLevel"] {
+				missingFuncContent.WriteString(structLevelCode(structName, options.PerStructValidator))
+			}
 		}
 
 		// 5. 替换原有的验证方法映射和init函数
@@ -484,24 +1531,74 @@ func ProcessTypesFile(genFlag bool, filePath string, options Options) (bool, err
 			// 如果已经有map格式了，替换它
 			newValidationContent = mapRegex.ReplaceAllString(validationContent, newMapContent.String())
 
-			// 移除validate变量的声明(如果存在)
-			validateVarPattern := `var validate = validator\.New\(\)\n*`
-			validateVarRegex := regexp.MustCompile(validateVarPattern)
-			newValidationContent = validateVarRegex.ReplaceAllString(newValidationContent, "")
+			// 移除validate变量的声明(如果存在)，不生成Validate方法时验证器变量声明在验证文件中
+			if !options.SkipMethodGeneration && validatorImport == "" {
+				validateVarPattern := `var validate = validator\.New\(\)\n*`
+				validateVarRegex := regexp.MustCompile(validateVarPattern)
+				newValidationContent = validateVarRegex.ReplaceAllString(newValidationContent, "")
+			}
+
+			// 旧版本生成的init直接注册验证方法，替换为通过sync.Once只注册一次的initValidate
+			newValidationContent = withValidateOnce(newValidationContent)
+
+			// 验证文件中包含需要实现的自定义验证方法，删除旧版本添加的生成代码标记
+			newValidationContent = strings.TrimPrefix(newValidationContent, GeneratedHeader)
 
-			// 添加缺失的验证函数到文件末尾
+			// 添加缺失的验证函数到文件末尾，旧版本生成的文件可能缺少新增内置验证函数使用的导入
 			if missingFuncContent.Len() > 0 {
 				newValidationContent = newValidationContent + "\n" + missingFuncContent.String()
+				for _, imp := range validationFuncImports {
+					if usesImport(missingFuncContent.String(), imp) && !strings.Contains(newValidationContent, strconv.Quote(imp)) {
+						newValidationContent = strings.Replace(newValidationContent, "import (\n", "import (\n\t"+strconv.Quote(imp)+"\n", 1)
+					}
+				}
 			}
 		} else {
 			// 如果是旧格式或者格式不匹配，创建一个全新的内容
+			// 保留用户修改过的内置验证函数
+			sources, err := funcSources(validationFilePath, []byte(validationContent))
+			if err != nil {
+				return nil, fmt.Errorf("解析现有验证文件失败: %w", err)
+			}
+			funcCode := validationFuncCode(validations, sources, otherFuncs)
+
+			// 保留旧文件中除init和内置验证函数外的所有函数，如手动注册的自定义验证函数，按函数名排序
+			builtInFuncs := make(map[string]bool)
+			for _, v := range validations {
+				builtInFuncs[v.Func] = true
+			}
+			var funcNames []string
+			for funcName := range sources {
+				if funcName != "init" && funcName != "initValidate" && !builtInFuncs[funcName] {
+					funcNames = append(funcNames, funcName)
+				}
+			}
+			sort.Strings(funcNames)
+			var keptFuncs strings.Builder
+			for _, funcName := range funcNames {
+				keptFuncs.WriteString(sources[funcName] + "\n\n")
+			}
+
 			var newFullContent strings.Builder
-			newFullContent.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+			newFullContent.WriteString(fmt.Sprintf("package %s\n\n", validationPackage))
 
-			// 添加导入
+			// 添加导入，旧文件中保留的函数使用的导入同样保留
 			newFullContent.WriteString("import (\n")
-			newFullContent.WriteString("\t\"regexp\"\n")
+			for _, imp := range validationFuncImports {
+				if usesImport(funcCode, imp) || usesImport(keptFuncs.String(), imp) {
+					newFullContent.WriteString("\t" + strconv.Quote(imp) + "\n")
+				}
+			}
+			newFullContent.WriteString("\t\"sync\"\n")
 			newFullContent.WriteString("\t" + ValidateImport + "\n")
+			for _, imp := range fileImports(validationFile) {
+				if slices.Contains(validationFuncImports, imp.Path) || imp.Path == "github.com/go-playground/validator/v10" {
+					continue
+				}
+				if strings.Contains(keptFuncs.String(), imp.usedName()+".") {
+					newFullContent.WriteString("\t" + imp.String() + "\n")
+				}
+			}
 			newFullContent.WriteString(")\n\n")
 
 			// 添加验证方法映射（不添加validator变量）
@@ -511,61 +1608,43 @@ func ProcessTypesFile(genFlag bool, filePath string, options Options) (bool, err
 			newFullContent.WriteString(ValidateInitFunc + "\n")
 
 			// 添加内置验证函数
-			newFullContent.WriteString(BuiltInValidationFunc + "\n")
-
-			// 提取所有自定义验证函数
-			customFuncPattern := `(?s)// 自定义验证方法:.*?return true\n\}`
-			customFuncRegex := regexp.MustCompile(customFuncPattern)
-			customFuncMatches := customFuncRegex.FindAllString(validationContent, -1)
-
-			// 按字母顺序整理自定义验证函数
-			type FuncInfo struct {
-				Tag  string
-				Code string
-			}
-			var funcInfos []FuncInfo
-
-			// 收集所有现有的函数
-			for _, funcCode := range customFuncMatches {
-				funcNameRegex := regexp.MustCompile(`func validate(\w+)\(`)
-				nameMatch := funcNameRegex.FindStringSubmatch(funcCode)
-				if len(nameMatch) > 1 {
-					funcName := nameMatch[1]
-					tag := strings.ToLower(funcName[:1]) + funcName[1:]
-					funcInfos = append(funcInfos, FuncInfo{Tag: tag, Code: funcCode})
-				}
-			}
+			newFullContent.WriteString(funcCode + "\n")
 
-			// 对函数按标签名排序
-			sort.Slice(funcInfos, func(i, j int) bool {
-				return funcInfos[i].Tag < funcInfos[j].Tag
-			})
-
-			// 添加所有排序后的函数
-			for _, funcInfo := range funcInfos {
-				newFullContent.WriteString(funcInfo.Code + "\n\n")
-			}
+			// 添加旧文件中的其他函数
+			newFullContent.WriteString(keptFuncs.String())
 
 			// 添加缺失的验证函数
 			for _, tag := range missingTags {
-				newFullContent.WriteString(fmt.Sprintf(CustomValidationFuncTemplate, tag, strings.Title(tag), tag))
+				newFullContent.WriteString(customValidationFunc(tag, paramTags[tag], options.CustomValidationCtx))
 			}
 
 			newValidationContent = newFullContent.String()
 		}
 
+		// 确保存在结构体专属验证器
+		if options.PerStructValidator && !strings.Contains(newValidationContent, "func structValidator(") {
+			for _, imp := range []string{`"sync"`, `"github.com/go-playground/locales"`, `ut "github.com/go-playground/universal-translator"`} {
+				if !strings.Contains(newValidationContent, imp) {
+					newValidationContent = strings.Replace(newValidationContent, "import (\n", "import (\n\t"+imp+"\n", 1)
+				}
+			}
+			newValidationContent = newValidationContent + "\n" + PerStructValidatorFunc
+		}
+
 		// 6. 格式化并写入文件
-		formatted, err := format.Source([]byte(newValidationContent))
+		formatted, err := formatSource([]byte(newValidationContent), options)
 		if err != nil {
-			return false, fmt.Errorf("格式化更新的验证文件代码失败: %w", err)
+			return nil, fmt.Errorf("格式化更新的验证文件代码失败: %w", err)
 		}
 
-		if err := os.WriteFile(validationFilePath, formatted, 0644); err != nil {
-			return false, fmt.Errorf("写入更新的验证文件失败: %w", err)
-		}
+		result.ValidationFile = formatted
+	}
 
-		if options.DebugMode {
-			fmt.Printf("成功更新验证文件: %s\n", validationFilePath)
+	// 使用的验证规则别名同样注册翻译，validator的默认翻译按别名查找翻译文本，无法翻译别名中验证失败的验证器
+	translationTags := maps.Clone(customTags)
+	for alias, used := range usedAliases {
+		if used {
+			translationTags[alias] = true
 		}
 	}
 
@@ -574,36 +1653,64 @@ func ProcessTypesFile(genFlag bool, filePath string, options Options) (bool, err
 		var translatorFileContent strings.Builder
 
 		// 如果翻译器文件不存在，创建新文件
-		if !translatorExists {
+		if !translatorExists && tr.shared() {
+			// 共享翻译器包只生成一次，各types包通过Register注册
+			formatted, err := formatSource([]byte(sharedTranslatorFile(tr.pkg(), langs, validations, translationTags, enumTags, options.Bilingual)), options)
+			if err != nil {
+				return nil, fmt.Errorf("格式化翻译器文件代码失败: %w", err)
+			}
+			result.TranslatorFile = formatted
+		} else if !translatorExists {
+			translatorFileContent.WriteString(GeneratedHeader)
 			translatorFileContent.WriteString(fmt.Sprintf("package %s\n\n", packageName))
 
 			// 添加导入
 			translatorFileContent.WriteString("import (\n")
 			translatorFileContent.WriteString("\t\"errors\"\n")
+			translatorFileContent.WriteString("\t\"reflect\"\n")
 			translatorFileContent.WriteString("\t\"strings\"\n")
+			if options.LazyTranslator {
+				translatorFileContent.WriteString("\t\"sync\"\n")
+			}
 			translatorFileContent.WriteString("\t\"github.com/go-playground/validator/v10\"\n")
-			translatorFileContent.WriteString(TranslatorImports)
+			translatorFileContent.WriteString(translatorImports(langs))
 			translatorFileContent.WriteString(")\n\n")
 
 			// 添加翻译器变量
 			translatorFileContent.WriteString("var (\n")
 			translatorFileContent.WriteString("\tuni      *ut.UniversalTranslator\n")
 			translatorFileContent.WriteString("\ttrans    ut.Translator\n")
+			if options.LazyTranslator {
+				translatorFileContent.WriteString("\t// 保证翻译器只初始化一次\n")
+				translatorFileContent.WriteString("\ttranslatorOnce sync.Once\n")
+			}
 			translatorFileContent.WriteString(")\n\n")
 
 			// 添加翻译器初始化函数
-			translatorFileContent.WriteString("// 初始化翻译器\n")
-			translatorFileContent.WriteString("func init() {\n")
-			translatorFileContent.WriteString("\t// 初始化翻译器\n")
-			translatorFileContent.WriteString("\tenLocale := en.New()\n")
-			translatorFileContent.WriteString("\tzhLocale := zh.New()\n")
-			translatorFileContent.WriteString("\tuni = ut.New(enLocale, zhLocale)\n\n")
-			translatorFileContent.WriteString("\ttrans, _ = uni.GetTranslator(\"zh\")\n")
-			translatorFileContent.WriteString("\t// 注册默认翻译\n")
-			translatorFileContent.WriteString("\t_ = zhTrans.RegisterDefaultTranslations(validate, trans)\n\n")
-			translatorFileContent.WriteString("\t// 注册自定义翻译\n")
-			translatorFileContent.WriteString("\tregisterCustomTranslations(validate, trans)\n")
-			translatorFileContent.WriteString("}\n\n")
+			var initFunc string
+			if len(langs) > 1 {
+				// 按字母顺序排序自定义标签，确保生成顺序一致
+				initFunc = multiTranslatorInit(langs, validations, sortedTags(translationTags), enumTags)
+			} else {
+				initFunc = fmt.Sprintf(TranslatorInitFunc, translatorLocales(langs), lang, TranslatorTagNameFunc)
+			}
+			// translator.go的init()先于validation.go的init()执行，先注册验证方法，不依赖init()的执行顺序
+			validationCode := validationFileContent.String()
+			if validationExists {
+				validationCode = string(result.ValidationFile)
+			}
+			if strings.Contains(validationCode, "func initValidate()") {
+				initFunc = strings.Replace(initFunc, "func init() {\n", "func init() {\n\t// 先在验证器上注册验证方法，不依赖各文件init()的执行顺序\n\tinitValidate()\n\n", 1)
+			}
+			// 结构体专属的验证器同样需要注册字段名和翻译，翻译器统一包装为sharedTranslator
+			if options.PerStructValidator {
+				initFunc = strings.TrimSuffix(initFunc, "}\n") + translatorStructSetup(langs, validations, sortedTags(translationTags), enumTags) + "}\n"
+				initFunc = sharedTranslatorRegex.ReplaceAllString(initFunc, "$0${1}${2} = sharedTranslator{${2}}\n")
+			}
+			if options.LazyTranslator {
+				initFunc = lazyTranslatorInit(initFunc)
+			}
+			translatorFileContent.WriteString(initFunc + "\n")
 
 			// 添加错误翻译函数
 			translatorFileContent.WriteString("// Translate 翻译验证错误\n")
@@ -617,972 +1724,657 @@ func ProcessTypesFile(genFlag bool, filePath string, options Options) (bool, err
 			translatorFileContent.WriteString("\t}\n\n")
 			translatorFileContent.WriteString("\tvar errMsgs []string\n")
 			translatorFileContent.WriteString("\tfor _, e := range errs {\n")
-			translatorFileContent.WriteString("\t\ttranslatedErr := e.Translate(trans)\n")
+			translatorFileContent.WriteString(fmt.Sprintf("\t\ttranslatedErr := %s\n", tr.fieldExpr("e")))
 			translatorFileContent.WriteString("\t\terrMsgs = append(errMsgs, translatedErr)\n")
 			translatorFileContent.WriteString("\t}\n")
 			translatorFileContent.WriteString("\t// TODO 可以自定义错误类型\n")
 			translatorFileContent.WriteString("\treturn errors.New(strings.Join(errMsgs, \", \"))\n")
-			translatorFileContent.WriteString("}\n\n")
+			translatorFileContent.WriteString("}\n")
+			translatorFileContent.WriteString(GetValidateErrorMsgFunc)
 
-			// 添加自定义翻译注册函数
-			translatorFileContent.WriteString("// 注册自定义翻译\n")
-			translatorFileContent.WriteString("func registerCustomTranslations(validate *validator.Validate, trans ut.Translator) {\n")
-			translatorFileContent.WriteString("\t// 内置自定义验证器的翻译\n")
-			translatorFileContent.WriteString("\t_ = trans.Add(\"mobile\", \"{0}手机号码格式不正确\", true)\n")
-			translatorFileContent.WriteString("\t_ = validate.RegisterTranslation(\"mobile\", trans, func(ut ut.Translator) error {\n")
-			translatorFileContent.WriteString("\t\treturn nil\n")
-			translatorFileContent.WriteString("\t}, func(ut ut.Translator, fe validator.FieldError) string {\n")
-			translatorFileContent.WriteString("\t\tt, _ := ut.T(\"mobile\", fe.Field())\n")
-			translatorFileContent.WriteString("\t\treturn t\n")
-			translatorFileContent.WriteString("\t})\n\n")
-			translatorFileContent.WriteString("\t_ = trans.Add(\"idcard\", \"{0}身份证号码格式不正确\", true)\n")
-			translatorFileContent.WriteString("\t_ = validate.RegisterTranslation(\"idcard\", trans, func(ut ut.Translator) error {\n")
-			translatorFileContent.WriteString("\t\treturn nil\n")
-			translatorFileContent.WriteString("\t}, func(ut ut.Translator, fe validator.FieldError) string {\n")
-			translatorFileContent.WriteString("\t\tt, _ := ut.T(\"idcard\", fe.Field())\n")
-			translatorFileContent.WriteString("\t\treturn t\n")
-			translatorFileContent.WriteString("\t})\n")
-
-			// 为自定义标签添加初始翻译
-			for tag := range customTags {
-				if !isBuiltInValidator(tag) {
-					// 为新标签生成默认翻译文本
-					var description string
-					switch tag {
-					case "uuid":
-						description = "格式不正确"
-					case "datetime":
-						description = "日期格式不正确"
-					default:
-						description = "格式不符合要求"
-					}
+			// 字段通过msg标签自定义的错误信息
+			translatorFileContent.WriteString(FieldMessageFuncs + "\n")
 
-					translatorFileContent.WriteString(fmt.Sprintf("\n\t_ = trans.Add(\"%s\", \"{0}%s\", true)\n", tag, description))
-					translatorFileContent.WriteString(fmt.Sprintf("\t_ = validate.RegisterTranslation(\"%s\", trans, func(ut ut.Translator) error {\n", tag))
-					translatorFileContent.WriteString("\t\treturn nil\n")
-					translatorFileContent.WriteString("\t}, func(ut ut.Translator, fe validator.FieldError) string {\n")
-					translatorFileContent.WriteString(fmt.Sprintf("\t\tt, _ := ut.T(\"%s\", fe.Field())\n", tag))
-					translatorFileContent.WriteString("\t\treturn t\n")
-					translatorFileContent.WriteString("\t})\n")
+			// 多语言时添加按语言翻译的函数
+			if len(langs) > 1 {
+				translateWith := TranslateWithFunc
+				if options.PerStructValidator {
+					translateWith = strings.Replace(translateWith, "\t\tt = trans\n\t}\n", "\t\tt = trans\n\t} else {\n\t\tt = sharedTranslator{t}\n\t}\n", 1)
 				}
+				if options.LazyTranslator {
+					translateWith = lazyTranslateWith(translateWith)
+				}
+				translatorFileContent.WriteString(translateWith + "\n")
+			}
+			if options.Bilingual {
+				translatorFileContent.WriteString(translateBilingualFunc(langs, options.PerStructValidator, options.LazyTranslator) + "\n")
 			}
 
-			translatorFileContent.WriteString("}\n")
+			// 添加自定义翻译注册函数
+			translatorFileContent.WriteString(customTranslationsFunc(validations, translationTags, enumTags, lang))
 
 			// 格式化并写入翻译器文件
-			formatted, err := format.Source([]byte(translatorFileContent.String()))
+			formatted, err := formatSource([]byte(translatorFileContent.String()), options)
 			if err != nil {
-				return false, fmt.Errorf("格式化翻译器文件代码失败: %w", err)
+				return nil, fmt.Errorf("格式化翻译器文件代码失败: %w", err)
 			}
 
-			if err := os.WriteFile(translatorFilePath, formatted, 0644); err != nil {
-				return false, fmt.Errorf("写入翻译器文件失败: %w", err)
-			}
-
-			if options.DebugMode {
-				fmt.Printf("成功创建翻译器文件: %s\n", translatorFilePath)
-			}
+			result.TranslatorFile = formatted
 		} else {
 			// 如果翻译器文件已存在，追加新的自定义标签翻译
 			// 读取现有的翻译器文件
-			translatorBytes, err := os.ReadFile(translatorFilePath)
-			if err != nil {
-				return false, fmt.Errorf("读取现有翻译器文件失败: %w", err)
-			}
+			translatorBytes := in.Translator
 
 			translatorContent := string(translatorBytes)
 
 			// 提取已存在的翻译
-			existingTranslations := make(map[string]bool)
-			transRegex := regexp.MustCompile(`RegisterTranslation\("([^"]+)"`)
-			transMatches := transRegex.FindAllStringSubmatch(translatorContent, -1)
-
-			for _, match := range transMatches {
-				if len(match) > 1 {
-					existingTranslations[match[1]] = true
-				}
+			translatorFile, err := parseExistingFile(translatorFilePath, translatorBytes)
+			if err != nil {
+				return nil, fmt.Errorf("解析现有翻译器文件失败: %w", err)
 			}
+			existingTranslations := registeredTranslationTags(translatorFile)
 
-			if options.DebugMode {
-				fmt.Println("现有的翻译标签:", existingTranslations)
-				fmt.Println("自定义标签:", customTags)
-			}
+			options.debugf("现有的翻译标签: %v", existingTranslations)
+			options.debugf("自定义标签: %v", translationTags)
 
 			// 检查有没有新的自定义标签需要添加翻译
+			// 按字母顺序追加，重复执行插件时新增翻译的顺序保持一致
 			var newTranslations strings.Builder
-			for tag := range customTags {
-				if options.DebugMode {
-					fmt.Printf("检查标签 %s: 存在于现有翻译=%v, 是内置标签=%v\n",
-						tag, existingTranslations[tag], isBuiltInValidator(tag))
-				}
+			for _, tag := range sortedTags(translationTags) {
+				options.debugf("检查标签 %s: 存在于现有翻译=%v, 是内置标签=%v",
+					tag, existingTranslations[tag], isBuiltInValidator(tag))
 
 				// 仅为非内置标签且未翻译的标签添加翻译
 				if !existingTranslations[tag] && !isBuiltInValidator(tag) {
-					// 为新标签生成默认翻译文本（可以根据标签名生成合理的中文描述）
-					var description string
-					switch tag {
-					case "uuid":
-						description = "格式不正确"
-					case "datetime", "date", "time":
-						description = "日期格式不正确"
-					default:
-						description = "格式不符合要求"
-					}
+					// 为新标签生成默认翻译文本（根据标签名和翻译语言生成合理的描述）
+					description := customTagMessage(lang, tag, enumTags)
 
-					if options.DebugMode {
-						fmt.Printf("添加标签 %s 的翻译\n", tag)
-					}
+					options.debugf("添加标签 %s 的翻译", tag)
 
 					newTranslations.WriteString(fmt.Sprintf(CustomTranslationTemplate, tag, description, tag, tag))
 				}
 			}
 
-			// 如果有新的翻译，追加到registerCustomTranslations函数末尾
-			if newTranslations.Len() > 0 {
-				// 找到registerCustomTranslations函数
-				funcStartRegex := regexp.MustCompile(`func registerCustomTranslations\([^)]+\) {`)
-				funcStartMatch := funcStartRegex.FindStringIndex(translatorContent)
-
-				if funcStartMatch == nil {
-					return false, fmt.Errorf("无法找到registerCustomTranslations函数")
+			// 配置文件中新增的验证器及旧版本生成的文件中缺少的插件内置验证器同样需要添加翻译
+			for _, v := range validations {
+				if !existingTranslations[v.Tag] {
+					newTranslations.WriteString(fmt.Sprintf(CustomTranslationTemplate, v.Tag, validationMessage(v, lang), v.Tag, v.Tag))
 				}
+			}
 
-				// 找到函数的开始位置
-				funcStart := funcStartMatch[1] // 使用函数声明的结束位置
-
-				// 计算函数体的大括号配对
-				braceCount := 1
-				funcEnd := -1
-
-				for i := funcStart; i < len(translatorContent); i++ {
-					if translatorContent[i] == '{' {
-						braceCount++
-					} else if translatorContent[i] == '}' {
-						braceCount--
-						if braceCount == 0 {
-							funcEnd = i
-							break
-						}
-					}
+			// 如果有新的翻译，追加到registerCustomTranslations函数末尾
+			if newTranslations.Len() > 0 {
+				// 通过AST找到registerCustomTranslations函数体结束的位置
+				funcEnd, ok, err := funcBodyEnd(translatorFilePath, translatorBytes, "registerCustomTranslations")
+				if err != nil {
+					return nil, fmt.Errorf("解析现有翻译器文件失败: %w", err)
 				}
-
-				if funcEnd == -1 {
-					return false, fmt.Errorf("无法找到registerCustomTranslations函数的结束位置")
+				if !ok {
+					return nil, fmt.Errorf("无法找到registerCustomTranslations函数")
 				}
 
 				// 在函数结束位置的大括号前添加新翻译
 				modifiedContent := translatorContent[:funcEnd] + newTranslations.String() + translatorContent[funcEnd:]
 
-				if options.DebugMode {
-					fmt.Printf("修改后的翻译器内容:\n%s\n", modifiedContent)
-				}
+				options.debugf("修改后的翻译器内容:\n%s", modifiedContent)
 
-				// 尝试格式化代码
-				formatted, err := format.Source([]byte(modifiedContent))
+				formatted, err := formatSource([]byte(modifiedContent), options)
 				if err != nil {
-					// 如果格式化失败，尝试在函数的适当位置添加翻译
-					if options.DebugMode {
-						fmt.Printf("格式化失败: %v\n", err)
-					}
-
-					// 寻找最后一个翻译注册的位置
-					lastRegisterPos := strings.LastIndex(translatorContent, "RegisterTranslation(")
-					if lastRegisterPos == -1 {
-						return false, fmt.Errorf("无法找到适合添加翻译的位置")
-					}
-
-					// 找到此注册的结束位置（下一个}）
-					endRegisterPos := strings.Index(translatorContent[lastRegisterPos:], "})") + lastRegisterPos
-					if endRegisterPos == -1 {
-						return false, fmt.Errorf("无法找到适合添加翻译的位置")
-					}
-
-					// 在此位置后添加新翻译
-					endRegisterPos += 2 // 跳过})
-					modifiedContent = translatorContent[:endRegisterPos] + "\n" + newTranslations.String() + translatorContent[endRegisterPos:]
-
-					formatted, err = format.Source([]byte(modifiedContent))
-					if err != nil {
-						return false, fmt.Errorf("格式化翻译器代码失败: %w", err)
-					}
+					return nil, fmt.Errorf("格式化翻译器代码失败: %w", err)
 				}
 
 				// 写入更新后的文件
-				if err := os.WriteFile(translatorFilePath, formatted, 0644); err != nil {
-					return false, fmt.Errorf("写入更新的翻译器文件失败: %w", err)
-				}
+				result.TranslatorFile = formatted
+			} else {
+				options.debugf("没有需要添加翻译的新标签")
+			}
 
-				if options.DebugMode {
-					fmt.Printf("成功更新翻译器文件: %s\n", translatorFilePath)
+			// 旧版本生成的翻译器文件缺少按字段查找自定义错误信息的函数时追加到文件末尾
+			if !declaredFuncs(translatorFile)["translateField"] {
+				content := translatorBytes
+				if result.TranslatorFile != nil {
+					content = result.TranslatorFile
+				}
+				content = append(append([]byte(nil), content...), FieldMessageFuncs...)
+				fset := token.NewFileSet()
+				file, err := parser.ParseFile(fset, translatorFilePath, content, parser.ParseComments)
+				if err != nil {
+					return nil, fmt.Errorf("解析翻译器文件失败: %w", err)
+				}
+				if result.TranslatorFile, err = addImports(fset, file, []importSpec{{Path: "strings"}}); err != nil {
+					return nil, fmt.Errorf("格式化翻译器代码失败: %w", err)
 				}
-			} else if options.DebugMode {
-				fmt.Println("没有需要添加翻译的新标签")
 			}
-		}
-	}
-
-	// 为所有请求结构体生成验证方法
-	var methodsBuilder strings.Builder
 
-	// 检查是否需要添加验证器的导入
-	if !hasValidatorImport && len(reqStructs) > 0 {
-		// 找到最后一个导入
-		lastImportPos := -1
-		for i, decl := range f.Decls {
-			genDecl, ok := decl.(*ast.GenDecl)
-			if ok && genDecl.Tok == token.IMPORT {
-				lastImportPos = i
-			}
-		}
-
-		// 找到文件中的包声明之后的位置
-		fileContentStr := string(fileContent)
-		packageEndPos := findPackagePosition(fileContentStr)
-		if packageEndPos > 0 {
-			packageEndPos = packageEndPos + len("package "+f.Name.Name)
-
-			// 如果已经有导入部分
-			if lastImportPos >= 0 {
-				// 将验证器的导入添加到现有导入部分
-				// 实现比较复杂，这里简单处理为在末尾添加
-			} else {
-				// 在包声明之后添加导入
-				importStatement := `
-import (
-    "fmt"
-
-	"github.com/go-playground/validator/v10"
-)
-`
-				if !genFlag {
-					importStatement = `
-import (
-    "fmt"
-
-	"github.com/go-playground/validator/v10"
-	"github.com/go-playground/locales/zh"
-	ut "github.com/go-playground/universal-translator"
-	zhTranslations "github.com/go-playground/validator/v10/translations/zh"
-)
-`
+			// 旧版本生成的翻译器文件缺少GetValidateErrorMsg时追加到文件末尾，Translate返回string的旧文件不追加
+			if !declaredFuncs(translatorFile)["GetValidateErrorMsg"] && funcReturnsError(translatorFile, "Translate") {
+				content := translatorBytes
+				if result.TranslatorFile != nil {
+					content = result.TranslatorFile
 				}
+				content = append(append([]byte(nil), content...), GetValidateErrorMsgFunc...)
+				if result.TranslatorFile, err = formatSource(content, options); err != nil {
+					return nil, fmt.Errorf("格式化翻译器代码失败: %w", err)
+				}
+			}
 
-				// 插入导入语句
-				if options.DebugMode {
-					fmt.Println("添加验证器导入")
+			// 启用双语翻译时，缺少TranslateBilingual的多语言翻译器文件追加到文件末尾
+			if options.Bilingual && !declaredFuncs(translatorFile)["TranslateBilingual"] {
+				if !declaredFuncs(translatorFile)["TranslateWith"] {
+					return nil, fmt.Errorf("现有的翻译器文件%s不是多语言翻译器，启用--bilingual需要使用--force重新生成", translatorFilePath)
 				}
+				content := translatorBytes
+				if result.TranslatorFile != nil {
+					content = result.TranslatorFile
+				}
+				content = append(append([]byte(nil), content...), translateBilingualFunc(langs, options.PerStructValidator || tr.shared(), options.LazyTranslator)...)
+				if result.TranslatorFile, err = formatSource(content, options); err != nil {
+					return nil, fmt.Errorf("格式化翻译器代码失败: %w", err)
+				}
+			}
 
-				// 将导入添加到文件内容
-				fileContentStr = fileContentStr[:packageEndPos] + importStatement + fileContentStr[packageEndPos:]
-				fileContent = []byte(fileContentStr)
+			// 旧版本生成的自定义翻译只传入字段名，补充标签参数以支持翻译文本中的{1}
+			content := translatorBytes
+			if result.TranslatorFile != nil {
+				content = result.TranslatorFile
+			}
+			if translationParamRegex.Match(content) {
+				result.TranslatorFile = translationParamRegex.ReplaceAll(content, []byte("ut.T($1, fe.Field(), fe.Param())"))
 			}
 
-		}
+			// 旧版本增量添加的翻译不覆盖已有的翻译文本，与完整生成时的行为不一致，统一改为覆盖
+			content = translatorBytes
+			if result.TranslatorFile != nil {
+				content = result.TranslatorFile
+			}
+			if translationOverrideRegex.Match(content) {
+				result.TranslatorFile = translationOverrideRegex.ReplaceAll(content, []byte("${1}true)"))
+			}
 
-		// 添加验证器变量的声明
-		// 如果之前已经生成过定义变量，则跳过
-		if !genFlag {
-			validateVarStatement := fmt.Sprintf(`
-    var zhTrans =  zh.New()
-	var trans, _ = ut.New(zhTrans, zhTrans).GetTranslator("zh")
-	%s
-	// 注册中文翻译
-func init(){
-    zhTranslations.RegisterDefaultTranslations(validate, trans)
-}
-`, ValidateVar)
-			fileContentStr = string(fileContent) + validateVarStatement
-			genDefineValidate = true
+			// 启用清理时删除包内已没有结构体使用的自定义标签的翻译，共享翻译器包被多个包使用，不清理
+			if options.Prune && !tr.shared() {
+				content := translatorBytes
+				if result.TranslatorFile != nil {
+					content = result.TranslatorFile
+				}
+				pruned, err := pruneTranslations(translatorFilePath, content, func(tag string) bool {
+					return !knownTags[tag] && !isBuiltInValidator(tag) && !translationTags[tag] && !in.PackageTags[tag]
+				})
+				if err != nil {
+					return nil, fmt.Errorf("清理翻译器文件失败: %w", err)
+				}
+				if pruned != nil {
+					result.TranslatorFile = pruned
+				}
+			}
 		}
-		fileContent = []byte(fileContentStr)
 	}
 
+	// 为所有请求结构体生成验证方法
+	var methodsBuilder strings.Builder
+
 	// 根据是否启用翻译器来生成不同的Validate方法
 	for _, structName := range reqStructs {
-		// 检查是否已经存在该结构体的Validate方法
-		if !strings.Contains(string(fileContent), "func (r *"+structName+") Validate()") {
-			//if options.EnableTranslator {
-			//	// 使用翻译器版本的验证方法
-			//	methodsBuilder.WriteString(fmt.Sprintf("\nfunc (r *%s) Validate() error {\n\terr := validate.Struct(r)\n\treturn TranslateError(err)\n}\n", structName))
-			//} else {
-			// 使用普通版本的验证方法
-			methodsBuilder.WriteString(fmt.Sprintf(`
-func (req *%s) Validate() error {
-    err := validate.Struct(req)
-	if err != nil {
-		es, ok := err.(validator.ValidationErrors)
-		if !ok {
-			return err
+		// 不生成Validate方法时只根据结构体收集的标签生成验证文件和翻译器文件
+		if options.SkipMethodGeneration {
+			break
 		}
-		for _, err := range es {
-			return fmt.Errorf(err.Translate(trans))
+		//if options.EnableTranslator {
+		//	// 使用翻译器版本的验证方法
+		//	methodsBuilder.WriteString(fmt.Sprintf("\nfunc (r *%s) Validate() error {\n\terr := validate.Struct(r)\n\treturn TranslateError(err)\n}\n", structName))
+		//} else {
+		// 使用普通版本的验证方法，启用结构体专属验证器时使用该结构体的验证器
+		validatorExpr := "validate"
+		if options.PerStructValidator {
+			validatorExpr = structValidatorCall(structName, collectStructTags(structName, structTags, structRefs))
 		}
-	}
-	return err
-}
-`, structName))
-			//}
+		var method string
+		switch {
+		case validatorImport != "":
+			// 启用独立验证包时由验证包验证并翻译
+			method = fmt.Sprintf(ValidatorPackageValidateMethod, structName, receiver, path.Base(validatorImport))
+		case options.GenerateErrorCodes:
+			// 启用错误码时返回聚合的验证错误
+			method = fmt.Sprintf(ErrorCodeValidateMethod, structName, validatorExpr, receiver)
+		case options.GenerateErrorHandler:
+			// 启用错误处理函数时返回原始的验证错误，由ValidationErrorHandler翻译
+			method = fmt.Sprintf(ErrorHandlerValidateMethod, structName, validatorExpr, receiver)
+		default:
+			// 启用翻译器时优先使用字段通过msg标签自定义的错误信息
+			translateExpr := "err.Translate(trans)"
+			if options.EnableTranslator {
+				translateExpr = tr.fieldExpr("err")
+			}
+			method = fmt.Sprintf(ValidateMethod, structName, validatorExpr, translateExpr, receiver)
 		}
-	}
-
-	// 将方法添加到types.go文件末尾
-	if methodsBuilder.Len() > 0 {
-		modifiedContent := string(fileContent) + methodsBuilder.String()
-
-		// 格式化代码
-		formatted, err := format.Source([]byte(modifiedContent))
-		if err != nil {
-			return false, fmt.Errorf("格式化代码失败: %w", err)
+		//}
+		mustMethod := fmt.Sprintf(MustValidateMethod, structName, receiver)
+		var jsonMethod, fieldsMethod string
+		if options.GenerateJSONMethod {
+			jsonMethod = validateJSONMethod(structName, validatorExpr, tr, receiver, validatorImport)
 		}
-
-		// 写回文件
-		if err := os.WriteFile(filePath, formatted, 0644); err != nil {
-			return false, fmt.Errorf("写入文件失败: %w", err)
+		if options.GenerateFieldsMethod {
+			translateExpr := "err.Translate(trans)"
+			if options.EnableTranslator {
+				translateExpr = tr.fieldExpr("err")
+			}
+			fieldsMethod = validateFieldsMethod(structName, validatorExpr, translateExpr, receiver, validatorImport)
 		}
-
-		if options.DebugMode {
-			fmt.Printf("成功添加验证方法到 %s\n", filePath)
+		if options.ValueReceiver {
+			method = valueReceiverMethods(method, structName, receiver)
+			mustMethod = valueReceiverMethods(mustMethod, structName, receiver)
+			jsonMethod = valueReceiverMethods(jsonMethod, structName, receiver)
+			fieldsMethod = valueReceiverMethods(fieldsMethod, structName, receiver)
 		}
-	}
 
-	// 如果需要创建或更新验证文件
-	if !validationExists {
-		// 格式化验证文件内容
-		formatted, err := format.Source([]byte(validationFileContent.String()))
-		if err != nil {
-			return false, fmt.Errorf("格式化验证文件代码失败: %w", err)
+		// 检查是否已经存在该结构体的Validate方法
+		if !validateReceivers[structName] {
+			methodsBuilder.WriteString(method)
 		}
-
-		// 写入验证文件
-		if err := os.WriteFile(validationFilePath, formatted, 0644); err != nil {
-			return false, fmt.Errorf("写入验证文件失败: %w", err)
+		// 同时生成使用StructCtx的ValidateCtx方法，自定义验证方法可以读取请求上下文中的值
+		if options.GenerateContextMethod && !validateCtxReceivers[structName] {
+			methodsBuilder.WriteString(contextMethod(method, receiver))
 		}
-
-		if options.DebugMode {
-			fmt.Printf("成功创建验证文件: %s\n", validationFilePath)
+		// 同时生成验证失败时panic的MustValidate方法
+		if options.GenerateMust && !mustValidateReceivers[structName] {
+			methodsBuilder.WriteString(mustMethod)
+		}
+		// 同时生成返回各字段错误信息的ValidateJSON方法
+		if options.GenerateJSONMethod && !validateJSONReceivers[structName] {
+			methodsBuilder.WriteString(jsonMethod)
+		}
+		// 同时生成返回所有字段验证错误及其完整路径的ValidateFields方法
+		if options.GenerateFieldsMethod && !validateFieldsReceivers[structName] {
+			methodsBuilder.WriteString(fieldsMethod)
+		}
+		// 启用翻译器时随Validate方法注册字段通过msg标签自定义的错误信息
+		if options.EnableTranslator && !validateReceivers[structName] {
+			methodsBuilder.WriteString(fieldMessageCode(structName, localStructs, tr.registerMessagesFunc()))
 		}
 	}
 
-	return genDefineValidate, nil
-}
+	// 检查是否需要添加验证器的导入
+	// 首次生成或需要追加方法时，根据生成的代码合并需要的导入
+	if len(reqStructs) > 0 && !options.SkipMethodGeneration && (!validateDeclared || methodsBuilder.Len() > 0) {
+		methods := methodsBuilder.String()
+		// 需要声明验证器变量时生成声明，独立验证包中已声明验证器变量
+		var validateVarStatement string
+		if !validateDeclared && validatorImport == "" {
+			validateVarStatement = validatorVarDecl(lang, options, tr)
+		}
+		// 启用翻译器时由translator.go负责翻译器的声明
+		// 只在生成的方法或验证器变量的声明引用验证器包时导入，避免同一包中的其他types文件及共享验证器变量导入未使用的包
+		imports := typesImports(lang, typesImportOptions{
+			Validator:     strings.Contains(methods, "validator.") || strings.Contains(validateVarStatement, "validator."),
+			ValidatorName: validatorName,
+			Translations:  validateVarStatement != "" && !options.EnableTranslator,
+			// 声明验证器变量时引用共享验证器的库包
+			SharedValidator: options.SharedValidator && validateVarStatement != "",
+			Fmt:             strings.Contains(methods, "fmt."),
+			Context:         strings.Contains(methods, "context."),
+		})
+		// 引用共享翻译器包的方法及验证器变量的注册需要导入该包
+		if tr.shared() && (strings.Contains(methods, tr.pkg()+".") || strings.Contains(validateVarStatement, tr.pkg()+".")) {
+			imports = append(imports, importSpec{Path: tr.Import})
+		}
+		// 引用独立验证包的方法需要导入该包
+		if validatorImport != "" && strings.Contains(methods, path.Base(validatorImport)+".") {
+			imports = append(imports, importSpec{Path: validatorImport})
+		}
 
-// 从结构体标签中提取validate标签内容
-func extractValidateTag(tag string) string {
-	re := regexp.MustCompile(`validate:"([^"]*)"`)
-	matches := re.FindStringSubmatch(tag)
-	if len(matches) > 1 {
-		return matches[1]
-	}
-	return ""
-}
+		options.debugf("添加验证器导入")
 
-// 判断是否是内置验证器
-func isBuiltInValidator(validator string) bool {
-	builtInValidators := map[string]bool{
-		"required":  true,
-		"mobile":    true,
-		"idcard":    true,
-		"email":     true,
-		"url":       true,
-		"ip":        true,
-		"len":       true,
-		"min":       true,
-		"max":       true,
-		"eq":        true,
-		"ne":        true,
-		"lt":        true,
-		"lte":       true,
-		"gt":        true,
-		"gte":       true,
-		"oneof":     true,
-		"numeric":   true,
-		"alpha":     true,
-		"alphanum":  true,
-		"omitempty": true, // 这实际上是JSON标签的一部分，不是验证标签
-	}
-
-	// 检查是否是带参数的内置验证器，如min=10
-	parts := strings.Split(validator, "=")
-	if len(parts) > 1 {
-		return builtInValidators[parts[0]]
-	}
-
-	return builtInValidators[validator]
-}
+		// 将导入合并到文件已有的导入分组中
+		fileContent, err = addImports(fset, f, imports)
+		if err != nil {
+			return nil, fmt.Errorf("添加导入失败: %w", err)
+		}
 
-// findPackagePosition 查找package关键字在文件中的位置
-func findPackagePosition(content string) int {
-	// 使用正则表达式查找package关键字
-	re := regexp.MustCompile(`(?m)^package\s+\w+`)
-	loc := re.FindStringIndex(content)
-	if loc == nil {
-		return -1
+		// 添加验证器变量的声明
+		if validateVarStatement != "" {
+			fileContent = []byte(string(fileContent) + renameValidatorPkg(validateVarStatement, validatorName))
+			result.DefinedValidate = true
+		}
 	}
-	return loc[0]
-}
 
-// ProcessTranslator 处理翻译器文件
-func ProcessTranslator(filePath string, regFuncFromTypeStruct string, customTags map[string]bool, debugMode bool) error {
-	// 获取类型文件所在的目录
-	dir := filepath.Dir(filePath)
-	translatorFilePath := filepath.Join(dir, "translator.go")
+	// 将方法添加到types.go文件末尾，按注释添加了标签或只声明了验证器变量（结构体都已有Validate方法）时同样需要写回
+	if methodsBuilder.Len() > 0 || tagged != nil || result.DefinedValidate {
+		modifiedContent := string(fileContent) + renameValidatorPkg(methodsBuilder.String(), validatorName)
 
-	// 读取现有的翻译器文件
-	var translatorContent []byte
-	var err error
-	translatorExists := false
-	if _, err = os.Stat(translatorFilePath); err == nil {
-		translatorExists = true
-		translatorContent, err = os.ReadFile(translatorFilePath)
+		// 格式化代码
+		formatted, err := formatSource([]byte(modifiedContent), options)
 		if err != nil {
-			return err
+			return nil, fmt.Errorf("格式化代码失败: %w", err)
 		}
-	}
 
-	// 创建或更新翻译器文件
-	if !translatorExists {
-		// 如果翻译器文件不存在，创建一个新的
-		translatorCode := generateNewTranslatorCode(customTags)
-		return os.WriteFile(translatorFilePath, []byte(translatorCode), 0644)
+		// 写回文件
+		result.TypesFile = formatted
 	}
 
-	// 更新现有的翻译器文件
-	var newTranslations strings.Builder
-
-	// 对于所有自定义标签，添加新的翻译
-	for tag := range customTags {
-		// 检查此标签是否已有翻译，以及是否为内置验证器
-		if debugMode {
-			fmt.Printf("[Debug] 检查自定义标签: %s\n", tag)
+	// 如果需要创建或更新验证文件
+	if !validationExists {
+		// 格式化验证文件内容
+		formatted, err := formatSource([]byte(validationFileContent.String()), options)
+		if err != nil {
+			return nil, fmt.Errorf("格式化验证文件代码失败: %w", err)
+		}
 
-			// 特别检查此标签是否已有翻译
-			hasTranslation := bytes.Contains(translatorContent, []byte(fmt.Sprintf("RegisterTranslation(\"%s\"", tag)))
-			fmt.Printf("[Debug] 标签 %s 已有翻译: %v\n", tag, hasTranslation)
+		// 写入验证文件
+		result.ValidationFile = formatted
+	}
 
-			// 检查是否为内置验证器
-			isBuiltIn := isBuiltInValidator(tag)
-			fmt.Printf("[Debug] 标签 %s 是内置验证器: %v\n", tag, isBuiltIn)
+	// ValidateFields方法返回的FieldError类型声明在验证文件中，独立验证包同时声明ValidateFields函数
+	if options.GenerateFieldsMethod && len(reqStructs) > 0 {
+		content := result.ValidationFile
+		if content == nil {
+			content = in.Validation
 		}
-
-		if !bytes.Contains(translatorContent, []byte(fmt.Sprintf("RegisterTranslation(\"%s\"", tag))) && !isBuiltInValidator(tag) {
-			tagDesc := getTagDescription(tag)
-			newTranslations.WriteString(fmt.Sprintf(`
-	// 注册 %s 验证器的翻译
-	v.RegisterTranslation("%s", trans, func(ut ut.Translator) error {
-		return ut.Add("%s.%s", "{0} %s", true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("%s.%s", fe.Field())
-		return t
-	})
-`, tag, tag, "validationErrors", tag, tagDesc, "validationErrors", tag))
-		}
-	}
-
-	// 如果有新的翻译，更新文件
-	if newTranslations.Len() > 0 {
-		// 寻找最后一个RegisterTranslation调用后的位置
-		lastRegisterPos := bytes.LastIndex(translatorContent, []byte("RegisterTranslation("))
-		if lastRegisterPos == -1 {
-			// 如果找不到RegisterTranslation调用，寻找init函数的结束位置
-			initEndPos := bytes.LastIndex(translatorContent, []byte("}"))
-			if initEndPos == -1 {
-				return fmt.Errorf("无法找到适合插入新翻译的位置")
-			}
-
-			// 添加新的翻译
-			updatedContent := append(translatorContent[:initEndPos], []byte(newTranslations.String())...)
-			updatedContent = append(updatedContent, translatorContent[initEndPos:]...)
-			return os.WriteFile(translatorFilePath, updatedContent, 0644)
-		}
-
-		// 找到此RegisterTranslation调用的结束位置
-		afterLastRegister := lastRegisterPos
-		braceCount := 0
-		for i := lastRegisterPos; i < len(translatorContent); i++ {
-			if translatorContent[i] == '{' {
-				braceCount++
-			} else if translatorContent[i] == '}' {
-				braceCount--
-				if braceCount < 0 && bytes.HasPrefix(translatorContent[i+1:], []byte("\n")) {
-					afterLastRegister = i + 1
-					break
-				}
+		translateExpr := "err.Translate(trans)"
+		if options.EnableTranslator {
+			translateExpr = "TranslateField(err)"
+		}
+		updated, err := withValidateFields(validationFilePath, content, validatorImport != "", translateExpr)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(updated, content) {
+			if result.ValidationFile, err = formatSource(updated, options); err != nil {
+				return nil, fmt.Errorf("格式化验证文件代码失败: %w", err)
 			}
 		}
-
-		// 插入新的翻译
-		updatedContent := append(translatorContent[:afterLastRegister], []byte(newTranslations.String())...)
-		updatedContent = append(updatedContent, translatorContent[afterLastRegister:]...)
-		return os.WriteFile(translatorFilePath, updatedContent, 0644)
-	}
-
-	return nil
-}
-
-// 获取标签的描述
-func getTagDescription(tag string) string {
-	switch tag {
-	case "mobile":
-		return "必须是有效的手机号码"
-	case "idcard":
-		return "必须是有效的身份证号码"
-	default:
-		// 为未知标签提供一个默认描述
-		return fmt.Sprintf("必须是有效的 %s 格式", tag)
-	}
-}
-
-// 为结构体添加验证方法
-func AddValidationMethodsToStructs(filePath string, options *Options) error {
-	// 读取文件
-	fileContent, err := os.ReadFile(filePath)
-	if err != nil {
-		return err
 	}
 
-	// 解析Go代码
-	fset := token.NewFileSet()
-	file, err := parser.ParseFile(fset, filePath, fileContent, parser.ParseComments)
-	if err != nil {
-		return err
-	}
-
-	// 检查包名
-	packageName := file.Name.Name
-
-	// 收集需要处理的结构体和自定义验证标签
-	var reqStructs []string
-	customTags := make(map[string]bool)
-	existingValidations := make(map[string]bool)
-
-	regFuncFromTypeStruct := ""
-
-	// 遍历AST，找到所有结构体
-	for _, decl := range file.Decls {
-		genDecl, ok := decl.(*ast.GenDecl)
-		if !ok || genDecl.Tok != token.TYPE {
-			continue
+	// 自定义验证方法通过RegisterValidationCtx注册时，验证文件中记录验证失败的错误信息，翻译器优先使用
+	if options.CustomValidationCtx && len(reqStructs) > 0 {
+		content := result.ValidationFile
+		if content == nil {
+			content = in.Validation
 		}
-
-		for _, spec := range genDecl.Specs {
-			typeSpec, ok := spec.(*ast.TypeSpec)
-			if !ok {
-				continue
-			}
-
-			structType, ok := typeSpec.Type.(*ast.StructType)
-			if !ok {
-				continue
-			}
-
-			// 检测是否有validate标签的字段
-			hasValidateTag := false
-			for _, field := range structType.Fields.List {
-				if field.Tag != nil && strings.Contains(field.Tag.Value, "validate:") {
-					hasValidateTag = true
-					break
-				}
+		updated, err := withValidationMessageFuncs(validationFilePath, content)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(updated, in.Validation) {
+			result.ValidationFile = updated
+		}
+		if options.EnableTranslator {
+			content := result.TranslatorFile
+			if content == nil {
+				content = in.Translator
 			}
-
-			// 如果结构体包含验证标签或是以Req结尾，则处理
-			if hasValidateTag || strings.HasSuffix(typeSpec.Name.Name, "Req") {
-				reqStructs = append(reqStructs, typeSpec.Name.Name)
-
-				// 分析结构体字段的验证标签
-				for _, field := range structType.Fields.List {
-					if field.Tag != nil {
-						tag := field.Tag.Value
-
-						// 提取验证标签
-						validateTag := extractValidateTag(tag)
-						if validateTag != "" {
-							// 分析验证标签中的自定义验证器
-							validators := strings.Split(validateTag, ",")
-							for _, v := range validators {
-								// 跳过空验证器
-								if v == "" {
-									continue
-								}
-
-								// 如果启用了自定义验证或翻译器，添加自定义标签
-								if (options.EnableCustomValidation || options.EnableTranslator) && !isBuiltInValidator(v) {
-									// 添加自定义验证标签
-									customTags[v] = true
-
-									// 如果启用了自定义验证，检查该验证器函数是否已存在
-									if options.EnableCustomValidation {
-										if bytes.Contains(fileContent, []byte(fmt.Sprintf("func validate%s", strings.Title(v)))) {
-											existingValidations[v] = true
-										}
-									}
-								}
-							}
-						}
-					}
-				}
+			if updated := withTranslatorValidationMessage(content); !bytes.Equal(updated, in.Translator) {
+				result.TranslatorFile = updated
 			}
 		}
+	}
 
-		// 检查是否已有Validate方法
-		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Name.Name == "Validate" {
-			if funcDecl.Recv != nil && len(funcDecl.Recv.List) > 0 {
-				if starExpr, ok := funcDecl.Recv.List[0].Type.(*ast.StarExpr); ok {
-					if ident, ok := starExpr.X.(*ast.Ident); ok {
-						regFuncFromTypeStruct = fmt.Sprintf("func (v *%s) Validate() error", ident.Name)
-					}
-				}
+	// 配置文件定义的验证规则别名在验证文件中通过RegisterAlias注册
+	if len(reqStructs) > 0 {
+		content := result.ValidationFile
+		if content == nil {
+			content = in.Validation
+		}
+		if updated := withRuleAliases(content, aliases, options.PerStructValidator); !bytes.Equal(updated, content) {
+			if result.ValidationFile, err = formatSource(updated, options); err != nil {
+				return nil, fmt.Errorf("格式化验证文件代码失败: %w", err)
 			}
 		}
 	}
 
-	// 如果没有结构体需要处理，直接返回
-	if len(reqStructs) == 0 {
-		return nil
-	}
-
-	// 生成import语句
-	var imports []string
-	imports = append(imports, `"github.com/go-playground/validator/v10"`)
-
-	// 添加validator包到imports
-	if !bytes.Contains(fileContent, []byte(`"github.com/go-playground/validator/v10"`)) {
-		// 查找import部分
-		importStart := bytes.Index(fileContent, []byte("import ("))
-		importEnd := -1
-		if importStart != -1 {
-			importEnd = bytes.Index(fileContent[importStart:], []byte(")"))
-			if importEnd != -1 {
-				importEnd += importStart
+	// 验证文件中生成以结构体封装的验证器，需要在生成Validator函数之前，已声明Validator类型时不再生成该函数
+	if options.StructStyle && len(reqStructs) > 0 {
+		content := result.ValidationFile
+		if content == nil {
+			content = in.Validation
+		}
+		updated, err := withStructValidator(validationFilePath, content, in.PackageFuncs)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(updated, content) {
+			if result.ValidationFile, err = formatSource(updated, options); err != nil {
+				return nil, fmt.Errorf("格式化验证文件代码失败: %w", err)
 			}
 		}
+	}
 
-		var newContent []byte
-		if importStart != -1 && importEnd != -1 {
-			// 添加到现有import
-			newContent = append(fileContent[:importEnd], []byte("\n\t\"github.com/go-playground/validator/v10\"")...)
-			newContent = append(newContent, fileContent[importEnd:]...)
-		} else {
-			// 创建新的import
-			importStmt := "\nimport (\n\t\"github.com/go-playground/validator/v10\"\n)\n"
-			packageEnd := bytes.Index(fileContent, []byte("package "+packageName)) + len("package "+packageName)
-			newLine := bytes.IndexByte(fileContent[packageEnd:], '\n')
-			if newLine != -1 {
-				packageEnd += newLine
+	// 验证文件中生成返回验证器的Validator函数，便于测试复用已注册验证标签的验证器
+	if len(reqStructs) > 0 {
+		content := result.ValidationFile
+		if content == nil {
+			content = in.Validation
+		}
+		updated, err := withValidatorAccessor(validationFilePath, content, in.PackageFuncs)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(updated, content) {
+			if result.ValidationFile, err = formatSource(updated, options); err != nil {
+				return nil, fmt.Errorf("格式化验证文件代码失败: %w", err)
 			}
-			newContent = append(fileContent[:packageEnd+1], []byte(importStmt)...)
-			newContent = append(newContent, fileContent[packageEnd+1:]...)
 		}
-		fileContent = newContent
 	}
 
-	// 如果启用了翻译器功能，添加相关import
+	// 旧版本生成的翻译器文件在没有json标签时直接使用字段名，补充读取protobuf标签中的json名称
 	if options.EnableTranslator {
-		if !bytes.Contains(fileContent, []byte(`"github.com/go-playground/locales/zh"`)) {
-			// 查找import部分
-			importStart := bytes.Index(fileContent, []byte("import ("))
-			importEnd := -1
-			if importStart != -1 {
-				importEnd = bytes.Index(fileContent[importStart:], []byte(")"))
-				if importEnd != -1 {
-					importEnd += importStart
-				}
-			}
-
-			var newContent []byte
-			if importStart != -1 && importEnd != -1 {
-				// 添加到现有import
-				importAddition := []byte("\n\t\"github.com/go-playground/locales/en\"\n\t\"github.com/go-playground/locales/zh\"\n\t\"github.com/go-playground/universal-translator\"")
-				newContent = append(fileContent[:importEnd], importAddition...)
-				newContent = append(newContent, fileContent[importEnd:]...)
+		content := result.TranslatorFile
+		if content == nil {
+			content = in.Translator
+		}
+		if updated := withProtobufTagName(content); !bytes.Equal(updated, content) {
+			if result.TranslatorFile, err = formatSource(updated, options); err != nil {
+				return nil, fmt.Errorf("格式化翻译器文件代码失败: %w", err)
 			}
-			fileContent = newContent
 		}
 	}
 
-	// 处理翻译器
-	if options.EnableTranslator {
-		err = ProcessTranslator(filePath, regFuncFromTypeStruct, customTags, options.DebugMode)
-		if err != nil {
-			return err
+	// 错误信息中的字段名优先使用label标签，已有的翻译器文件同样补充读取label标签
+	if options.UseLabelTag {
+		content := result.TranslatorFile
+		if content == nil {
+			content = in.Translator
+		}
+		if updated := withLabelTagName(content); !bytes.Equal(updated, content) {
+			result.TranslatorFile = updated
 		}
 	}
 
-	// 添加验证方法
-	for _, structName := range reqStructs {
-		validateMethod := fmt.Sprintf(`
-// Validate 验证 %s 的字段
-func (r *%s) Validate() error {
-	validate := validator.New()
-	return validate.Struct(r)
-}
-`, structName, structName)
-
-		// 检查结构体是否已有Validate方法
-		validateMethodSign := fmt.Sprintf("func (r *%s) Validate() error", structName)
-		if !bytes.Contains(fileContent, []byte(validateMethodSign)) {
-			fileContent = append(fileContent, []byte(validateMethod)...)
+	// 生成错误码文件
+	if options.GenerateErrorCodes && len(reqStructs) > 0 && !in.ErrorCodeExists {
+		if result.ErrorCodeFile, err = generateErrorCodeFile(packageName, validations, tr, options); err != nil {
+			return nil, err
 		}
 	}
 
-	// 处理自定义验证
-	if options.EnableCustomValidation && len(customTags) > 0 {
-		// 创建或更新validation.go文件
-		dir := filepath.Dir(filePath)
-		validationFilePath := filepath.Join(dir, "validation.go")
-
-		// 添加Debug日志
-		if options.DebugMode {
-			fmt.Printf("[Debug] 处理自定义验证，有 %d 个自定义标签\n", len(customTags))
-			for tag := range customTags {
-				fmt.Printf("[Debug] 自定义标签: %s，是否已存在验证: %v\n", tag, existingValidations[tag])
-			}
+	// 生成错误处理函数文件
+	if options.GenerateErrorHandler && len(reqStructs) > 0 && !in.ErrorHandlerExists {
+		if result.ErrorHandlerFile, err = generateErrorHandlerFile(packageName, options, tr); err != nil {
+			return nil, err
 		}
+	}
 
-		// 检查是否已有validation.go文件
-		if _, err := os.Stat(validationFilePath); os.IsNotExist(err) {
-			// 生成新的validation.go文件
-			validationCode := generateValidationCode(packageName, customTags, existingValidations)
-			err = os.WriteFile(validationFilePath, []byte(validationCode), 0644)
-			if err != nil {
-				return err
-			}
-		} else {
-			// 更新现有的validation.go文件
-			validationContent, err := os.ReadFile(validationFilePath)
-			if err != nil {
-				return err
-			}
-
-			// 为每个缺失的自定义验证器添加验证函数
-			var newValidations strings.Builder
-			for tag := range customTags {
-				if !existingValidations[tag] && !bytes.Contains(validationContent, []byte(fmt.Sprintf("validate%s", strings.Title(tag)))) {
-					validationFunc := generateValidationFunction(tag)
-					newValidations.WriteString(validationFunc)
-
-					// 添加到init函数中
-					initTag := fmt.Sprintf("validate.RegisterValidation(\"%s\", validate%s)", tag, strings.Title(tag))
-					if !bytes.Contains(validationContent, []byte(initTag)) {
-						// 查找init函数的结尾
-						initPos := bytes.Index(validationContent, []byte("func init()"))
-						if initPos != -1 {
-							// 找到init函数块的大括号
-							braceStart := bytes.IndexByte(validationContent[initPos:], '{')
-							if braceStart != -1 {
-								braceStart += initPos
-								braceEnd := findMatchingCloseBrace(validationContent, braceStart)
-								if braceEnd != -1 {
-									newValidationContent := append(validationContent[:braceEnd], []byte(fmt.Sprintf("\n\tvalidate.RegisterValidation(\"%s\", validate%s)", tag, strings.Title(tag)))...)
-									newValidationContent = append(newValidationContent, validationContent[braceEnd:]...)
-									validationContent = newValidationContent
-								}
-							}
-						}
-					}
-				}
-			}
-
-			// 添加新的验证函数
-			if newValidations.Len() > 0 {
-				validationContent = append(validationContent, []byte(newValidations.String())...)
-				err = os.WriteFile(validationFilePath, validationContent, 0644)
-				if err != nil {
-					return err
-				}
-			}
+	// 根据字段的oneof标签生成枚举常量，每次根据包中所有types文件当前的标签完整生成
+	if options.GenerateEnums && len(reqStructs) > 0 {
+		src := result.TypesFile
+		if src == nil {
+			src = in.Src
+		}
+		if result.EnumsFile, err = generateEnumsFile(packageName, in.FilePath, src, in.PackageEnums, in.Enums != nil, options); err != nil {
+			return nil, err
 		}
 	}
 
-	// 保存对types.go文件的修改
-	return os.WriteFile(filePath, fileContent, 0644)
-}
-
-// 查找匹配的右括号位置
-func findMatchingCloseBrace(content []byte, openBracePos int) int {
-	braceCount := 1
-	for i := openBracePos + 1; i < len(content); i++ {
-		if content[i] == '{' {
-			braceCount++
-		} else if content[i] == '}' {
-			braceCount--
-			if braceCount == 0 {
-				return i
+	// 生成验证文件中注册的验证方法的测试，已有的测试函数不会被修改
+	if options.GenerateTests && len(reqStructs) > 0 {
+		validationFileName, _, err := outputFileNames(options)
+		if err != nil {
+			return nil, err
+		}
+		content := result.ValidationFile
+		if content == nil {
+			content = in.Validation
+		}
+		validationFile, err := parseExistingFile(validationFileName, content)
+		if err != nil {
+			return nil, fmt.Errorf("解析验证文件失败: %w", err)
+		}
+		overridden := make(map[string]bool)
+		for _, v := range validations {
+			if v.RegexOverridden {
+				overridden[v.Func] = true
 			}
 		}
-	}
-	return -1
-}
-
-// 生成新的翻译器代码
-func generateNewTranslatorCode(customTags map[string]bool) string {
-	var customTagCode strings.Builder
-	for tag := range customTags {
-		if !isBuiltInValidator(tag) {
-			tagDesc := getTagDescription(tag)
-			customTagCode.WriteString(fmt.Sprintf(`
-	// 注册 %s 验证器的翻译
-	v.RegisterTranslation("%s", trans, func(ut ut.Translator) error {
-		return ut.Add("%s.%s", "{0} %s", true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("%s.%s", fe.Field())
-		return t
-	})
-`, tag, tag, "validationErrors", tag, tagDesc, "validationErrors", tag))
+		if result.TestFile, err = generateValidationTestFile(validationTestFileName(validationFileName), validationPackage, registeredValidations(validationFile), overridden, in.ValidationTest, options); err != nil {
+			return nil, fmt.Errorf("格式化测试文件代码失败: %w", err)
 		}
 	}
 
-	code := fmt.Sprintf(`package types
-
-import (
-	"fmt"
-	"reflect"
-	"strings"
-
-	"github.com/go-playground/locales/en"
-	"github.com/go-playground/locales/zh"
-	ut "github.com/go-playground/universal-translator"
-	"github.com/go-playground/validator/v10"
-	zhTranslations "github.com/go-playground/validator/v10/translations/zh"
-)
-
-var (
-	uni      *ut.UniversalTranslator
-	trans    ut.Translator
-	validate *validator.Validate
-)
-
-// InitTranslator 初始化验证翻译器
-func InitTranslator() {
-	// 初始化验证器和翻译器
-	validate = validator.New()
-	
-	// 注册标签翻译
-	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
-		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
-		if name == "-" {
-			return field.Name
-		}
-		return name
-	})
-
-	// 中英文翻译器
-	zhCn := zh.New()
-	en := en.New()
-	uni = ut.New(en, zhCn)
-
-	// 获取中文翻译器
-	trans, _ = uni.GetTranslator("zh")
-
-	// 注册默认中文翻译器
-	zhTranslations.RegisterDefaultTranslations(validate, trans)
-
-	// 注册自定义标签的翻译
-	// 注册 mobile 验证器的翻译
-	v.RegisterTranslation("mobile", trans, func(ut ut.Translator) error {
-		return ut.Add("validationErrors.mobile", "{0} 必须是有效的手机号码", true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("validationErrors.mobile", fe.Field())
-		return t
-	})
-
-	// 注册 idcard 验证器的翻译
-	v.RegisterTranslation("idcard", trans, func(ut ut.Translator) error {
-		return ut.Add("validationErrors.idcard", "{0} 必须是有效的身份证号码", true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("validationErrors.idcard", fe.Field())
-		return t
-	})
-%s
+	return result, nil
 }
 
-// Translate 翻译验证错误信息
-func Translate(err error) string {
-	if err == nil {
-		return ""
+// customValidationFunc 生成自定义验证方法的代码，带参数的标签生成读取参数的方法
+func customValidationFunc(tag string, hasParam, ctx bool) string {
+	if ctx {
+		return customCtxValidationFunc(tag, hasParam)
 	}
-	
-	errs, ok := err.(validator.ValidationErrors)
-	if !ok {
-		return err.Error()
+	if hasParam {
+		return fmt.Sprintf(CustomParamValidationFuncTemplate, tag, exportName(tag), tag)
 	}
+	return fmt.Sprintf(CustomValidationFuncTemplate, tag, exportName(tag), tag)
+}
 
-	var errMsgs []string
-	for _, e := range errs {
-		translatedErr := e.Translate(trans)
-		errMsgs = append(errMsgs, translatedErr)
+// validatorVar 获取验证器变量的声明，启用共享验证器时使用进程内共享的验证器
+func validatorVar(options Options) string {
+	if options.SharedValidator {
+		return SharedValidateVar
 	}
-	
-	return strings.Join(errMsgs, ", ")
+	return ValidateVar
 }
-`, customTagCode.String())
 
-	return code
+// withValidateOnce 将旧版本生成的直接注册验证方法的init替换为ValidateInitFunc，并导入sync
+func withValidateOnce(content string) string {
+	if !strings.Contains(content, legacyValidateInitFunc) {
+		return content
+	}
+	content = strings.Replace(content, legacyValidateInitFunc, strings.TrimPrefix(ValidateInitFunc, "\n"), 1)
+	if !strings.Contains(content, `"sync"`) {
+		content = strings.Replace(content, "import (\n", "import (\n\t\"sync\"\n", 1)
+	}
+	return content
 }
 
-// 生成验证函数代码
-func generateValidationFunction(tag string) string {
-	// 根据标签生成验证函数
-	switch tag {
-	case "mobile":
-		return `
-// validateMobile 验证手机号码
-func validateMobile(fl validator.FieldLevel) bool {
-	value := fl.Field().String()
-	// 验证中国大陆手机号码
-	reg := regexp.MustCompile(` + "`" + `^1[3-9]\d{9}$` + "`" + `)
-	return reg.MatchString(value)
-}
-`
-	case "idcard":
-		return `
-// validateIdcard 验证身份证号码
-func validateIdcard(fl validator.FieldLevel) bool {
-	value := fl.Field().String()
-	// 验证中国大陆18位身份证号码
-	reg := regexp.MustCompile(` + "`" + `^\d{17}[\dXx]$` + "`" + `)
-	return reg.MatchString(value)
-}
-`
-	default:
-		// 生成一个默认的验证函数
-		return fmt.Sprintf(`
-// validate%s 验证%s格式
-func validate%s(fl validator.FieldLevel) bool {
-	// 请实现此验证函数...
-	// 此为自动生成的验证函数，需要手动实现验证逻辑
-	return true
+// validatorVarDecl 生成验证器变量的声明，未启用翻译器时同时声明默认语言的翻译器并注册默认翻译
+func validatorVarDecl(lang string, options Options, tr *translatorRef) string {
+	// 结构体专属的验证器同样注册默认翻译，翻译器包装为sharedTranslator
+	wrapTrans, structSetup := "", ""
+	if options.PerStructValidator {
+		wrapTrans = "\n\ttrans = sharedTranslator{trans}"
+		structSetup = fmt.Sprintf(`
+	validatorSetups = append(validatorSetups, func(v *validator.Validate) {
+		_ = %sTranslations.RegisterDefaultTranslations(v, trans)
+	})`, lang)
+	}
+	validateVarStatement := fmt.Sprintf(`
+    var %[1]sTrans =  %[1]s.New()
+	var trans, _ = ut.New(%[1]sTrans, %[1]sTrans).GetTranslator("%[1]s")
+	%[2]s
+	// 注册默认翻译
+func init(){%[3]s
+    %[1]sTranslations.RegisterDefaultTranslations(validate, trans)%[4]s
 }
-`, strings.Title(tag), tag, strings.Title(tag))
+`, lang, validatorVar(options), wrapTrans, structSetup)
+	if options.EnableTranslator {
+		validateVarStatement = "\n" + validatorVar(options) + "\n"
 	}
+	// 共享翻译器包需要在本包的验证器上注册翻译
+	if tr.shared() {
+		structSetup = ""
+		if options.PerStructValidator {
+			structSetup = fmt.Sprintf("\n\tvalidatorSetups = append(validatorSetups, %s.Register)", tr.pkg())
+		}
+		validateVarStatement += fmt.Sprintf(SharedTranslatorRegisterTemplate, tr.pkg(), structSetup)
+	}
+	return validateVarStatement
 }
 
-// 生成完整的验证代码
-func generateValidationCode(packageName string, customTags map[string]bool, existingValidations map[string]bool) string {
-	var registrations strings.Builder
-	var validationFunctions strings.Builder
+// customTranslationsFunc 生成注册插件内置、配置文件定义及自定义标签翻译的registerCustomTranslations函数
+func customTranslationsFunc(validations []builtInValidation, customTags, enumTags map[string]bool, lang string) string {
+	var code strings.Builder
+	code.WriteString("// 注册自定义翻译\n")
+	code.WriteString("func registerCustomTranslations(validate *validator.Validate, trans ut.Translator) {\n")
+	code.WriteString("\t// 内置自定义验证器的翻译\n")
+	for i, builtIn := range validations {
+		if i > 0 {
+			code.WriteString("\n")
+		}
+		code.WriteString(fmt.Sprintf("\t_ = trans.Add(\"%s\", %q, true)\n", builtIn.Tag, validationMessage(builtIn, lang)))
+		code.WriteString(fmt.Sprintf("\t_ = validate.RegisterTranslation(\"%s\", trans, func(ut ut.Translator) error {\n", builtIn.Tag))
+		code.WriteString("\t\treturn nil\n")
+		code.WriteString("\t}, func(ut ut.Translator, fe validator.FieldError) string {\n")
+		code.WriteString(fmt.Sprintf("\t\tt, _ := ut.T(\"%s\", fe.Field(), fe.Param())\n", builtIn.Tag))
+		code.WriteString("\t\treturn t\n")
+		code.WriteString("\t})\n")
+	}
 
-	for tag := range customTags {
-		if !existingValidations[tag] {
-			registrations.WriteString(fmt.Sprintf("\tvalidate.RegisterValidation(\"%s\", validate%s)\n", tag, strings.Title(tag)))
-			validationFunctions.WriteString(generateValidationFunction(tag))
+	// 按字母顺序为自定义标签添加初始翻译
+	for _, tag := range sortedTags(customTags) {
+		if !isBuiltInValidator(tag) {
+			// 为新标签生成默认翻译文本
+			description := customTagMessage(lang, tag, enumTags)
+
+			code.WriteString(fmt.Sprintf("\n\t_ = trans.Add(\"%s\", %q, true)\n", tag, description))
+			code.WriteString(fmt.Sprintf("\t_ = validate.RegisterTranslation(\"%s\", trans, func(ut ut.Translator) error {\n", tag))
+			code.WriteString("\t\treturn nil\n")
+			code.WriteString("\t}, func(ut ut.Translator, fe validator.FieldError) string {\n")
+			code.WriteString(fmt.Sprintf("\t\tt, _ := ut.T(\"%s\", fe.Field(), fe.Param())\n", tag))
+			code.WriteString("\t\treturn t\n")
+			code.WriteString("\t})\n")
 		}
 	}
 
-	code := fmt.Sprintf(`package %s
+	code.WriteString("}\n")
+	return code.String()
+}
 
-import (
-	"regexp"
+// 从结构体标签中提取validate标签内容，validate:"-"表示validator跳过该字段，与没有验证规则相同返回空字符串
+func extractValidateTag(tag string) string {
+	re := regexp.MustCompile(`validate:"([^"]*)"`)
+	matches := re.FindStringSubmatch(tag)
+	if len(matches) > 1 && matches[1] != "-" {
+		return matches[1]
+	}
+	return ""
+}
 
-	"github.com/go-playground/validator/v10"
-)
+//go:generate go run gen_builtin_tags.go
 
-var validate *validator.Validate
+// 判断是否是内置验证器
+// omitempty、omitnil、dive等是go-playground的验证控制指令（不属于JSON标签），同样视为内置，不会生成自定义验证方法
+func isBuiltInValidator(validator string) bool {
+	// 或运算的验证标签，如hexcolor|rgb，所有标签都是内置验证器时才视为内置
+	for _, v := range strings.Split(validator, "|") {
+		// 带参数的内置验证器，如min=10
+		tag, _, _ := strings.Cut(v, "=")
+		if !goPlaygroundTags[tag] && !isPluginValidation(tag) {
+			return false
+		}
+	}
+	return true
+}
 
-func init() {
-	validate = validator.New()
-%s}
-%s
-`, packageName, registrations.String(), validationFunctions.String())
+// isPluginValidation 判断是否是插件内置的验证方法，如mobile、idcard
+func isPluginValidation(tag string) bool {
+	for _, builtIn := range builtInValidations {
+		if builtIn.Tag == tag {
+			return true
+		}
+	}
+	return false
+}
 
-	return code
+// AddValidationMethodsToStructs 为结构体添加验证方法
+// Deprecated: 使用ProcessTypesFile，两者生成相同的代码
+func AddValidationMethodsToStructs(filePath string, options *Options) error {
+	_, err := ProcessTypesFile(false, filePath, *options, nil)
+	return err
 }
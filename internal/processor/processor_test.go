@@ -0,0 +1,2819 @@
+package processor
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testTypesSrc 测试使用的types文件，包含插件内置标签(mobile)及自定义标签(age_range)
+const testTypesSrc = "package types\n\n" +
+	"type CreateUserReq struct {\n" +
+	"\tName   string `json:\"name\" validate:\"required,min=2\"`\n" +
+	"\tMobile string `json:\"mobile\" validate:\"required,mobile\"`\n" +
+	"\tAge    int    `json:\"age\" validate:\"age_range\"`\n" +
+	"}\n"
+
+// writeTypesFile 在目录中写入types文件并返回路径
+func writeTypesFile(t *testing.T, dir, name, src string) string {
+	t.Helper()
+	filePath := filepath.Join(dir, name)
+	if err := os.WriteFile(filePath, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filePath
+}
+
+// processFiles 按插件的方式依次处理同一目录中的types文件，第一个文件之后的文件不再声明验证器变量
+func processFiles(t *testing.T, options Options, files ...string) error {
+	t.Helper()
+	genFlag := false
+	for _, file := range files {
+		gen, err := ProcessTypesFile(genFlag, file, options, nil)
+		if err != nil {
+			return err
+		}
+		genFlag = genFlag || gen
+	}
+	return nil
+}
+
+// snapshotDir 读取目录中所有文件的内容，key为文件名
+func snapshotDir(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, entry := range entries {
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[entry.Name()] = string(content)
+	}
+	return files
+}
+
+// containsCode 判断生成的代码中是否包含want，忽略空白的差异（如gofmt对齐映射的值）
+func containsCode(content, want string) bool {
+	return strings.Contains(strings.Join(strings.Fields(content), " "), strings.Join(strings.Fields(want), " "))
+}
+
+// readFile 读取目录中的文件，不存在时返回空字符串
+func readFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+// testModuleGoMod 编译生成代码的临时模块，共享验证器包指向本仓库
+const testModuleGoMod = `module example.com/gen
+
+go 1.23.7
+
+require (
+	github.com/go-playground/locales v0.14.1
+	github.com/go-playground/universal-translator v0.18.1
+	github.com/go-playground/validator/v10 v10.26.0
+	github.com/xs-cw/goctl-validate v0.0.0
+)
+
+replace github.com/xs-cw/goctl-validate => %s
+`
+
+// newTestModule 创建编译生成代码的临时模块，返回模块根目录，types文件写入其中的types目录
+// 依赖从本地模块缓存中读取，需要go命令，-short时跳过
+func newTestModule(t *testing.T) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping compilation of generated code in short mode")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	repo, err := filepath.Abs(filepath.Join("..", ".."))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := os.ReadFile(filepath.Join(repo, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	writeTypesFile(t, root, "go.mod", fmt.Sprintf(testModuleGoMod, repo))
+	writeTypesFile(t, root, "go.sum", string(sum))
+	if err := os.Mkdir(filepath.Join(root, "types"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// goCommand 在临时模块中执行go命令，失败时输出命令的输出
+func goCommand(t *testing.T, root string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("go", args...)
+	cmd.Dir = root
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOSUMDB=off", "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go %s failed: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
+// runGenerated 在临时模块中运行调用生成的types包的main函数，返回输出
+func runGenerated(t *testing.T, root, mainSrc string) string {
+	t.Helper()
+	writeTypesFile(t, root, "main.go", mainSrc)
+	return goCommand(t, root, "run", ".")
+}
+
+func TestProcessTypesFileIdempotent(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+	}{
+		{"default", Options{}},
+		{"translator", Options{EnableTranslator: true}},
+		{"custom", Options{EnableCustomValidation: true}},
+		{"custom translator", Options{EnableCustomValidation: true, EnableTranslator: true}},
+		{"multi language", Options{EnableCustomValidation: true, EnableTranslator: true, TranslationLanguages: []string{"zh", "en"}}},
+		{"ctx", Options{EnableCustomValidation: true, EnableTranslator: true, GenerateContextMethod: true, GenerateJSONMethod: true, GenerateFieldsMethod: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			file := writeTypesFile(t, dir, "types.go", testTypesSrc)
+			if err := processFiles(t, tt.options, file); err != nil {
+				t.Fatal(err)
+			}
+			first := snapshotDir(t, dir)
+			if err := processFiles(t, tt.options, file); err != nil {
+				t.Fatal(err)
+			}
+			second := snapshotDir(t, dir)
+			for name, content := range first {
+				if second[name] != content {
+					t.Errorf("%s changed on the second run:\n%s", name, second[name])
+				}
+			}
+			if len(second) != len(first) {
+				t.Errorf("second run wrote %d files, want %d", len(second), len(first))
+			}
+		})
+	}
+}
+
+func TestCustomTagsRequireCustomFlag(t *testing.T) {
+	dir := t.TempDir()
+	file := writeTypesFile(t, dir, "types.go", testTypesSrc)
+	// 第二次执行走更新已有验证文件的路径，与新建文件一样不生成自定义标签的注册和验证方法
+	for range 2 {
+		if err := processFiles(t, Options{EnableTranslator: true}, file); err != nil {
+			t.Fatal(err)
+		}
+		if validation := readFile(t, dir, "validation.go"); strings.Contains(validation, "validateAgeRange") {
+			t.Fatalf("validation.go registers age_range without --custom:\n%s", validation)
+		}
+	}
+}
+
+func TestUpdateAddsCustomTag(t *testing.T) {
+	dir := t.TempDir()
+	options := Options{EnableCustomValidation: true, EnableTranslator: true}
+	file := writeTypesFile(t, dir, "types.go", testTypesSrc)
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+
+	// 新增的自定义标签在已有的验证文件和翻译器文件中增量添加
+	src := strings.Replace(readFile(t, dir, "types.go"), `validate:"age_range"`, `validate:"age_range,new_tag1"`, 1)
+	writeTypesFile(t, dir, "types.go", src)
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+
+	validation := readFile(t, dir, "validation.go")
+	for _, want := range []string{
+		"func validateNewTag1(",
+		"validateNewTag1, // 自定义验证: new_tag1 (请实现)",
+		"validateAgeRange, // 自定义验证: age_range (请实现)",
+	} {
+		if !containsCode(validation, want) {
+			t.Errorf("validation.go does not contain %q:\n%s", want, validation)
+		}
+	}
+	translator := readFile(t, dir, "translator.go")
+	if !strings.Contains(translator, `trans.Add("new_tag1", "{0}格式不符合要求", true)`) {
+		t.Errorf("translator.go does not register new_tag1 with override:\n%s", translator)
+	}
+	// 新建及增量添加的翻译使用相同的覆盖方式
+	if strings.Contains(translator, ", false)") {
+		t.Errorf("translator.go registers translations without override:\n%s", translator)
+	}
+}
+
+func TestForceRegeneratesValidationFile(t *testing.T) {
+	dir := t.TempDir()
+	options := Options{EnableCustomValidation: true}
+	file := writeTypesFile(t, dir, "types.go", testTypesSrc)
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+
+	// 删除自定义标签后，只有完整重新生成时才会删除其验证方法
+	src := strings.Replace(readFile(t, dir, "types.go"), ` validate:"age_range"`, "", 1)
+	writeTypesFile(t, dir, "types.go", src)
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(readFile(t, dir, "validation.go"), "func validateAgeRange(") {
+		t.Fatal("validateAgeRange removed without --force")
+	}
+
+	options.Force = true
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	validation := readFile(t, dir, "validation.go")
+	if strings.Contains(validation, "age_range") {
+		t.Errorf("validation.go still registers age_range after --force:\n%s", validation)
+	}
+	if !containsCode(validation, `"mobile": validateMobile`) {
+		t.Errorf("validation.go lost the built-in mobile validation after --force:\n%s", validation)
+	}
+}
+
+func TestHandWrittenRegisteredFunc(t *testing.T) {
+	dir := t.TempDir()
+	options := Options{EnableCustomValidation: true}
+	file := writeTypesFile(t, dir, "types.go", testTypesSrc)
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+
+	// 将age_range注册到手动实现的函数，并删除生成的验证方法
+	validation := readFile(t, dir, "validation.go")
+	start := strings.Index(validation, "// 自定义验证方法: age_range")
+	end := strings.Index(validation[start:], "\n}\n") + start + len("\n}\n")
+	validation = validation[:start] + "func checkAge(fl validator.FieldLevel) bool {\n\treturn fl.Field().Int() > 0\n}\n" + validation[end:]
+	validation = strings.Replace(validation, "validateAgeRange,", "checkAge,", 1)
+	writeTypesFile(t, dir, "validation.go", validation)
+
+	options.Strict = true
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatalf("--strict failed for a tag registered to an implemented function: %v", err)
+	}
+	validation = readFile(t, dir, "validation.go")
+	if strings.Contains(validation, "validateAgeRange") {
+		t.Errorf("validation.go generated a stub for a tag registered to checkAge:\n%s", validation)
+	}
+	if strings.Contains(validation, "(请实现)") {
+		t.Errorf("validation.go marks the implemented checkAge as unimplemented:\n%s", validation)
+	}
+}
+
+func TestStrictFailsOnStub(t *testing.T) {
+	dir := t.TempDir()
+	file := writeTypesFile(t, dir, "types.go", testTypesSrc)
+	err := processFiles(t, Options{EnableCustomValidation: true, Strict: true}, file)
+	if err == nil || !strings.Contains(err.Error(), "age_range") {
+		t.Fatalf("processFiles() error = %v, want an error naming age_range", err)
+	}
+	// 文件仍会正常写入
+	if !strings.Contains(readFile(t, dir, "validation.go"), "func validateAgeRange(") {
+		t.Error("validation.go was not written")
+	}
+}
+
+func TestTranslationLanguage(t *testing.T) {
+	tests := []struct {
+		name string
+		lang string
+		want []string
+	}{
+		{"default", "", []string{`"github.com/go-playground/validator/v10/translations/zh"`, `uni.GetTranslator("zh")`}},
+		{"en", "en", []string{`"github.com/go-playground/validator/v10/translations/en"`, `"github.com/go-playground/locales/en"`, `uni.GetTranslator("en")`}},
+		{"ja", "ja", []string{`"github.com/go-playground/validator/v10/translations/ja"`, `"github.com/go-playground/locales/ja"`, `uni.GetTranslator("ja")`}},
+		{"ko", "ko", []string{`"github.com/go-playground/validator/v10/translations/ko"`, `"github.com/go-playground/locales/ko"`, `uni.GetTranslator("ko")`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Generate([]byte(testTypesSrc), "", Options{EnableTranslator: true, TranslationLanguage: tt.lang})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(string(result.TranslatorFile), want) {
+					t.Errorf("translator file does not contain %s:\n%s", want, result.TranslatorFile)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorHandlerMultipleTypesFiles(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	user := writeTypesFile(t, dir, "user.go", testTypesSrc)
+	order := writeTypesFile(t, dir, "order.go", "package types\n\n"+
+		"type CreateOrderReq struct {\n"+
+		"\tAmount int `json:\"amount\" validate:\"required,gt=0\"`\n"+
+		"}\n")
+	// 第二个types文件只调用验证器变量，不导入验证器包，重复执行时不重复声明验证器变量
+	for range 2 {
+		if err := processFiles(t, Options{GenerateErrorHandler: true}, order, user); err != nil {
+			t.Fatal(err)
+		}
+		goCommand(t, root, "build", "./...")
+	}
+}
+
+func TestErrorCodesMultipleTypesFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+	}{
+		{"global validator", Options{GenerateErrorCodes: true}},
+		{"per struct", Options{GenerateErrorCodes: true, PerStructValidator: true}},
+		{"translator", Options{GenerateErrorCodes: true, EnableTranslator: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newTestModule(t)
+			dir := filepath.Join(root, "types")
+			user := writeTypesFile(t, dir, "user.go", testTypesSrc)
+			order := writeTypesFile(t, dir, "order.go", "package types\n\n"+
+				"type CreateOrderReq struct {\n"+
+				"\tAmount int `json:\"amount\" validate:\"required,gt=0\"`\n"+
+				"}\n")
+			for range 2 {
+				if err := processFiles(t, tt.options, order, user); err != nil {
+					t.Fatal(err)
+				}
+				goCommand(t, root, "build", "./...")
+			}
+		})
+	}
+}
+
+func TestSharedValidatorCompiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+	}{
+		{"default", Options{SharedValidator: true}},
+		{"error handler", Options{SharedValidator: true, GenerateErrorHandler: true}},
+		{"error codes", Options{SharedValidator: true, GenerateErrorCodes: true}},
+		{"custom translator", Options{SharedValidator: true, EnableCustomValidation: true, EnableTranslator: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newTestModule(t)
+			file := writeTypesFile(t, filepath.Join(root, "types"), "types.go", testTypesSrc)
+			if err := processFiles(t, tt.options, file); err != nil {
+				t.Fatal(err)
+			}
+			goCommand(t, root, "build", "./...")
+		})
+	}
+}
+
+// mobileTypesSrc 只使用内置标签的types文件，生成的代码无需实现自定义验证方法即可运行
+const mobileTypesSrc = "package types\n\n" +
+	"type CreateUserReq struct {\n" +
+	"\tName   string `json:\"name\" validate:\"required,min=2\"`\n" +
+	"\tMobile string `json:\"mobile\" validate:\"required,mobile\"`\n" +
+	"}\n"
+
+// mobileMain 分别验证无效及有效手机号的main函数
+const mobileMain = `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.CreateUserReq{Name: "name", Mobile: "12345"}).Validate() != nil)
+	fmt.Println((&types.CreateUserReq{Name: "name", Mobile: "13800138000"}).Validate() == nil)
+}
+`
+
+func TestValidateEnforcesMobile(t *testing.T) {
+	root := newTestModule(t)
+	file := writeTypesFile(t, filepath.Join(root, "types"), "types.go", mobileTypesSrc)
+	if err := processFiles(t, Options{}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 生成的Validate方法使用init中通过sync.Once注册了内置验证方法的包级别验证器
+	if got := runGenerated(t, root, mobileMain); got != "true\ntrue\n" {
+		t.Errorf("Validate() results = %q, want the mobile validator to reject 12345 and accept 13800138000", got)
+	}
+}
+
+func TestValidateOnceMigration(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", mobileTypesSrc)
+	if err := processFiles(t, Options{}, file); err != nil {
+		t.Fatal(err)
+	}
+
+	// 旧版本生成的验证文件在init中直接注册验证方法
+	validation := readFile(t, dir, "validation.go")
+	if !strings.Contains(validation, ValidateInitFunc) {
+		t.Fatalf("validation.go does not contain the sync.Once guarded init:\n%s", validation)
+	}
+	validation = strings.Replace(validation, strings.TrimPrefix(ValidateInitFunc, "\n"), legacyValidateInitFunc, 1)
+	validation = strings.Replace(validation, "\t\"sync\"\n", "", 1)
+	writeTypesFile(t, dir, "validation.go", validation)
+
+	if err := processFiles(t, Options{}, file); err != nil {
+		t.Fatal(err)
+	}
+	validation = readFile(t, dir, "validation.go")
+	for _, want := range []string{`"sync"`, "var validateOnce sync.Once", "func initValidate() {"} {
+		if !strings.Contains(validation, want) {
+			t.Errorf("validation.go does not contain %q after regeneration:\n%s", want, validation)
+		}
+	}
+	if got := runGenerated(t, root, mobileMain); got != "true\ntrue\n" {
+		t.Errorf("Validate() results = %q after migration", got)
+	}
+}
+
+func TestTranslatorInitKeepsValidations(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+	}{
+		{"translator", Options{EnableTranslator: true}},
+		{"multi language", Options{EnableTranslator: true, TranslationLanguages: []string{"zh", "en"}}},
+		{"lazy translator", Options{EnableTranslator: true, LazyTranslator: true}},
+		{"per struct", Options{EnableTranslator: true, PerStructValidator: true}},
+	}
+	main := `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.CreateUserReq{Name: "name", Mobile: "12345"}).Validate())
+}
+`
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newTestModule(t)
+			dir := filepath.Join(root, "types")
+			file := writeTypesFile(t, dir, "types.go", mobileTypesSrc)
+			if err := processFiles(t, tt.options, file); err != nil {
+				t.Fatal(err)
+			}
+			// translator.go的init()先于validation.go执行，先通过initValidate注册验证方法
+			if !strings.Contains(readFile(t, dir, "translator.go"), "initValidate()") {
+				t.Errorf("translator.go does not register the validations before translating")
+			}
+			// 翻译器初始化后验证器仍是同一个实例，mobile验证方法及其翻译都已注册
+			if got := runGenerated(t, root, main); !strings.Contains(got, "mobile手机号码格式不正确") {
+				t.Errorf("Validate() = %q, want the translated mobile error", got)
+			}
+		})
+	}
+}
+
+func TestGeneratedHeader(t *testing.T) {
+	dir := t.TempDir()
+	options := Options{EnableCustomValidation: true, EnableTranslator: true, GenerateErrorCodes: true, GenerateErrorHandler: true}
+	file := writeTypesFile(t, dir, "types.go", testTypesSrc)
+	for range 2 {
+		if err := processFiles(t, options, file); err != nil {
+			t.Fatal(err)
+		}
+		// 完全生成的文件带有一次生成代码标记
+		for _, name := range []string{"translator.go", ErrorCodeFileName, ErrorHandlerFileName} {
+			if content := readFile(t, dir, name); !strings.HasPrefix(content, GeneratedHeader) || strings.Count(content, "DO NOT EDIT") != 1 {
+				t.Errorf("%s does not start with a single generated header:\n%s", name, content)
+			}
+		}
+		// validation.go中需要实现自定义验证方法，不带生成代码标记
+		if validation := readFile(t, dir, "validation.go"); strings.Contains(validation, "DO NOT EDIT") {
+			t.Errorf("validation.go contains the generated header:\n%s", validation)
+		}
+	}
+
+	// 旧版本生成的验证文件带有生成代码标记时删除
+	writeTypesFile(t, dir, "validation.go", GeneratedHeader+readFile(t, dir, "validation.go"))
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	if validation := readFile(t, dir, "validation.go"); strings.Contains(validation, "DO NOT EDIT") {
+		t.Errorf("validation.go keeps the generated header of an older version:\n%s", validation)
+	}
+}
+
+func TestGeneratedTestsCompile(t *testing.T) {
+	root := newTestModule(t)
+	file := writeTypesFile(t, filepath.Join(root, "types"), "types.go", testTypesSrc)
+	options := Options{EnableCustomValidation: true, GenerateTests: true}
+	for range 2 {
+		if err := processFiles(t, options, file); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 内置验证方法的测试用例通过，自定义验证方法的占位用例跳过
+	goCommand(t, root, "test", "./types")
+}
+
+func TestCustomTranslationsQuoteMessages(t *testing.T) {
+	// 翻译文本中的引号和反斜杠需要转义
+	translationLanguages["zh"]["quoted_tag"] = `{0}必须是"{1}"\格式`
+	t.Cleanup(func() { delete(translationLanguages["zh"], "quoted_tag") })
+
+	code := customTranslationsFunc(nil, map[string]bool{"quoted_tag": true}, nil, "zh")
+	if _, err := parser.ParseFile(token.NewFileSet(), "translator.go", "package types\n\n"+code, 0); err != nil {
+		t.Fatalf("generated translations do not parse: %v\n%s", err, code)
+	}
+	if want := `_ = trans.Add("quoted_tag", "{0}必须是\"{1}\"\\格式", true)`; !strings.Contains(code, want) {
+		t.Errorf("generated translations do not contain %s:\n%s", want, code)
+	}
+}
+
+func TestDatetimeBuiltIn(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type EventReq struct {\n"+
+		"\tDate string `json:\"date\" validate:\"required,datetime=2006-01-02\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{EnableCustomValidation: true, EnableTranslator: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	// datetime是validator内置的验证标签，不能生成自定义验证方法
+	validation := readFile(t, dir, "validation.go")
+	if strings.Contains(validation, "validateDatetime") || strings.Contains(validation, "自定义验证方法") {
+		t.Errorf("validation.go generates a stub for datetime:\n%s", validation)
+	}
+	// 中文翻译由validator的zh翻译提供，翻译中包含布局参数
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.EventReq{Date: "2024/01/02"}).Validate())
+	fmt.Println((&types.EventReq{Date: "2024-01-02"}).Validate())
+}
+`)
+	if want := "date的格式必须是2006-01-02\n<nil>\n"; got != want {
+		t.Errorf("Validate() = %q, want %q", got, want)
+	}
+}
+
+func TestOmitemptyCustomTag(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type ItemReq struct {\n"+
+		"\tSku string `json:\"sku\" validate:\"omitempty,sku\"`\n"+
+		"}\n")
+	summary := &Summary{}
+	if _, err := ProcessTypesFile(false, file, Options{EnableCustomValidation: true}, summary); err != nil {
+		t.Fatal(err)
+	}
+	// omitempty是validator的控制标签，只为sku生成验证方法
+	if !slices.Equal(summary.CustomTags, []string{"sku"}) {
+		t.Errorf("CustomTags = %v, want [sku]", summary.CustomTags)
+	}
+	validation := readFile(t, dir, "validation.go")
+	if !strings.Contains(validation, "func validateSku(") || strings.Contains(validation, "validateOmitempty") {
+		t.Fatalf("validation.go does not stub sku only:\n%s", validation)
+	}
+	// sku的验证方法拒绝所有值，空值由omitempty跳过验证
+	validation = strings.Replace(validation, "// 在这里实现 sku 的验证逻辑\n\treturn true", "return false", 1)
+	writeTypesFile(t, dir, "validation.go", validation)
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.ItemReq{}).Validate() == nil)
+	fmt.Println((&types.ItemReq{Sku: "x"}).Validate() == nil)
+}
+`)
+	if got != "true\nfalse\n" {
+		t.Errorf("Validate() = %q, want an empty sku to pass and a non-empty one to fail", got)
+	}
+}
+
+func TestMapValueStructs(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	// validator只在endkeys后有验证规则时验证map的值，因此endkeys后需要required
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type OrderReq struct {\n"+
+		"\tItems map[string]Item `json:\"items\" validate:\"required,dive,keys,min=2,endkeys,required\"`\n"+
+		"}\n\n"+
+		"type Item struct {\n"+
+		"\tMobile string `json:\"mobile\" validate:\"required,mobile\"`\n"+
+		"}\n")
+	summary := &Summary{}
+	if _, err := ProcessTypesFile(false, file, Options{EnableCustomValidation: true}, summary); err != nil {
+		t.Fatal(err)
+	}
+	// map的值类型同样生成Validate方法，dive、keys、endkeys不是自定义标签
+	if !slices.Equal(summary.StructsProcessed, []string{"OrderReq", "Item"}) || len(summary.CustomTags) != 0 {
+		t.Errorf("StructsProcessed = %v, CustomTags = %v, want [OrderReq Item] and no custom tags", summary.StructsProcessed, summary.CustomTags)
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.OrderReq{Items: map[string]types.Item{"ab": {Mobile: "13800138000"}}}).Validate() == nil)
+	fmt.Println((&types.OrderReq{Items: map[string]types.Item{"ab": {Mobile: "12345"}}}).Validate() == nil)
+	fmt.Println((&types.OrderReq{Items: map[string]types.Item{"a": {Mobile: "13800138000"}}}).Validate() == nil)
+	fmt.Println((&types.Item{Mobile: "12345"}).Validate() == nil)
+}
+`)
+	if got != "true\nfalse\nfalse\nfalse\n" {
+		t.Errorf("Validate() = %q, want the map values and keys to be validated", got)
+	}
+}
+
+func TestConditionalRequiredTags(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type PlanReq struct {\n"+
+		"\tType  string `json:\"type\" validate:\"required,oneof=free premium\"`\n"+
+		"\tCard  string `json:\"card\" validate:\"required_if=Type premium\"`\n"+
+		"\tCoupon string `json:\"coupon\" validate:\"excluded_unless=Type premium\"`\n"+
+		"}\n")
+	summary := &Summary{}
+	if _, err := ProcessTypesFile(false, file, Options{EnableCustomValidation: true}, summary); err != nil {
+		t.Fatal(err)
+	}
+	// 条件验证标签的参数包含空格和字段名，仍是validator内置的验证标签
+	if len(summary.CustomTags) != 0 {
+		t.Errorf("CustomTags = %v, want none", summary.CustomTags)
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.PlanReq{Type: "free"}).Validate() == nil)
+	fmt.Println((&types.PlanReq{Type: "premium"}).Validate() == nil)
+	fmt.Println((&types.PlanReq{Type: "premium", Card: "6222"}).Validate() == nil)
+	fmt.Println((&types.PlanReq{Type: "free", Coupon: "vip"}).Validate() == nil)
+}
+`)
+	if got != "true\nfalse\ntrue\nfalse\n" {
+		t.Errorf("Validate() = %q, want card and coupon to depend on the plan type", got)
+	}
+}
+
+func TestTimeFieldComparison(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"import \"time\"\n\n"+
+		"type RangeReq struct {\n"+
+		"\tStartTime time.Time `json:\"startTime\" validate:\"required\"`\n"+
+		"\tEndTime   time.Time `json:\"endTime\" validate:\"required,gtefield=StartTime\"`\n"+
+		"}\n")
+	summary := &Summary{}
+	if _, err := ProcessTypesFile(false, file, Options{EnableCustomValidation: true, EnableTranslator: true}, summary); err != nil {
+		t.Fatal(err)
+	}
+	if len(summary.CustomTags) != 0 {
+		t.Errorf("CustomTags = %v, want none", summary.CustomTags)
+	}
+	// 写入Validate方法后types文件仍需导入time
+	if types := readFile(t, dir, "types.go"); !strings.Contains(types, `"time"`) {
+		t.Errorf("types.go lost the time import:\n%s", types)
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+	"time"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	fmt.Println((&types.RangeReq{StartTime: start, EndTime: start}).Validate() == nil)
+	fmt.Println((&types.RangeReq{StartTime: start, EndTime: start.Add(-time.Hour)}).Validate() == nil)
+}
+`)
+	if got != "true\nfalse\n" {
+		t.Errorf("Validate() = %q, want endTime before startTime to fail", got)
+	}
+}
+
+func TestPointerMobileField(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type ContactReq struct {\n"+
+		"\tMobile *string `json:\"mobile\" validate:\"omitempty,mobile\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 指针字段解引用后再验证，nil由omitempty跳过
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	valid, invalid := "13800138000", "12345"
+	fmt.Println((&types.ContactReq{}).Validate() == nil)
+	fmt.Println((&types.ContactReq{Mobile: &valid}).Validate() == nil)
+	fmt.Println((&types.ContactReq{Mobile: &invalid}).Validate() == nil)
+}
+`)
+	if got != "true\ntrue\nfalse\n" {
+		t.Errorf("Validate() = %q, want the pointed-to mobile to be validated", got)
+	}
+}
+
+func TestOutputDir(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", testTypesSrc)
+	before := snapshotDir(t, dir)
+	out := t.TempDir()
+	options := Options{EnableCustomValidation: true, EnableTranslator: true, OutputDir: out}
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	// 项目中的文件保持不变
+	if after := snapshotDir(t, dir); !maps.Equal(after, before) {
+		t.Errorf("files in the project changed with --output-dir: %v", slices.Sorted(maps.Keys(after)))
+	}
+	// 生成的文件及修改后的types.go按相对于模块根目录的路径写入输出目录
+	outDir := filepath.Join(out, "types")
+	if types := readFile(t, outDir, "types.go"); !strings.Contains(types, "func (r *CreateUserReq) Validate() error") {
+		t.Errorf("output types.go has no Validate method:\n%s", types)
+	}
+	for _, name := range []string{"validation.go", "translator.go"} {
+		if readFile(t, outDir, name) == "" {
+			t.Errorf("%s was not written to the output dir", name)
+		}
+	}
+	// 输出目录中的包可以独立编译
+	for _, name := range []string{"go.mod", "go.sum"} {
+		writeTypesFile(t, out, name, readFile(t, root, name))
+	}
+	goCommand(t, out, "vet", "./types")
+}
+
+// validateValues 生成只有一个使用tag验证的Value字段的结构体，返回各个值能否通过Validate
+func validateValues(t *testing.T, options Options, tag string, values ...string) []bool {
+	t.Helper()
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type ValueReq struct {\n"+
+		"\tValue string `json:\"value\" validate:\""+tag+"\"`\n"+
+		"}\n")
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	main := fmt.Sprintf(`package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	for _, v := range %#v {
+		fmt.Println((&types.ValueReq{Value: v}).Validate() == nil)
+	}
+}
+`, values)
+	var got []bool
+	for _, line := range strings.Fields(runGenerated(t, root, main)) {
+		got = append(got, line == "true")
+	}
+	return got
+}
+
+func TestBankcard(t *testing.T) {
+	values := []string{"6222021234567890128", "6222021234567890123", "6222-0212-3456-7890", "123456789012"}
+	want := []bool{true, false, false, false}
+	if got := validateValues(t, Options{EnableCustomValidation: true}, "bankcard", values...); !slices.Equal(got, want) {
+		t.Errorf("bankcard %q = %v, want %v", values, got, want)
+	}
+}
+
+func TestBankcardTranslation(t *testing.T) {
+	dir := t.TempDir()
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type PayReq struct {\n"+
+		"\tCard string `json:\"card\" validate:\"bankcard\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{EnableCustomValidation: true, EnableTranslator: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	if validation := readFile(t, dir, "validation.go"); strings.Contains(validation, "自定义验证方法") {
+		t.Errorf("validation.go stubs the built-in bankcard tag:\n%s", validation)
+	}
+	if translator := readFile(t, dir, "translator.go"); !strings.Contains(translator, "{0}必须是有效的银行卡号") {
+		t.Errorf("translator.go does not translate bankcard:\n%s", translator)
+	}
+}
+
+func TestChineseName(t *testing.T) {
+	values := []string{"张三", "阿卜杜·热合曼", "张", "Zhang San", "张三1"}
+	want := []bool{true, true, false, false, false}
+	// 启用自定义验证时chinesename同样使用内置的验证方法，不生成总是通过的空方法
+	if got := validateValues(t, Options{EnableCustomValidation: true}, "chinesename", values...); !slices.Equal(got, want) {
+		t.Errorf("chinesename %q = %v, want %v", values, got, want)
+	}
+}
+
+func TestPerStructValidator(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type AReq struct {\n"+
+		"\tA string `json:\"a\" validate:\"tag_a\"`\n"+
+		"}\n\n"+
+		"type BReq struct {\n"+
+		"\tB string `json:\"b\" validate:\"tag_b\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{EnableCustomValidation: true, PerStructValidator: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 每个结构体的验证器只注册该结构体使用的验证标签
+	types := readFile(t, dir, "types.go")
+	for _, want := range []string{`structValidator("AReq", "tag_a")`, `structValidator("BReq", "tag_b")`} {
+		if !strings.Contains(types, want) {
+			t.Errorf("types.go does not call %s:\n%s", want, types)
+		}
+	}
+	validation := strings.Replace(readFile(t, dir, "validation.go"), "// 在这里实现 tag_a 的验证逻辑\n\treturn true", "return false", 1)
+	writeTypesFile(t, dir, "validation.go", validation)
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.AReq{A: "x"}).Validate() == nil)
+	fmt.Println((&types.BReq{B: "x"}).Validate() == nil)
+}
+`)
+	if got != "false\ntrue\n" {
+		t.Errorf("Validate() = %q, want only AReq to use tag_a", got)
+	}
+}
+
+func TestSliceElementStructs(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type CartReq struct {\n"+
+		"\tItems []CartItem `json:\"items\" validate:\"required,dive\"`\n"+
+		"}\n\n"+
+		"type CartItem struct {\n"+
+		"\tCount int `json:\"count\" validate:\"min=1\"`\n"+
+		"}\n")
+	summary := &Summary{}
+	if _, err := ProcessTypesFile(false, file, Options{EnableCustomValidation: true}, summary); err != nil {
+		t.Fatal(err)
+	}
+	// 切片元素的结构体同样生成Validate方法，dive不是自定义标签
+	if !slices.Equal(summary.StructsProcessed, []string{"CartReq", "CartItem"}) || len(summary.CustomTags) != 0 {
+		t.Errorf("StructsProcessed = %v, CustomTags = %v, want [CartReq CartItem] and no custom tags", summary.StructsProcessed, summary.CustomTags)
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.CartReq{Items: []types.CartItem{{Count: 1}}}).Validate() == nil)
+	fmt.Println((&types.CartReq{Items: []types.CartItem{{Count: 1}, {Count: 0}}}).Validate() == nil)
+	fmt.Println((&types.CartItem{Count: 0}).Validate() == nil)
+}
+`)
+	if got != "true\nfalse\nfalse\n" {
+		t.Errorf("Validate() = %q, want the slice elements to be validated", got)
+	}
+}
+
+func TestNestedStructs(t *testing.T) {
+	dir := t.TempDir()
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type ListReq struct {\n"+
+		"\tQuery Query `json:\"query\"`\n"+
+		"}\n\n"+
+		"type Query struct {\n"+
+		"\tPage Page `json:\"page\"`\n"+
+		"}\n\n"+
+		"type Page struct {\n"+
+		"\tSize int `json:\"size\" validate:\"min=1\"`\n"+
+		"}\n")
+	summary := &Summary{}
+	if _, err := ProcessTypesFile(false, file, Options{}, summary); err != nil {
+		t.Fatal(err)
+	}
+	// 只有最内层的结构体有验证标签，外层字段引用的同文件结构体都生成Validate方法
+	if want := []string{"ListReq", "Query", "Page"}; !slices.Equal(summary.StructsProcessed, want) {
+		t.Errorf("StructsProcessed = %v, want %v", summary.StructsProcessed, want)
+	}
+}
+
+func TestImportsJoinExistingBlock(t *testing.T) {
+	dir := t.TempDir()
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"import (\n"+
+		"\t\"time\"\n"+
+		")\n\n"+
+		"type EventReq struct {\n"+
+		"\tAt time.Time `json:\"at\" validate:\"required\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{GenerateContextMethod: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	types := readFile(t, dir, "types.go")
+	if formatted, err := format.Source([]byte(types)); err != nil || string(formatted) != types {
+		t.Fatalf("types.go is not gofmt formatted (%v):\n%s", err, types)
+	}
+	// 新增的导入与time在同一个导入分组中
+	f, err := parser.ParseFile(token.NewFileSet(), "types.go", types, parser.ImportsOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, decl := range f.Decls {
+		gen := decl.(*ast.GenDecl)
+		if len(paths) > 0 {
+			t.Fatalf("types.go has more than one import declaration:\n%s", types)
+		}
+		for _, spec := range gen.Specs {
+			paths = append(paths, spec.(*ast.ImportSpec).Path.Value)
+		}
+	}
+	for _, want := range []string{`"context"`, `"github.com/go-playground/validator/v10"`, `"time"`} {
+		if !slices.Contains(paths, want) {
+			t.Errorf("imports = %v, want %s in the same block", paths, want)
+		}
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	dir := t.TempDir()
+	file := writeTypesFile(t, dir, "types.go", testTypesSrc)
+	before := snapshotDir(t, dir)
+	summary := &Summary{}
+	options := Options{EnableCustomValidation: true, EnableTranslator: true, DryRun: true}
+	if _, err := ProcessTypesFile(false, file, options, summary); err != nil {
+		t.Fatal(err)
+	}
+	if after := snapshotDir(t, dir); !maps.Equal(after, before) {
+		t.Errorf("DryRun changed files on disk: %v", slices.Sorted(maps.Keys(after)))
+	}
+	if len(summary.FilesWritten) != 0 || len(summary.FilesSkipped) == 0 {
+		t.Errorf("FilesWritten = %v, FilesSkipped = %v, want every file skipped", summary.FilesWritten, summary.FilesSkipped)
+	}
+}
+
+func TestConfigValidators(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	config := writeTypesFile(t, root, "validators.yaml", `validators:
+  - tag: mobile_hk
+    regex: "^[569]\\d{7}$"
+    message: "{0}必须是香港手机号"
+  - tag: postcode
+    regex: '^\d{6}$'
+    message: '{0}必须是"6位"邮编'
+`)
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type AddressReq struct {\n"+
+		"\tMobile   string `json:\"mobile\" validate:\"mobile_hk\"`\n"+
+		"\tPostcode string `json:\"postcode\" validate:\"postcode\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{EnableCustomValidation: true, EnableTranslator: true, ConfigPath: config}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 配置文件定义的标签根据正则生成验证方法，不生成空方法
+	validation := readFile(t, dir, "validation.go")
+	if strings.Contains(validation, "自定义验证方法") {
+		t.Errorf("validation.go stubs a tag defined in the config file:\n%s", validation)
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.AddressReq{Mobile: "51234567", Postcode: "100000"}).Validate())
+	fmt.Println((&types.AddressReq{Mobile: "13800138000", Postcode: "100000"}).Validate())
+	fmt.Println((&types.AddressReq{Mobile: "51234567", Postcode: "1000"}).Validate())
+}
+`)
+	if want := "<nil>\nmobile必须是香港手机号\npostcode必须是\"6位\"邮编\n"; got != want {
+		t.Errorf("Validate() = %q, want %q", got, want)
+	}
+}
+
+func TestStructLevelValidation(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"// +validate:struct\n"+
+		"type RangeReq struct {\n"+
+		"\tStartDate int `json:\"startDate\"`\n"+
+		"\tEndDate   int `json:\"endDate\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{}, file); err != nil {
+		t.Fatal(err)
+	}
+	var path string
+	for name, content := range snapshotDir(t, dir) {
+		if strings.Contains(content, "validate.RegisterStructValidation(RangeReqStructLevel, RangeReq{})") {
+			path = name
+		}
+	}
+	if path == "" {
+		t.Fatalf("RangeReqStructLevel is not registered:\n%s", readFile(t, dir, "validation.go"))
+	}
+	// 按模板中的示例实现跨字段的验证
+	content := readFile(t, dir, path)
+	for _, line := range []string{
+		"req := sl.Current().Interface().(RangeReq)",
+		"if req.EndDate.Before(req.StartDate) {",
+		"\tsl.ReportError(req.EndDate, \"EndDate\", \"EndDate\", \"gtfield\", \"StartDate\")",
+		"}",
+	} {
+		content = strings.Replace(content, "// "+line, line, 1)
+	}
+	content = strings.Replace(content, "req.EndDate.Before(req.StartDate)", "req.EndDate < req.StartDate", 1)
+	writeTypesFile(t, dir, path, content)
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.RangeReq{StartDate: 1, EndDate: 2}).Validate() == nil)
+	fmt.Println((&types.RangeReq{StartDate: 2, EndDate: 1}).Validate() == nil)
+}
+`)
+	if got != "true\nfalse\n" {
+		t.Errorf("Validate() = %q, want the struct level validation to run", got)
+	}
+}
+
+func TestCustomizedBuiltInSurvivesRegeneration(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type IdentityReq struct {\n"+
+		"\tIdCard string `json:\"idCard\" validate:\"idcard\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 用户在验证文件中修改了validateIdCard，重新执行时保留修改
+	validation := strings.Replace(readFile(t, dir, "validation.go"), "// 支持15位或18位身份证号", "// 支持15位或18位身份证号，已修改", 1)
+	writeTypesFile(t, dir, "validation.go", validation)
+	if err := processFiles(t, Options{}, file); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(readFile(t, dir, "validation.go"), "已修改") {
+		t.Errorf("rerunning the plugin overwrote the customized validateIdCard")
+	}
+	// 用户将validateIdCard移到单独的文件中并修改了实现
+	custom := "package types\n\n" +
+		"import \"github.com/go-playground/validator/v10\"\n\n" +
+		"func validateIdCard(fl validator.FieldLevel) bool {\n" +
+		"\treturn fl.Field().String() == \"custom\"\n" +
+		"}\n"
+	writeTypesFile(t, dir, "idcard.go", custom)
+	// 重新生成验证文件时不再生成默认的validateIdCard，只保留注册
+	if err := processFiles(t, Options{Force: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	validation = readFile(t, dir, "validation.go")
+	if strings.Contains(validation, "func validateIdCard(") || !strings.Contains(validation, "validateIdCard") {
+		t.Errorf("validation.go does not keep the customized validateIdCard:\n%s", validation)
+	}
+	if got := readFile(t, dir, "idcard.go"); got != custom {
+		t.Errorf("idcard.go changed:\n%s", got)
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.IdentityReq{IdCard: "custom"}).Validate() == nil)
+	fmt.Println((&types.IdentityReq{IdCard: "11010519491231002X"}).Validate() == nil)
+}
+`)
+	if got != "true\nfalse\n" {
+		t.Errorf("Validate() = %q, want the customized validateIdCard to be registered", got)
+	}
+}
+
+func TestIgnoreDirective(t *testing.T) {
+	dir := t.TempDir()
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type CreateReq struct {\n"+
+		"\tName string `json:\"name\" validate:\"required\"`\n"+
+		"}\n\n"+
+		"// +validate:ignore\n"+
+		"type InternalReq struct {\n"+
+		"\tName string `json:\"name\" validate:\"required\"`\n"+
+		"}\n")
+	summary := &Summary{}
+	if _, err := ProcessTypesFile(false, file, Options{}, summary); err != nil {
+		t.Fatal(err)
+	}
+	types := readFile(t, dir, "types.go")
+	if !strings.Contains(types, "func (r *CreateReq) Validate() error") || strings.Contains(types, "func (r *InternalReq) Validate") {
+		t.Errorf("types.go does not generate Validate for CreateReq only:\n%s", types)
+	}
+	if !slices.Equal(summary.StructsProcessed, []string{"CreateReq"}) {
+		t.Errorf("StructsProcessed = %v, want [CreateReq]", summary.StructsProcessed)
+	}
+}
+
+func TestContextMethod(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", mobileTypesSrc)
+	if err := processFiles(t, Options{GenerateContextMethod: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	types := readFile(t, dir, "types.go")
+	for _, want := range []string{"func (r *CreateUserReq) Validate() error", "func (r *CreateUserReq) ValidateCtx(ctx context.Context) error", "validate.StructCtx(ctx, r)", `"context"`} {
+		if !strings.Contains(types, want) {
+			t.Errorf("types.go does not contain %s:\n%s", want, types)
+		}
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"context"
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.CreateUserReq{Name: "name", Mobile: "13800138000"}).ValidateCtx(context.Background()) == nil)
+	fmt.Println((&types.CreateUserReq{Name: "name", Mobile: "12345"}).ValidateCtx(context.Background()) == nil)
+}
+`)
+	if got != "true\nfalse\n" {
+		t.Errorf("ValidateCtx() = %q, want the mobile to be validated", got)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	result, err := Generate([]byte(testTypesSrc), "types", Options{EnableCustomValidation: true, EnableTranslator: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := []struct {
+		name    string
+		content []byte
+		want    string
+	}{
+		{"types.go", result.TypesFile, "func (r *CreateUserReq) Validate() error"},
+		{"validation.go", result.ValidationFile, "func validateAgeRange("},
+		{"translator.go", result.TranslatorFile, "func init() {"},
+	}
+	for _, file := range files {
+		if !strings.Contains(string(file.content), file.want) {
+			t.Errorf("%s does not contain %s:\n%s", file.name, file.want, file.content)
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), file.name, file.content, 0); err != nil {
+			t.Errorf("%s is not valid Go: %v", file.name, err)
+		}
+	}
+	if !slices.Equal(result.Structs, []string{"CreateUserReq"}) || !slices.Equal(result.CustomTags, []string{"age_range"}) {
+		t.Errorf("Structs = %v, CustomTags = %v, want [CreateUserReq] and [age_range]", result.Structs, result.CustomTags)
+	}
+}
+
+func TestGenerateWithoutStructs(t *testing.T) {
+	result, err := Generate([]byte("package types\n\ntype Resp struct {\n\tName string\n}\n"), "types", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TypesFile != nil || result.ValidationFile != nil || result.TranslatorFile != nil {
+		t.Errorf("Generate() = %+v, want no generated files", result)
+	}
+}
+
+func TestOutputFileNames(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", mobileTypesSrc)
+	options := Options{EnableTranslator: true, ValidationFileName: "rules.go", TranslatorFileName: "i18n.go"}
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	files := snapshotDir(t, dir)
+	if want := []string{"i18n.go", "rules.go", "types.go"}; !slices.Equal(slices.Sorted(maps.Keys(files)), want) {
+		t.Errorf("generated files = %v, want %v", slices.Sorted(maps.Keys(files)), want)
+	}
+	if got := runGenerated(t, root, mobileMain); got != "true\ntrue\n" {
+		t.Errorf("Validate() = %q, want the mobile to be validated", got)
+	}
+}
+
+func TestInvalidOutputFileNames(t *testing.T) {
+	tests := []Options{
+		{ValidationFileName: "rules"},
+		{ValidationFileName: "rules_test.go"},
+		{ValidationFileName: "dir/rules.go"},
+		{TranslatorFileName: `dir\i18n.go`},
+		{ValidationFileName: "same.go", TranslatorFileName: "same.go"},
+		{ValidationFileName: ErrorCodeFileName},
+	}
+	for _, options := range tests {
+		if _, _, err := outputFileNames(options); err == nil {
+			t.Errorf("outputFileNames(%q, %q) succeeded, want an error", options.ValidationFileName, options.TranslatorFileName)
+		}
+	}
+}
+
+func TestEmbeddedStructTags(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type AuditReq struct {\n"+
+		"\tMobile string `json:\"mobile\" validate:\"mobile,audit_code\"`\n"+
+		"}\n\n"+
+		"type UpdateUser struct {\n"+
+		"\tAuditReq\n"+
+		"\tName string `json:\"name\"`\n"+
+		"}\n")
+	summary := &Summary{}
+	if _, err := ProcessTypesFile(false, file, Options{EnableCustomValidation: true, EnableTranslator: true}, summary); err != nil {
+		t.Fatal(err)
+	}
+	// 匿名嵌入的结构体的验证标签同样收集，外层结构体生成Validate方法
+	if !slices.Contains(summary.StructsProcessed, "UpdateUser") || !slices.Equal(summary.CustomTags, []string{"audit_code"}) {
+		t.Errorf("StructsProcessed = %v, CustomTags = %v, want UpdateUser and [audit_code]", summary.StructsProcessed, summary.CustomTags)
+	}
+	if translator := readFile(t, dir, "translator.go"); !strings.Contains(translator, "手机号码格式不正确") {
+		t.Errorf("translator.go does not translate mobile:\n%s", translator)
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.UpdateUser{AuditReq: types.AuditReq{Mobile: "13800138000"}}).Validate())
+	fmt.Println((&types.UpdateUser{AuditReq: types.AuditReq{Mobile: "12345"}}).Validate())
+}
+`)
+	if want := "<nil>\nmobile手机号码格式不正确\n"; got != want {
+		t.Errorf("Validate() = %q, want %q", got, want)
+	}
+}
+
+func TestValidateMethodOrder(t *testing.T) {
+	src := "package types\n\n" +
+		"type ZetaReq struct {\n" +
+		"\tName string `json:\"name\" validate:\"required\"`\n" +
+		"}\n\n" +
+		"const maxSize = 10\n\n" +
+		"type AlphaReq struct {\n" +
+		"\tSize int `json:\"size\" validate:\"max=10\"`\n" +
+		"}\n\n" +
+		"func helper() {}\n\n" +
+		"type MidReq struct {\n" +
+		"\tCode string `json:\"code\" validate:\"len=6\"`\n" +
+		"}\n"
+	options := Options{EnableCustomValidation: true, EnableTranslator: true}
+	first, err := Generate([]byte(src), "types", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 多次生成的结果完全相同
+	for range 5 {
+		again, err := Generate([]byte(src), "types", options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(again.TypesFile) != string(first.TypesFile) || string(again.ValidationFile) != string(first.ValidationFile) || string(again.TranslatorFile) != string(first.TranslatorFile) {
+			t.Fatalf("Generate() is not deterministic")
+		}
+	}
+	// Validate方法按结构体的声明顺序连续写在文件末尾
+	types := string(first.TypesFile)
+	last := strings.Index(types, "type MidReq struct")
+	for _, name := range []string{"ZetaReq", "AlphaReq", "MidReq"} {
+		i := strings.Index(types, "func (r *"+name+") Validate() error")
+		if i < last {
+			t.Fatalf("Validate methods are not in declaration order after the original declarations:\n%s", types)
+		}
+		last = i
+	}
+	methods := types[strings.Index(types, "func (r *ZetaReq) Validate() error"):]
+	if strings.Contains(methods, "\ntype ") || strings.Contains(methods, "\nconst ") || strings.Contains(methods, "func helper") {
+		t.Errorf("Validate methods are not contiguous at the end of the file:\n%s", types)
+	}
+}
+
+func TestFieldMessage(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type ArticleReq struct {\n"+
+		"\tName  string `json:\"name\" validate:\"required\" msg:\"名称不能为空\"`\n"+
+		"\tTitle string `json:\"title\" validate:\"required\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{EnableTranslator: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 只有带msg标签的字段使用自定义的错误信息，其他字段使用默认翻译
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.ArticleReq{Title: "title"}).Validate())
+	fmt.Println((&types.ArticleReq{Name: "name"}).Validate())
+}
+`)
+	if want := "名称不能为空\ntitle为必填字段\n"; got != want {
+		t.Errorf("Validate() = %q, want %q", got, want)
+	}
+}
+
+func TestMustValidate(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", mobileTypesSrc)
+	// 重复执行时不重复生成MustValidate
+	for range 2 {
+		if err := processFiles(t, Options{GenerateMust: true}, file); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := strings.Count(readFile(t, dir, "types.go"), "func (r *CreateUserReq) MustValidate()"); n != 1 {
+		t.Fatalf("types.go declares MustValidate %d times, want 1", n)
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func mustValidate(req *types.CreateUserReq) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+	req.MustValidate()
+	return false
+}
+
+func main() {
+	fmt.Println(mustValidate(&types.CreateUserReq{Name: "name", Mobile: "13800138000"}))
+	fmt.Println(mustValidate(&types.CreateUserReq{Name: "name", Mobile: "12345"}))
+}
+`)
+	if got != "false\ntrue\n" {
+		t.Errorf("MustValidate() panicked = %q, want a panic on invalid input only", got)
+	}
+}
+
+func TestJapaneseTranslation(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", mobileTypesSrc)
+	if err := processFiles(t, Options{EnableTranslator: true, TranslationLanguage: "ja"}, file); err != nil {
+		t.Fatal(err)
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.CreateUserReq{Mobile: "13800138000"}).Validate())
+	fmt.Println((&types.CreateUserReq{Name: "name", Mobile: "12345"}).Validate())
+}
+`)
+	if want := "nameは必須フィールドです\nmobileは有効な携帯電話番号でなければなりません\n"; got != want {
+		t.Errorf("Validate() = %q, want %q", got, want)
+	}
+}
+
+func TestTranslationMessageFallback(t *testing.T) {
+	// 只有英文翻译的标签
+	translationLanguages["en"]["en_only"] = "{0} is english only"
+	t.Cleanup(func() { delete(translationLanguages["en"], "en_only") })
+	tests := []struct {
+		lang, tag, want string
+	}{
+		{"ja", "mobile", translationLanguages["ja"]["mobile"]},
+		// 日语没有提供翻译时使用英文翻译
+		{"ja", "en_only", "{0} is english only"},
+		{"ko", "unknown_tag", translationLanguages["ko"][""]},
+	}
+	for _, tt := range tests {
+		if got := translationMessage(tt.lang, tt.tag); got != tt.want {
+			t.Errorf("translationMessage(%q, %q) = %q, want %q", tt.lang, tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestAliasedValidatorImport(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"import valid \"github.com/go-playground/validator/v10\"\n\n"+
+		"var _ valid.FieldLevel\n\n"+
+		"type CreateUserReq struct {\n"+
+		"\tName   string `json:\"name\" validate:\"required,min=2\"`\n"+
+		"\tMobile string `json:\"mobile\" validate:\"required,mobile\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 生成的代码使用已有的导入名，不重复导入validator
+	types := readFile(t, dir, "types.go")
+	if n := strings.Count(types, `"github.com/go-playground/validator/v10"`); n != 1 {
+		t.Errorf("types.go imports validator %d times, want 1:\n%s", n, types)
+	}
+	if strings.Contains(types, "validator.") {
+		t.Errorf("types.go does not use the valid alias:\n%s", types)
+	}
+	if got := runGenerated(t, root, mobileMain); got != "true\ntrue\n" {
+		t.Errorf("Validate() = %q, want the mobile to be validated", got)
+	}
+}
+
+func TestSkipMethodGeneration(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", mobileTypesSrc)
+	if err := processFiles(t, Options{EnableTranslator: true, SkipMethodGeneration: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	// types.go保持不变，验证文件中声明验证器变量并注册mobile
+	if types := readFile(t, dir, "types.go"); types != mobileTypesSrc {
+		t.Errorf("types.go changed with SkipMethodGeneration:\n%s", types)
+	}
+	if validation := readFile(t, dir, "validation.go"); !strings.Contains(validation, "validateMobile") {
+		t.Fatalf("validation.go does not register mobile:\n%s", validation)
+	}
+	// 用户自行实现的Validate方法使用生成的验证器
+	writeTypesFile(t, dir, "methods.go", "package types\n\n"+
+		"func (r *CreateUserReq) Validate() error {\n"+
+		"\treturn validate.Struct(r)\n"+
+		"}\n")
+	if got := runGenerated(t, root, mobileMain); got != "true\ntrue\n" {
+		t.Errorf("Validate() = %q, want the mobile to be validated", got)
+	}
+}
+
+func TestParamCustomTagStub(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type AreaReq struct {\n"+
+		"\tRadius int    `json:\"radius\" validate:\"within=10\"`\n"+
+		"\tCode   string `json:\"code\" validate:\"area_code\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{EnableCustomValidation: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 带参数的标签读取参数，不带参数的标签不读取
+	validation := readFile(t, dir, "validation.go")
+	within := validation[strings.Index(validation, "func validateWithin("):]
+	within = within[:strings.Index(within, "\n}\n")]
+	area := validation[strings.Index(validation, "func validateAreaCode("):]
+	area = area[:strings.Index(area, "\n}\n")]
+	if !strings.Contains(within, "param := fl.Param()") || strings.Contains(area, "fl.Param()") {
+		t.Errorf("validation.go does not read the param of within only:\n%s", validation)
+	}
+	goCommand(t, root, "vet", "./types")
+}
+
+func TestPruneDroppedTag(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	src := "package types\n\n" +
+		"type ItemReq struct {\n" +
+		"\tSku  string `json:\"sku\" validate:\"sku\"`\n" +
+		"\tCode string `json:\"code\" validate:\"item_code\"`\n" +
+		"}\n"
+	file := writeTypesFile(t, dir, "types.go", src)
+	options := Options{EnableCustomValidation: true, EnableTranslator: true, Prune: true}
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	// 删除item_code标签后重新执行，只保留仍在使用的sku
+	types := strings.Replace(readFile(t, dir, "types.go"), ` validate:"item_code"`, "", 1)
+	writeTypesFile(t, dir, "types.go", types)
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	validation := readFile(t, dir, "validation.go")
+	if strings.Contains(validation, `"item_code"`) || !strings.Contains(validation, `"sku"`) {
+		t.Errorf("validation.go does not prune item_code only:\n%s", validation)
+	}
+	if translator := readFile(t, dir, "translator.go"); strings.Contains(translator, `"item_code"`) || !strings.Contains(translator, `"sku"`) {
+		t.Errorf("translator.go does not prune item_code only:\n%s", translator)
+	}
+	goCommand(t, root, "vet", "./types")
+}
+
+// registerMap 获取验证文件中registerValidation映射的代码
+func registerMap(t *testing.T, validation string) string {
+	t.Helper()
+	start := strings.Index(validation, "var registerValidation = map[string]validator.Func{")
+	if start < 0 {
+		t.Fatalf("validation file has no registerValidation map:\n%s", validation)
+	}
+	end := strings.Index(validation[start:], "\n}\n")
+	return validation[start : start+end]
+}
+
+func TestRegisterMapCanonical(t *testing.T) {
+	options := Options{EnableCustomValidation: true}
+	src := "package types\n\n" +
+		"type ItemReq struct {\n" +
+		"\tSku string `json:\"sku\" validate:\"sku\"`\n" +
+		"}\n"
+	want := "package types\n\n" +
+		"type ItemReq struct {\n" +
+		"\tSku  string `json:\"sku\" validate:\"sku\"`\n" +
+		"\tCode string `json:\"code\" validate:\"item_code\"`\n" +
+		"}\n"
+	fresh := t.TempDir()
+	if err := processFiles(t, options, writeTypesFile(t, fresh, "types.go", want)); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	file := writeTypesFile(t, dir, "types.go", src)
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	// 手动修改过格式的映射在追加新标签时恢复为统一的格式
+	validation := readFile(t, dir, "validation.go")
+	unaligned := regexp.MustCompile(`":\s+`).ReplaceAllString(registerMap(t, validation), `": `)
+	unaligned = regexp.MustCompile(`,\s+//`).ReplaceAllString(unaligned, `, //`)
+	validation = strings.Replace(validation, registerMap(t, validation), unaligned, 1)
+	validation = strings.Replace(validation, "validateMobile, // 手机号验证", "validateMobile,", 1)
+	writeTypesFile(t, dir, "validation.go", validation)
+	types := strings.Replace(readFile(t, dir, "types.go"), "\tSku string `json:\"sku\" validate:\"sku\"`\n", "\tSku  string `json:\"sku\" validate:\"sku\"`\n\tCode string `json:\"code\" validate:\"item_code\"`\n", 1)
+	writeTypesFile(t, dir, "types.go", types)
+	for range 2 {
+		if err := processFiles(t, options, file); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := registerMap(t, readFile(t, dir, "validation.go")), registerMap(t, readFile(t, fresh, "validation.go")); got != want {
+			t.Fatalf("registerValidation =\n%s\nwant\n%s", got, want)
+		}
+	}
+}
+
+func TestValidatorPackage(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", mobileTypesSrc)
+	if err := processFiles(t, Options{EnableTranslator: true, ValidatorPackage: "internal/validate"}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 验证文件和翻译器文件生成到验证包中，types包通过导入验证包验证
+	pkgDir := filepath.Join(root, "internal", "validate")
+	for _, name := range []string{"validation.go", "translator.go"} {
+		f, err := parser.ParseFile(token.NewFileSet(), name, readFile(t, pkgDir, name), parser.PackageClauseOnly)
+		if err != nil || f.Name.Name != "validate" {
+			t.Errorf("internal/validate/%s is not in package validate: %v", name, err)
+		}
+		if readFile(t, dir, name) != "" {
+			t.Errorf("%s was generated in the types package", name)
+		}
+	}
+	if types := readFile(t, dir, "types.go"); !strings.Contains(types, `"example.com/gen/internal/validate"`) {
+		t.Errorf("types.go does not import the validator package:\n%s", types)
+	}
+	if got := runGenerated(t, root, mobileMain); got != "true\ntrue\n" {
+		t.Errorf("Validate() = %q, want the mobile to be validated", got)
+	}
+}
+
+func TestTagAliases(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	config := writeTypesFile(t, root, "validators.yaml", "aliases:\n  phone: mobile\n")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type ContactReq struct {\n"+
+		"\tPhone string `json:\"phone\" validate:\"required,phone\"`\n"+
+		"}\n")
+	summary := &Summary{}
+	if _, err := ProcessTypesFile(false, file, Options{EnableCustomValidation: true, EnableTranslator: true, ConfigPath: config}, summary); err != nil {
+		t.Fatal(err)
+	}
+	// phone按mobile验证，不生成自定义验证方法，结构体标签保持不变
+	if len(summary.CustomTags) != 0 {
+		t.Errorf("CustomTags = %v, want none", summary.CustomTags)
+	}
+	if types := readFile(t, dir, "types.go"); !strings.Contains(types, `validate:"required,phone"`) {
+		t.Errorf("types.go rewrote the phone tag:\n%s", types)
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.ContactReq{Phone: "13800138000"}).Validate())
+	fmt.Println((&types.ContactReq{Phone: "12345"}).Validate())
+}
+`)
+	if want := "<nil>\nphone手机号码格式不正确\n"; got != want {
+		t.Errorf("Validate() = %q, want %q", got, want)
+	}
+}
+
+func TestTypeCheck(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", mobileTypesSrc)
+	// 去掉验证文件中regexp的导入，模拟生成的代码缺少导入
+	dropImport := func(path string, content []byte) ([]byte, error) {
+		return bytes.Replace(content, []byte("\t\"regexp\"\n"), nil, 1), nil
+	}
+	err := processFiles(t, Options{TypeCheck: true, PostProcess: dropImport}, file)
+	if err == nil || !strings.Contains(err.Error(), "regexp") {
+		t.Fatalf("processFiles() error = %v, want an undefined regexp error", err)
+	}
+	// 未通过类型检查时不写入任何文件
+	if readFile(t, dir, "validation.go") != "" || readFile(t, dir, "types.go") != mobileTypesSrc {
+		t.Errorf("files were written although the type check failed")
+	}
+	if err := processFiles(t, Options{TypeCheck: true}, file); err != nil {
+		t.Fatalf("processFiles() error = %v, want the generated code to type check", err)
+	}
+}
+
+func TestIncludeSuffixes(t *testing.T) {
+	src := "package types\n\n" +
+		"type CommonResp struct {\n" +
+		"\tCode int `json:\"code\"`\n" +
+		"}\n\n" +
+		"type LoginReq struct {\n" +
+		"\tName string `json:\"name\"`\n" +
+		"}\n\n" +
+		"type UserForm struct {\n" +
+		"\tName string `json:\"name\" validate:\"required\"`\n" +
+		"}\n"
+	tests := []struct {
+		name     string
+		suffixes []string
+		want     []string
+	}{
+		// 默认按Req后缀处理，带验证标签的结构体始终处理
+		{"default", nil, []string{"LoginReq", "UserForm"}},
+		{"resp", []string{"Resp"}, []string{"CommonResp", "UserForm"}},
+		{"req and resp", []string{"Req", "Resp"}, []string{"CommonResp", "LoginReq", "UserForm"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Generate([]byte(src), "types", Options{IncludeSuffixes: tt.suffixes})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(result.Structs, tt.want) {
+				t.Errorf("Structs = %v, want %v", result.Structs, tt.want)
+			}
+		})
+	}
+}
+
+func TestValueReceiverValidate(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	src := mobileTypesSrc + "\n" +
+		"func (r CreateUserReq) Validate() error {\n" +
+		"\treturn nil\n" +
+		"}\n"
+	file := writeTypesFile(t, dir, "types.go", src)
+	if err := processFiles(t, Options{}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 已有值接收者的Validate方法时不再生成指针接收者的方法
+	if types := readFile(t, dir, "types.go"); strings.Count(types, ") Validate() error") != 1 {
+		t.Errorf("types.go declares Validate more than once:\n%s", types)
+	}
+	goCommand(t, root, "vet", "./types")
+}
+
+func TestConfigMessagesPerLanguage(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	config := writeTypesFile(t, root, "validators.yaml", `validators:
+  - tag: postcode
+    regex: '^\d{6}$'
+    messages:
+      zh: "{0}必须是6位邮编"
+      en: "{0} must be a 6-digit postcode"
+`)
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type AddressReq struct {\n"+
+		"\tPostcode string `json:\"postcode\" validate:\"postcode\"`\n"+
+		"}\n")
+	options := Options{EnableTranslator: true, TranslationLanguages: []string{"zh", "en"}, ConfigPath: config}
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	// 非默认语言的翻译器注册该语言的翻译
+	if translator := readFile(t, dir, "translator.go"); !containsCode(translator, `_ = enTranslator.Add("postcode", "{0} must be a 6-digit postcode", true)`) {
+		t.Errorf("translator.go does not register the en message:\n%s", translator)
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.AddressReq{Postcode: "1000"}).Validate())
+}
+`)
+	if want := "postcode必须是6位邮编\n"; got != want {
+		t.Errorf("Validate() = %q, want %q", got, want)
+	}
+}
+
+func TestTranslateHelpers(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", mobileTypesSrc)
+	if err := processFiles(t, Options{EnableTranslator: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 翻译器文件同时提供返回error的Translate和返回string的GetValidateErrorMsg
+	translator := readFile(t, dir, "translator.go")
+	for _, want := range []string{"func Translate(err error) error {", "func GetValidateErrorMsg(err error) string {"} {
+		if !strings.Contains(translator, want) {
+			t.Errorf("translator.go does not declare %s\n%s", want, translator)
+		}
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	var _ func(error) error = types.Translate
+	var _ func(error) string = types.GetValidateErrorMsg
+	fmt.Printf("%q\n", types.GetValidateErrorMsg(nil))
+	fmt.Println(types.GetValidateErrorMsg((&types.CreateUserReq{Name: "name", Mobile: "12345"}).Validate()))
+}
+`)
+	if want := "\"\"\nmobile手机号码格式不正确\n"; got != want {
+		t.Errorf("GetValidateErrorMsg() = %q, want %q", got, want)
+	}
+}
+
+func TestVsemver(t *testing.T) {
+	values := []string{"1.2.3", "v1.2.3-rc.1", "v1.2", "1.2.3.4", "version1"}
+	want := []bool{true, true, false, false, false}
+	if got := validateValues(t, Options{EnableCustomValidation: true}, "vsemver", values...); !slices.Equal(got, want) {
+		t.Errorf("vsemver %q = %v, want %v", values, got, want)
+	}
+	// validator内置的semver不接受v前缀
+	if got := validateValues(t, Options{EnableCustomValidation: true}, "semver", "1.2.3", "v1.2.3"); !slices.Equal(got, []bool{true, false}) {
+		t.Errorf("semver = %v, want [true false]", got)
+	}
+}
+
+func TestValueReceiver(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", mobileTypesSrc)
+	// 重复执行时按值接收者识别已生成的方法
+	for range 2 {
+		if err := processFiles(t, Options{ValueReceiver: true}, file); err != nil {
+			t.Fatal(err)
+		}
+	}
+	types := readFile(t, dir, "types.go")
+	if strings.Count(types, "func (r CreateUserReq) Validate() error") != 1 || strings.Contains(types, "func (r *CreateUserReq)") {
+		t.Fatalf("types.go does not declare Validate once with a value receiver:\n%s", types)
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println(types.CreateUserReq{Name: "name", Mobile: "13800138000"}.Validate() == nil)
+	fmt.Println(types.CreateUserReq{Name: "name", Mobile: "12345"}.Validate() == nil)
+}
+`)
+	if got != "true\nfalse\n" {
+		t.Errorf("Validate() = %q, want the mobile to be validated", got)
+	}
+}
+
+func TestCommentRules(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type CreateUserReq struct {\n"+
+		"\t// validate: required,min=2\n"+
+		"\tName   string `json:\"name\"`\n"+
+		"\tMobile string `json:\"mobile\"` // validate: required,mobile\n"+
+		"\tAge    int\n"+
+		"}\n")
+	if err := processFiles(t, Options{}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 注释中的验证规则写回为validate标签，与字段已有的json标签合并
+	types := readFile(t, dir, "types.go")
+	for _, want := range []string{"`json:\"name\" validate:\"required,min=2\"`", "`json:\"mobile\" validate:\"required,mobile\"`"} {
+		if !strings.Contains(types, want) {
+			t.Errorf("types.go does not contain %s:\n%s", want, types)
+		}
+	}
+	if got := runGenerated(t, root, mobileMain); got != "true\ntrue\n" {
+		t.Errorf("Validate() = %q, want the comment rules to be validated", got)
+	}
+}
+
+func TestValidateJSON(t *testing.T) {
+	root := newTestModule(t)
+	file := writeTypesFile(t, filepath.Join(root, "types"), "types.go", "package types\n\n"+
+		"type CreateUserReq struct {\n"+
+		"\tUserName string `json:\"user_name\" validate:\"required,min=2\"`\n"+
+		"\tMobile   string `json:\"mobile,omitempty\" validate:\"required,mobile\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{EnableTranslator: true, GenerateJSONMethod: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 键为json标签中的字段名（去掉omitempty等选项），值为翻译后的错误信息；验证通过时返回空map
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.CreateUserReq{UserName: "a", Mobile: "12345"}).ValidateJSON())
+	fmt.Println((&types.CreateUserReq{UserName: "name", Mobile: "13800138000"}).ValidateJSON())
+}
+`)
+	want := "map[mobile:mobile手机号码格式不正确 user_name:user_name长度必须至少为2个字符] <nil>\nmap[] <nil>\n"
+	if got != want {
+		t.Errorf("ValidateJSON() output = %q, want %q", got, want)
+	}
+}
+
+func TestCustomValidationCtx(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type PayReq struct {\n"+
+		"\tAmount int `json:\"amount\" validate:\"balance\"`\n"+
+		"}\n")
+	options := Options{EnableCustomValidation: true, EnableTranslator: true, CustomValidationCtx: true, GenerateContextMethod: true}
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	validation := readFile(t, dir, "validation.go")
+	if !containsCode(validation, `_ = validate.RegisterValidationCtx("balance", validateBalanceCtx)`) {
+		t.Fatalf("validation.go does not register balance with RegisterValidationCtx:\n%s", validation)
+	}
+	// 验证方法读取ValidateCtx传入的上下文，并记录翻译时优先使用的错误信息
+	validation = strings.Replace(validation, "// 在这里实现 balance 的验证逻辑\n\treturn true", `balance, _ := ctx.Value("balance").(int)
+	if int(fl.Field().Int()) > balance {
+		setValidationMessage(fl, fmt.Sprintf("余额不足，当前余额%d", balance))
+		return false
+	}
+	return true`, 1)
+	if !strings.Contains(validation, "\t\"fmt\"\n") {
+		validation = strings.Replace(validation, "import (\n", "import (\n\t\"fmt\"\n", 1)
+	}
+	writeTypesFile(t, dir, "validation.go", validation)
+	got := runGenerated(t, root, `package main
+
+import (
+	"context"
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	ctx := context.WithValue(context.Background(), "balance", 100)
+	fmt.Println((&types.PayReq{Amount: 200}).ValidateCtx(ctx))
+	fmt.Println((&types.PayReq{Amount: 50}).ValidateCtx(ctx))
+}
+`)
+	if want := "余额不足，当前余额100\n<nil>\n"; got != want {
+		t.Errorf("ValidateCtx() output = %q, want %q", got, want)
+	}
+}
+
+func TestEmails(t *testing.T) {
+	values := []string{"a@example.com", "a@example.com, b@example.org", "a@example.com,invalid", "a@example.com,", ""}
+	want := []bool{true, true, false, false, false}
+	if got := validateValues(t, Options{}, "emails", values...); !slices.Equal(got, want) {
+		t.Errorf("emails %q = %v, want %v", values, got, want)
+	}
+}
+
+func TestEmailsTranslation(t *testing.T) {
+	dir := t.TempDir()
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type MailReq struct {\n"+
+		"\tTo string `json:\"to\" validate:\"emails\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{EnableCustomValidation: true, EnableTranslator: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	if validation := readFile(t, dir, "validation.go"); strings.Contains(validation, "自定义验证方法") {
+		t.Errorf("validation.go stubs the built-in emails tag:\n%s", validation)
+	}
+	if translator := readFile(t, dir, "translator.go"); !strings.Contains(translator, "{0}必须是有效的邮箱列表") {
+		t.Errorf("translator.go has no emails translation:\n%s", translator)
+	}
+}
+
+func TestPostProcess(t *testing.T) {
+	dir := t.TempDir()
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"// marker\n"+
+		"type CreateUserReq struct {\n"+
+		"\tName string `json:\"name\" validate:\"required\"`\n"+
+		"}\n")
+	var paths []string
+	upper := func(path string, content []byte) ([]byte, error) {
+		paths = append(paths, filepath.Base(path))
+		return bytes.ReplaceAll(content, []byte("// marker"), []byte("// MARKER")), nil
+	}
+	options := Options{PostProcess: upper}
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(paths)
+	if want := []string{"types.go", "validation.go"}; !slices.Equal(paths, want) {
+		t.Errorf("PostProcess paths = %v, want %v", paths, want)
+	}
+	if types := readFile(t, dir, "types.go"); !strings.Contains(types, "// MARKER\n") || !strings.Contains(types, "Validate() error") {
+		t.Errorf("types.go was not post-processed:\n%s", types)
+	}
+	// 处理后的内容与现有文件相同时不重复写入
+	summary := &Summary{}
+	if _, err := ProcessTypesFile(false, file, options, summary); err != nil {
+		t.Fatal(err)
+	}
+	if len(summary.FilesWritten) != 0 {
+		t.Errorf("FilesWritten = %v, want none on the second run", summary.FilesWritten)
+	}
+}
+
+func TestTranslatorInsertWithBraces(t *testing.T) {
+	dir := t.TempDir()
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type CreateUserReq struct {\n"+
+		"\tCode string `json:\"code\" validate:\"tag_a\"`\n"+
+		"}\n")
+	options := Options{EnableCustomValidation: true, EnableTranslator: true}
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	// 翻译文本及注释中不成对的大括号
+	translator := regexp.MustCompile(`_ = trans\.Add\("tag_a", .*\n`).ReplaceAllLiteralString(readFile(t, dir, "translator.go"),
+		"// 不成对的大括号: }}\n\t_ = trans.Add(\"tag_a\", \"{0}格式必须是{{code}\", true)\n")
+	writeTypesFile(t, dir, "translator.go", translator)
+	src := strings.Replace(readFile(t, dir, "types.go"), `validate:"tag_a"`, `validate:"tag_a,tag_b"`, 1)
+	writeTypesFile(t, dir, "types.go", src)
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	translator = readFile(t, dir, "translator.go")
+	f, err := parser.ParseFile(token.NewFileSet(), "translator.go", translator, 0)
+	if err != nil {
+		t.Fatalf("translator.go does not parse: %v\n%s", err, translator)
+	}
+	// 新增的翻译添加到registerCustomTranslations函数体中，原有的翻译文本保持不变
+	var body string
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == "registerCustomTranslations" {
+			body = translator[fn.Body.Pos()-1 : fn.Body.End()-1]
+		}
+	}
+	if !strings.Contains(body, `_ = trans.Add("tag_b", `) || !strings.Contains(body, `"{0}格式必须是{{code}"`) {
+		t.Errorf("registerCustomTranslations does not contain the old and new translations:\n%s", body)
+	}
+}
+
+func TestCustomTranslationParam(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type CreateUserReq struct {\n"+
+		"\tAge int `json:\"age\" validate:\"min_age=18\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{EnableCustomValidation: true, EnableTranslator: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	validation := strings.Replace(readFile(t, dir, "validation.go"), "// 在这里实现 min_age 的验证逻辑\n\treturn true", "return false", 1)
+	writeTypesFile(t, dir, "validation.go", validation)
+	// 翻译文本中的{1}替换为标签的参数
+	translator := regexp.MustCompile(`_ = trans\.Add\("min_age", .*\n`).ReplaceAllLiteralString(readFile(t, dir, "translator.go"),
+		"_ = trans.Add(\"min_age\", \"{0}不能小于{1}\", true)\n")
+	writeTypesFile(t, dir, "translator.go", translator)
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.CreateUserReq{Age: 10}).Validate())
+}
+`)
+	if want := "age不能小于18\n"; got != want {
+		t.Errorf("Validate() = %q, want %q", got, want)
+	}
+}
+
+func TestLazyTranslator(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", mobileTypesSrc)
+	if err := processFiles(t, Options{EnableTranslator: true, LazyTranslator: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 包初始化时不创建翻译器，只在translator中通过sync.Once初始化
+	translator := readFile(t, dir, "translator.go")
+	if strings.Contains(translator, "\tinitTranslator()") || !containsCode(translator, "translatorOnce.Do(initTranslator)") {
+		t.Errorf("translator.go initializes the translator outside translator():\n%s", translator)
+	}
+	// 并发验证时翻译器只初始化一次，验证方法在包初始化时已注册
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+	"sync"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.CreateUserReq{Name: "name", Mobile: "13800138000"}).Validate())
+	errs := make([]error, 8)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = (&types.CreateUserReq{Name: "name", Mobile: "12345"}).Validate()
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err == nil || err.Error() != errs[0].Error() {
+			fmt.Println("mismatch:", err)
+		}
+	}
+	fmt.Println(errs[0])
+}
+`)
+	if want := "<nil>\nmobile手机号码格式不正确\n"; got != want {
+		t.Errorf("Validate() output = %q, want %q", got, want)
+	}
+}
+
+func TestCustomTagsSorted(t *testing.T) {
+	src := "package types\n\n" +
+		"type UserReq struct {\n" +
+		"\tName string `json:\"name\" validate:\"tag_c,tag_a\"`\n" +
+		"}\n\n" +
+		"type OrderReq struct {\n" +
+		"\tNo   string `json:\"no\" validate:\"tag_b,tag_a\"`\n" +
+		"\tCode string `json:\"code\" validate:\"tag_a\"`\n" +
+		"}\n"
+	options := Options{EnableCustomValidation: true, EnableTranslator: true}
+	// 不同结构体共用的自定义标签只生成一次，验证方法及翻译都按字母顺序排列，多次生成的结果相同
+	var first map[string]string
+	for range 5 {
+		dir := t.TempDir()
+		file := writeTypesFile(t, dir, "types.go", src)
+		if err := processFiles(t, options, file); err != nil {
+			t.Fatal(err)
+		}
+		validation, translator := readFile(t, dir, "validation.go"), readFile(t, dir, "translator.go")
+		for _, tc := range []struct {
+			name, content string
+			code          func(tag string) string
+		}{
+			{"validation.go", validation, func(tag string) string { return "func validate" + exportName(tag) + "(" }},
+			{"translator.go", translator, func(tag string) string { return fmt.Sprintf("_ = trans.Add(%q, ", tag) }},
+		} {
+			var positions []int
+			for _, tag := range []string{"tag_a", "tag_b", "tag_c"} {
+				code := tc.code(tag)
+				if strings.Count(tc.content, code) != 1 {
+					t.Fatalf("%s does not contain %q exactly once:\n%s", tc.name, code, tc.content)
+				}
+				positions = append(positions, strings.Index(tc.content, code))
+			}
+			if !slices.IsSorted(positions) {
+				t.Errorf("%s does not list tag_a, tag_b, tag_c in order:\n%s", tc.name, tc.content)
+			}
+		}
+		snapshot := snapshotDir(t, dir)
+		if first == nil {
+			first = snapshot
+		} else if !maps.Equal(snapshot, first) {
+			t.Fatalf("generated files differ between runs")
+		}
+	}
+	// 重新生成时新增的自定义标签同样按字母顺序追加
+	dir := t.TempDir()
+	file := writeTypesFile(t, dir, "types.go", src)
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	writeTypesFile(t, dir, "types.go", strings.Replace(readFile(t, dir, "types.go"), `validate:"tag_a"`, `validate:"tag_e,tag_d"`, 1))
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"validation.go", "translator.go"} {
+		content := readFile(t, dir, name)
+		d, e := strings.Index(content, "TagD"), strings.Index(content, "TagE")
+		if name == "translator.go" {
+			d, e = strings.Index(content, `"tag_d"`), strings.Index(content, `"tag_e"`)
+		}
+		if d < 0 || e < d {
+			t.Errorf("%s does not append tag_d before tag_e:\n%s", name, content)
+		}
+	}
+}
+
+func TestLabelTagName(t *testing.T) {
+	root := newTestModule(t)
+	file := writeTypesFile(t, filepath.Join(root, "types"), "types.go", "package types\n\n"+
+		"type CreateUserReq struct {\n"+
+		"\tName   string `json:\"name\" validate:\"required\" label:\"用户名\"`\n"+
+		"\tMobile string `json:\"mobile\" validate:\"required\"`\n"+
+		"\tCode   string `validate:\"required\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{EnableTranslator: true, UseLabelTag: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 错误信息中的字段名优先使用label标签，其次是json标签，都没有时使用字段名
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.CreateUserReq{Mobile: "13800138000", Code: "1"}).Validate())
+	fmt.Println((&types.CreateUserReq{Name: "name", Code: "1"}).Validate())
+	fmt.Println((&types.CreateUserReq{Name: "name", Mobile: "13800138000"}).Validate())
+}
+`)
+	if want := "用户名为必填字段\nmobile为必填字段\nCode为必填字段\n"; got != want {
+		t.Errorf("Validate() output = %q, want %q", got, want)
+	}
+}
+
+func TestInlineStructTags(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type CreateUserReq struct {\n"+
+		"\tName string `json:\"name\" validate:\"required\"`\n"+
+		"\tData struct {\n"+
+		"\t\tMobile string `json:\"mobile\" validate:\"required,mobile\"`\n"+
+		"\t\tCode   string `json:\"code\" validate:\"omitempty,sku\"`\n"+
+		"\t} `json:\"data\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{EnableCustomValidation: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 匿名结构体字段中的自定义标签同样生成验证方法
+	validation := readFile(t, dir, "validation.go")
+	if !strings.Contains(validation, "func validateSku(") {
+		t.Fatalf("validation.go does not stub the inline sku tag:\n%s", validation)
+	}
+	validation = strings.Replace(validation, "// 在这里实现 sku 的验证逻辑\n\treturn true", "return false", 1)
+	writeTypesFile(t, dir, "validation.go", validation)
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	req := &types.CreateUserReq{Name: "name"}
+	req.Data.Mobile = "12345"
+	fmt.Println(req.Validate() != nil)
+	req.Data.Mobile = "13800138000"
+	fmt.Println(req.Validate() == nil)
+	req.Data.Code = "x"
+	fmt.Println(req.Validate() != nil)
+}
+`)
+	if got != "true\ntrue\ntrue\n" {
+		t.Errorf("Validate() results = %q, want the inline mobile and sku tags to be enforced", got)
+	}
+}
+
+func TestTrimSpace(t *testing.T) {
+	values := []string{"13800138000", " 13800138000", "13800138000\t", " 12345 "}
+	tests := []struct {
+		name    string
+		options Options
+		want    []bool
+	}{
+		{"off", Options{}, []bool{true, false, false, false}},
+		// 只影响生成的验证方法，去掉首尾空白后再验证
+		{"on", Options{TrimSpace: true}, []bool{true, true, true, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateValues(t, tt.options, "mobile", values...); !slices.Equal(got, tt.want) {
+				t.Errorf("mobile %q = %v, want %v", values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleAliases(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	config := writeTypesFile(t, root, "validators.yaml", "rule_aliases:\n  username_rule: required,alphanum,min=3,max=20\n")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type RegisterReq struct {\n"+
+		"\tUsername string `json:\"username\" validate:\"username_rule\"`\n"+
+		"}\n")
+	summary := &Summary{}
+	if _, err := ProcessTypesFile(false, file, Options{EnableCustomValidation: true, EnableTranslator: true, ConfigPath: config}, summary); err != nil {
+		t.Fatal(err)
+	}
+	// 验证规则别名通过RegisterAlias注册，不生成自定义验证方法
+	if len(summary.CustomTags) != 0 {
+		t.Errorf("CustomTags = %v, want none", summary.CustomTags)
+	}
+	validation := readFile(t, dir, "validation.go")
+	for _, want := range []string{`"username_rule": "required,alphanum,min=3,max=20",`, "validate.RegisterAlias(alias, tags)"} {
+		if !containsCode(validation, want) {
+			t.Errorf("validation.go does not contain %q:\n%s", want, validation)
+		}
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	for _, name := range []string{"abc1", "ab", "a b c", ""} {
+		fmt.Println((&types.RegisterReq{Username: name}).Validate())
+	}
+}
+`)
+	// 别名中任一规则验证失败都使用别名的翻译
+	if want := "<nil>\n" + strings.Repeat("username格式不符合要求\n", 3); got != want {
+		t.Errorf("Validate() output = %q, want %q", got, want)
+	}
+}
+
+func TestRuneLength(t *testing.T) {
+	// 按字符数验证长度，汉字计为1个字符（UTF-8编码为3个字节）
+	values := []string{"张三", "ab", "张三a", "张", "张三李四王"}
+	tests := []struct {
+		tag  string
+		want []bool
+	}{
+		{"runelen=2", []bool{true, true, false, false, false}},
+		{"runemin=2", []bool{true, true, true, false, true}},
+		{"runemax=3", []bool{true, true, true, true, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := validateValues(t, Options{}, tt.tag, values...); !slices.Equal(got, tt.want) {
+				t.Errorf("%s %q = %v, want %v", tt.tag, values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuneLengthTranslation(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type CreateUserReq struct {\n"+
+		"\tName string `json:\"name\" validate:\"runemin=2,runemax=4\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{EnableCustomValidation: true, EnableTranslator: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	if validation := readFile(t, dir, "validation.go"); strings.Contains(validation, "自定义验证方法") {
+		t.Errorf("validation.go stubs the built-in rune length tags:\n%s", validation)
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.CreateUserReq{Name: "张"}).Validate())
+	fmt.Println((&types.CreateUserReq{Name: "张三李四王"}).Validate())
+}
+`)
+	if want := "name长度必须至少为2个字符\nname长度不能超过4个字符\n"; got != want {
+		t.Errorf("Validate() output = %q, want %q", got, want)
+	}
+}
+
+func TestTranslateBilingual(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type CreateUserReq struct {\n"+
+		"\tName string `json:\"name\" validate:\"required\"`\n"+
+		"\tCode string `json:\"code\" validate:\"required\" msg:\"请填写编码\"`\n"+
+		"}\n")
+	options := Options{EnableTranslator: true, TranslationLanguages: []string{"zh", "en"}, Bilingual: true}
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	// 通过同一包中的函数获取未翻译的验证错误
+	writeTypesFile(t, dir, "raw.go", "package types\n\nfunc StructErr(s any) error {\n\treturn validate.Struct(s)\n}\n")
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Printf("%q\n", types.TranslateBilingual(types.StructErr(&types.CreateUserReq{})))
+	fmt.Printf("%q\n", types.TranslateBilingual(nil))
+}
+`)
+	// 字段通过msg标签自定义的错误信息不重复显示
+	if want := "\"name为必填字段 (name is a required field), 请填写编码\"\n\"\"\n"; got != want {
+		t.Errorf("TranslateBilingual() output = %q, want %q", got, want)
+	}
+}
+
+func TestValidatorAccessor(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", mobileTypesSrc)
+	for range 2 {
+		if err := processFiles(t, Options{}, file); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if validation := readFile(t, dir, "validation.go"); strings.Count(validation, "func Validator() *validator.Validate {") != 1 {
+		t.Fatalf("validation.go does not declare Validator once:\n%s", validation)
+	}
+	// Validator返回生成代码使用的验证器，已注册插件内置的验证标签
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	v := types.Validator()
+	fmt.Println(v != nil)
+	fmt.Println(v.Var("13800138000", "mobile") == nil)
+	fmt.Println(v.Var("12345", "mobile") != nil)
+}
+`)
+	if got != "true\ntrue\ntrue\n" {
+		t.Errorf("Validator() results = %q, want a validator with mobile registered", got)
+	}
+}
+
+func TestMethodsInSeparateFile(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", mobileTypesSrc)
+	orderSrc := "package types\n\nimport \"time\"\n\n" +
+		"type OrderReq struct {\n" +
+		"\tMobile string    `json:\"mobile\" validate:\"required,mobile\"`\n" +
+		"\tAt     time.Time `json:\"at\" validate:\"required\"`\n" +
+		"}\n"
+	order := writeTypesFile(t, dir, "order.go", orderSrc)
+	options := Options{EnableTranslator: true, MethodsInSeparateFile: true}
+	for range 2 {
+		if err := processFiles(t, options, file, order); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// types文件保持不变，验证方法写入同一目录中的验证方法文件
+	if readFile(t, dir, "types.go") != mobileTypesSrc || readFile(t, dir, "order.go") != orderSrc {
+		t.Errorf("types files were modified")
+	}
+	for name, method := range map[string]string{APIMethodsFileName: "CreateUserReq", "order_" + APIMethodsFileName: "OrderReq"} {
+		if methods := readFile(t, dir, name); !strings.Contains(methods, "func (r *"+method+") Validate() error {") {
+			t.Errorf("%s does not contain the %s Validate method:\n%s", name, method, methods)
+		}
+	}
+	if got := runGenerated(t, root, mobileMain); got != "true\ntrue\n" {
+		t.Errorf("Validate() results = %q, want the mobile validator to reject 12345 and accept 13800138000", got)
+	}
+}
+
+func TestEnumTagTranslation(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type PostReq struct {\n"+
+		"\tStatus string `json:\"status\" validate:\"status_in=draft published\"`\n"+
+		"\tKind   string `json:\"kind\" validate:\"oneof=news blog\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{EnableCustomValidation: true, EnableTranslator: true, GenerateJSONMethod: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	validation := strings.Replace(readFile(t, dir, "validation.go"), "// 在这里实现 status_in 的验证逻辑\n\treturn true", "return false", 1)
+	writeTypesFile(t, dir, "validation.go", validation)
+	// 枚举类自定义标签的翻译列出标签参数中的可选值，内置的oneof使用validator的翻译
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.PostReq{Kind: "video"}).ValidateJSON())
+}
+`)
+	if want := "map[kind:kind必须是[news blog]中的一个 status:status必须是[draft published]中的一个] <nil>\n"; got != want {
+		t.Errorf("ValidateJSON() output = %q, want %q", got, want)
+	}
+}
+
+func TestNumericStringComparison(t *testing.T) {
+	// 按数值而不是字符串长度比较，不是数字时验证失败
+	values := []string{"0", "10.5", "10", "-1", "abc", ""}
+	tests := []struct {
+		tag  string
+		want []bool
+	}{
+		{"numstr_gt=0", []bool{false, true, true, false, false, false}},
+		{"numstr_gte=0", []bool{true, true, true, false, false, false}},
+		{"numstr_lt=10", []bool{true, false, false, true, false, false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			if got := validateValues(t, Options{}, tt.tag, values...); !slices.Equal(got, tt.want) {
+				t.Errorf("%s %q = %v, want %v", tt.tag, values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNumericStringTranslation(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type PayReq struct {\n"+
+		"\tAmount string `json:\"amount\" validate:\"numstr_gt=0,numstr_lt=10000\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{EnableCustomValidation: true, EnableTranslator: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	if validation := readFile(t, dir, "validation.go"); strings.Contains(validation, "自定义验证方法") {
+		t.Errorf("validation.go stubs the built-in numeric string tags:\n%s", validation)
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.PayReq{Amount: "0"}).Validate())
+	fmt.Println((&types.PayReq{Amount: "10000"}).Validate())
+}
+`)
+	if want := "amount必须大于0\namount必须小于10000\n"; got != want {
+		t.Errorf("Validate() output = %q, want %q", got, want)
+	}
+}
+
+func TestNoMethodMarker(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"// CreateUserReq 手写了Validate方法\n"+
+		"// +validate:nomethod\n"+
+		"type CreateUserReq struct {\n"+
+		"\tMobile string `json:\"mobile\" validate:\"required,mobile\"`\n"+
+		"}\n\n"+
+		"type UpdateUserReq struct {\n"+
+		"\tName string `json:\"name\" validate:\"required\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{EnableCustomValidation: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 只跳过标记的结构体的方法，其他结构体仍生成方法
+	types := readFile(t, dir, "types.go")
+	if strings.Contains(types, "func (r *CreateUserReq) Validate()") || !strings.Contains(types, "func (r *UpdateUserReq) Validate()") {
+		t.Fatalf("types.go does not skip CreateUserReq only:\n%s", types)
+	}
+	// 手写的Validate方法使用生成的验证器，mobile标签仍然注册
+	writeTypesFile(t, dir, "manual.go", "package types\n\n"+
+		"func (r *CreateUserReq) Validate() error {\n"+
+		"\treturn Validator().Struct(r)\n"+
+		"}\n")
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.CreateUserReq{Mobile: "12345"}).Validate() != nil)
+	fmt.Println((&types.CreateUserReq{Mobile: "13800138000"}).Validate() == nil)
+}
+`)
+	if got != "true\ntrue\n" {
+		t.Errorf("Validate() results = %q, want the mobile validator to be registered", got)
+	}
+}
+
+func TestSkipFieldTag(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type CreateUserReq struct {\n"+
+		"\tName     string `json:\"name\" validate:\"required\"`\n"+
+		"\tInternal string `json:\"-\" validate:\"-\"`\n"+
+		"\t// validate: required,mobile\n"+
+		"\tNote string `json:\"note\" validate:\"-\"`\n"+
+		"}\n")
+	summary := &Summary{}
+	if _, err := ProcessTypesFile(false, file, Options{EnableCustomValidation: true, EnableTranslator: true}, summary); err != nil {
+		t.Fatal(err)
+	}
+	// validate:"-"跳过字段，不作为自定义标签，也不使用注释中的验证规则
+	if len(summary.CustomTags) != 0 {
+		t.Errorf("CustomTags = %v, want none", summary.CustomTags)
+	}
+	if validation := readFile(t, dir, "validation.go"); strings.Contains(validation, "自定义验证方法") {
+		t.Errorf("validation.go stubs the skip tag:\n%s", validation)
+	}
+	if types := readFile(t, dir, "types.go"); !strings.Contains(types, "`json:\"note\" validate:\"-\"`") {
+		t.Errorf("types.go rewrote the skipped field:\n%s", types)
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.CreateUserReq{Name: "name"}).Validate())
+}
+`)
+	if got != "<nil>\n" {
+		t.Errorf("Validate() = %q, want skipped fields to be ignored", got)
+	}
+}
+
+func TestOverrideBuiltInRegex(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	config := writeTypesFile(t, root, "validators.yaml", `validators:
+  - tag: mobile
+    regex: '^(\+852)?[5689]\d{7}$'
+    message: "{0}必须是有效的香港手机号码"
+`)
+	file := writeTypesFile(t, dir, "types.go", mobileTypesSrc)
+	if err := processFiles(t, Options{EnableTranslator: true, ConfigPath: config}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 生成的验证函数保持原函数名和注册，使用配置的正则表达式
+	validation := readFile(t, dir, "validation.go")
+	for _, want := range []string{"func validateMobile(", `"mobile": validateMobile,`, fmt.Sprintf("%q", `^(\+852)?[5689]\d{7}$`)} {
+		if !containsCode(validation, want) {
+			t.Errorf("validation.go does not contain %q:\n%s", want, validation)
+		}
+	}
+	if strings.Contains(validation, fmt.Sprintf("%q", `^1[3-9]\d{9}$`)) {
+		t.Errorf("validation.go still uses the default mobile regex:\n%s", validation)
+	}
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.CreateUserReq{Name: "name", Mobile: "+85251234567"}).Validate())
+	fmt.Println((&types.CreateUserReq{Name: "name", Mobile: "13800138000"}).Validate())
+}
+`)
+	if want := "<nil>\nmobile必须是有效的香港手机号码\n"; got != want {
+		t.Errorf("Validate() output = %q, want %q", got, want)
+	}
+}
+
+func TestProtobufFieldNames(t *testing.T) {
+	root := newTestModule(t)
+	file := writeTypesFile(t, filepath.Join(root, "types"), "types.go", "package types\n\n"+
+		"type CreateUserReq struct {\n"+
+		"\tUserName string `protobuf:\"bytes,1,opt,name=user_name,json=userName,proto3\" validate:\"required\"`\n"+
+		"\tNickName string `protobuf:\"bytes,2,opt,name=nick_name,proto3\" validate:\"required\"`\n"+
+		"\tMobile   string `protobuf:\"bytes,3,opt,name=mobile,json=phone,proto3\" json:\"mobile,omitempty\" validate:\"required\"`\n"+
+		"}\n")
+	if err := processFiles(t, Options{EnableTranslator: true}, file); err != nil {
+		t.Fatal(err)
+	}
+	// 没有json标签时依次使用protobuf标签中的json名称及字段名称，有json标签时优先使用json标签
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	fmt.Println((&types.CreateUserReq{NickName: "n", Mobile: "m"}).Validate())
+	fmt.Println((&types.CreateUserReq{UserName: "u", Mobile: "m"}).Validate())
+	fmt.Println((&types.CreateUserReq{UserName: "u", NickName: "n"}).Validate())
+}
+`)
+	if want := "userName为必填字段\nnick_name为必填字段\nmobile为必填字段\n"; got != want {
+		t.Errorf("Validate() output = %q, want %q", got, want)
+	}
+}
+
+func TestStructStyle(t *testing.T) {
+	root := newTestModule(t)
+	dir := filepath.Join(root, "types")
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type CreateUserReq struct {\n"+
+		"\tMobile string `json:\"mobile\" validate:\"required,mobile\"`\n"+
+		"\tCode   string `json:\"code\" validate:\"omitempty,sku\"`\n"+
+		"}\n")
+	for range 2 {
+		if err := processFiles(t, Options{EnableCustomValidation: true, StructStyle: true}, file); err != nil {
+			t.Fatal(err)
+		}
+	}
+	validation := readFile(t, dir, "validation.go")
+	for _, want := range []string{"type Validator struct {", "func New() *Validator {", "func (v *Validator) Validate(s any) error {"} {
+		if strings.Count(validation, want) != 1 {
+			t.Errorf("validation.go does not contain %q once:\n%s", want, validation)
+		}
+	}
+	// 已生成Validator类型时不再生成同名的Validator函数
+	if strings.Contains(validation, "func Validator()") {
+		t.Errorf("validation.go declares both the Validator type and function:\n%s", validation)
+	}
+	validation = strings.Replace(validation, "// 在这里实现 sku 的验证逻辑\n\treturn true", "return false", 1)
+	writeTypesFile(t, dir, "validation.go", validation)
+	// New创建的验证器注册了插件内置及自定义的验证标签
+	got := runGenerated(t, root, `package main
+
+import (
+	"fmt"
+
+	"example.com/gen/types"
+)
+
+func main() {
+	v := types.New()
+	fmt.Println(v.Validate(&types.CreateUserReq{Mobile: "12345"}) != nil)
+	fmt.Println(v.Validate(&types.CreateUserReq{Mobile: "13800138000"}) == nil)
+	fmt.Println(v.Validate(&types.CreateUserReq{Mobile: "13800138000", Code: "x"}) != nil)
+}
+`)
+	if got != "true\ntrue\ntrue\n" {
+		t.Errorf("Validator.Validate() results = %q, want mobile and sku to be registered", got)
+	}
+}
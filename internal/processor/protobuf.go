@@ -0,0 +1,32 @@
+package processor
+
+import (
+	"regexp"
+)
+
+// ProtobufTagNameFallback 没有json标签时使用protobuf标签中的json名称，用于protobuf生成的结构体
+// protoc-gen-go只在json名称与字段名不同时生成json=选项，否则json名称即name=指定的字段名
+// 例如: UserName string `protobuf:"bytes,1,opt,name=user_name,json=userName,proto3"` -> userName为必填字段
+const ProtobufTagNameFallback = `		if name == "" {
+			// protobuf生成的结构体没有json标签时，使用protobuf标签中的json名称
+			for _, opt := range strings.Split(field.Tag.Get("protobuf"), ",") {
+				key, value, _ := strings.Cut(opt, "=")
+				if key == "json" {
+					name = value
+					break
+				}
+				if key == "name" {
+					name = value
+				}
+			}
+		}
+`
+
+// legacyTagNameRegex 匹配旧版本生成的注册字段名函数中读取json标签后直接判断字段名的语句
+var legacyTagNameRegex = regexp.MustCompile(`(\tname := strings\.SplitN\(field\.Tag\.Get\("json"\), ",", 2\)\[0\]\n)(\t+if name == "-" \|\| name == "" \{)`)
+
+// withProtobufTagName 已有的翻译器文件中注册字段名的函数补充读取protobuf标签中的json名称
+// 已经读取protobuf标签的函数不再修改
+func withProtobufTagName(content []byte) []byte {
+	return legacyTagNameRegex.ReplaceAll(content, []byte("${1}"+ProtobufTagNameFallback+"${2}"))
+}
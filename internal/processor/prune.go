@@ -0,0 +1,102 @@
+package processor
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+)
+
+// packageTags 获取目录中所有.go文件（测试文件除外）的结构体字段使用的验证标签名称
+func packageTags(dirPath string, aliases map[string]string) map[string]bool {
+	tags := make(map[string]bool)
+	for _, f := range parsePackageFiles(dirPath) {
+		ast.Inspect(f, func(n ast.Node) bool {
+			structType, ok := n.(*ast.StructType)
+			if !ok {
+				return true
+			}
+			for _, field := range structType.Fields.List {
+				if field.Tag == nil {
+					continue
+				}
+				for _, v := range expandRuleAliases(splitValidateTag(extractValidateTag(field.Tag.Value)), aliases) {
+					tag, _, _ := parseValidator(v)
+					tags[tag] = true
+				}
+			}
+			return true
+		})
+	}
+	return tags
+}
+
+// pruneTranslations 删除翻译器文件中stale返回true的标签的翻译注册语句
+// 包括trans.Add和RegisterTranslation，没有需要删除的语句时返回nil
+func pruneTranslations(filePath string, content []byte, stale func(tag string) bool) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filePath, content, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	// 需要删除的语句所在的整行范围，按在文件中的位置排列
+	var ranges [][2]int
+	ast.Inspect(f, func(n ast.Node) bool {
+		block, ok := n.(*ast.BlockStmt)
+		if !ok {
+			return true
+		}
+		for _, stmt := range block.List {
+			if tag, ok := translationStmtTag(stmt); ok && stale(tag) {
+				start := fset.Position(stmt.Pos()).Offset
+				end := fset.Position(stmt.End()).Offset
+				for start > 0 && (content[start-1] == ' ' || content[start-1] == '\t') {
+					start--
+				}
+				if end < len(content) && content[end] == '\n' {
+					end++
+				}
+				ranges = append(ranges, [2]int{start, end})
+			}
+		}
+		return true
+	})
+	if len(ranges) == 0 {
+		return nil, nil
+	}
+
+	var pruned bytes.Buffer
+	last := 0
+	for _, r := range ranges {
+		pruned.Write(content[last:r[0]])
+		last = r[1]
+	}
+	pruned.Write(content[last:])
+	return format.Source(pruned.Bytes())
+}
+
+// translationStmtTag 获取注册翻译语句的标签，如_ = trans.Add("tag", ...)或validate.RegisterTranslation("tag", ...)
+func translationStmtTag(stmt ast.Stmt) (string, bool) {
+	var expr ast.Expr
+	switch s := stmt.(type) {
+	case *ast.ExprStmt:
+		expr = s.X
+	case *ast.AssignStmt:
+		if len(s.Rhs) != 1 {
+			return "", false
+		}
+		expr = s.Rhs[0]
+	default:
+		return "", false
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return "", false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || (sel.Sel.Name != "Add" && sel.Sel.Name != "RegisterTranslation") {
+		return "", false
+	}
+	return stringLiteral(call.Args[0])
+}
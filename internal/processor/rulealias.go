@@ -0,0 +1,89 @@
+package processor
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// RuleAliasRegisterFunc 注册配置文件定义的验证规则别名，%s 为结构体专属验证器的注册代码
+	RuleAliasRegisterFunc = `
+// 注册验证规则别名
+func init() {
+	for alias, tags := range registerAlias {
+		validate.RegisterAlias(alias, tags)
+	}%s
+}
+`
+
+	// ruleAliasStructSetup 结构体专属的验证器同样注册验证规则别名
+	ruleAliasStructSetup = `
+	validatorSetups = append(validatorSetups, func(v *validator.Validate) {
+		for alias, tags := range registerAlias {
+			v.RegisterAlias(alias, tags)
+		}
+	})`
+)
+
+// ruleAliasMapRegex 匹配验证文件中的验证规则别名映射
+var ruleAliasMapRegex = regexp.MustCompile(`(?s)// registerAlias .*?var registerAlias = map\[string\]string\{.*?\n\}\n`)
+
+// ruleAliases 获取配置文件定义的验证规则别名，键为别名，值为别名代表的验证规则，未使用配置文件时返回nil
+func ruleAliases(options Options) (map[string]string, error) {
+	if options.ConfigPath == "" {
+		return nil, nil
+	}
+	config, err := LoadConfig(options.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	return config.RuleAliases, nil
+}
+
+// expandRuleAliases 在验证规则别名之后追加别名代表的验证器，用于收集别名中使用的自定义验证标签
+// 例如: [username_rule] -> [username_rule required alphanum min=3 max=20]
+func expandRuleAliases(validators []string, aliases map[string]string) []string {
+	if len(aliases) == 0 {
+		return validators
+	}
+	var expanded []string
+	for _, v := range validators {
+		expanded = append(expanded, v)
+		if rule, ok := aliases[v]; ok {
+			expanded = append(expanded, splitValidateTag(rule)...)
+		}
+	}
+	return expanded
+}
+
+// ruleAliasCode 生成验证规则别名映射的声明
+func ruleAliasCode(aliases map[string]string) string {
+	var code strings.Builder
+	code.WriteString("// registerAlias 配置文件定义的验证规则别名，键为别名，值为别名代表的验证规则\n")
+	code.WriteString("var registerAlias = map[string]string{\n")
+	for _, alias := range slices.Sorted(maps.Keys(aliases)) {
+		code.WriteString(fmt.Sprintf("\t%q: %q,\n", alias, aliases[alias]))
+	}
+	code.WriteString("}\n")
+	return code.String()
+}
+
+// withRuleAliases 更新验证文件中的验证规则别名映射，缺少映射时连同注册别名的init函数追加到文件末尾
+// 配置文件中没有别名且验证文件中也没有映射时不修改
+func withRuleAliases(content []byte, aliases map[string]string, perStruct bool) []byte {
+	code := string(content)
+	if ruleAliasMapRegex.MatchString(code) {
+		return []byte(ruleAliasMapRegex.ReplaceAllLiteralString(code, ruleAliasCode(aliases)))
+	}
+	if len(aliases) == 0 {
+		return content
+	}
+	setup := ""
+	if perStruct {
+		setup = ruleAliasStructSetup
+	}
+	return []byte(code + "\n" + ruleAliasCode(aliases) + fmt.Sprintf(RuleAliasRegisterFunc, setup))
+}
@@ -0,0 +1,192 @@
+package processor
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SharedTranslatorRegisterTemplate types包在验证器上注册共享翻译器的初始化函数
+// %[1]s 为共享翻译器的包名，%[2]s 为结构体专属验证器的注册代码
+const SharedTranslatorRegisterTemplate = `
+// 在验证器上注册共享翻译器的字段名和翻译
+func init() {
+	%[1]s.Register(validate)%[2]s
+}
+`
+
+// translatorRef 生成的代码引用翻译器的方式
+type translatorRef struct {
+	// 共享翻译器包的导入路径，为空时引用同一包中的translator.go
+	Import string
+	// 同一包中的翻译器是否延迟初始化，通过translator()获取
+	Lazy bool
+}
+
+// shared 是否引用共享翻译器包
+func (r *translatorRef) shared() bool {
+	return r != nil && r.Import != ""
+}
+
+// pkg 共享翻译器的包名
+func (r *translatorRef) pkg() string {
+	return path.Base(r.Import)
+}
+
+// fieldExpr 翻译单个字段验证错误的表达式，fe为字段错误的变量名
+func (r *translatorRef) fieldExpr(fe string) string {
+	if r.shared() {
+		return fmt.Sprintf("%s.TranslateField(%s)", r.pkg(), fe)
+	}
+	if r.Lazy {
+		return fmt.Sprintf("translateField(%s, translator())", fe)
+	}
+	return fmt.Sprintf("translateField(%s, trans)", fe)
+}
+
+// translateFunc 翻译验证错误的函数
+func (r *translatorRef) translateFunc() string {
+	if r.shared() {
+		return r.pkg() + ".Translate"
+	}
+	return "Translate"
+}
+
+// registerMessagesFunc 注册字段自定义错误信息的函数
+func (r *translatorRef) registerMessagesFunc() string {
+	if r.shared() {
+		return r.pkg() + ".RegisterFieldMessages"
+	}
+	return "registerFieldMessages"
+}
+
+// sharedTranslatorPackage 获取共享翻译器包相对于模块根目录的路径，未启用时返回空字符串
+func sharedTranslatorPackage(options Options) (string, error) {
+	return modulePackage(options.SharedTranslatorPackage, "共享翻译器包")
+}
+
+// findModule 从指定目录向上查找go.mod，返回模块根目录及模块路径
+func findModule(dir string) (string, string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	for {
+		content, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			scanner := bufio.NewScanner(bytes.NewReader(content))
+			for scanner.Scan() {
+				line := strings.TrimSpace(scanner.Text())
+				if modulePath, ok := strings.CutPrefix(line, "module"); ok {
+					modulePath = strings.TrimSpace(modulePath)
+					if unquoted, err := strconv.Unquote(modulePath); err == nil {
+						modulePath = unquoted
+					}
+					return dir, modulePath, nil
+				}
+			}
+			return "", "", fmt.Errorf("%s 中缺少module声明", filepath.Join(dir, "go.mod"))
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", fmt.Errorf("未找到go.mod，无法确定共享翻译器包的导入路径")
+		}
+		dir = parent
+	}
+}
+
+// sharedTranslatorFile 生成共享翻译器包的翻译器文件
+// 翻译器只在包初始化时创建一次，各types包的验证器通过Register注册字段名和翻译，bilingual表示生成TranslateBilingual函数
+func sharedTranslatorFile(pkgName string, langs []string, validations []builtInValidation, customTags, enumTags map[string]bool, bilingual bool) string {
+	tags := sortedTags(customTags)
+
+	var code strings.Builder
+	code.WriteString(GeneratedHeader)
+	code.WriteString(fmt.Sprintf("package %s\n\n", pkgName))
+
+	code.WriteString("import (\n")
+	code.WriteString("\t\"errors\"\n")
+	code.WriteString("\t\"reflect\"\n")
+	code.WriteString("\t\"strings\"\n")
+	code.WriteString("\t\"github.com/go-playground/locales\"\n")
+	code.WriteString("\t\"github.com/go-playground/validator/v10\"\n")
+	code.WriteString(translatorImports(langs))
+	code.WriteString(")\n\n")
+
+	code.WriteString("var (\n")
+	code.WriteString("\tuni   *ut.UniversalTranslator\n")
+	code.WriteString("\ttrans ut.Translator\n")
+	code.WriteString(")\n\n")
+
+	// 翻译器只创建一次，多个验证器共享时包装为sharedTranslator
+	code.WriteString("// 初始化翻译器\n")
+	code.WriteString("func init() {\n")
+	code.WriteString(translatorLocales(langs))
+	code.WriteString(fmt.Sprintf("\n\ttrans, _ = uni.GetTranslator(\"%s\")\n", langs[0]))
+	code.WriteString("\ttrans = sharedTranslator{trans}\n")
+	code.WriteString("}\n\n")
+
+	code.WriteString("// Register 在验证器上注册字段名、默认翻译及自定义翻译，每个types包的验证器都需要注册\n")
+	code.WriteString("func Register(validate *validator.Validate) {\n")
+	code.WriteString(TranslatorTagNameFunc)
+	for i, lang := range langs {
+		code.WriteString(fmt.Sprintf("\n\t// 注册%s翻译\n", lang))
+		code.WriteString(fmt.Sprintf("\tvar %sTranslator ut.Translator\n", lang))
+		code.WriteString(fmt.Sprintf("\t%[1]sTranslator, _ = uni.GetTranslator(\"%[1]s\")\n", lang))
+		code.WriteString(fmt.Sprintf("\t%[1]sTranslator = sharedTranslator{%[1]sTranslator}\n", lang))
+		code.WriteString(fmt.Sprintf("\t_ = %[1]sTrans.RegisterDefaultTranslations(validate, %[1]sTranslator)\n", lang))
+		code.WriteString(fmt.Sprintf("\tregisterCustomTranslations(validate, %sTranslator)\n", lang))
+		if i > 0 {
+			code.WriteString(localizedTranslations(lang, validations, tags, enumTags, "\t"))
+		}
+	}
+	code.WriteString("}\n\n")
+
+	code.WriteString(`// Translate 翻译验证错误
+func Translate(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var errs validator.ValidationErrors
+	if ok := errors.As(err, &errs); !ok {
+		return err
+	}
+
+	var errMsgs []string
+	for _, e := range errs {
+		errMsgs = append(errMsgs, translateField(e, trans))
+	}
+	return errors.New(strings.Join(errMsgs, ", "))
+}
+`)
+	code.WriteString(GetValidateErrorMsgFunc)
+	code.WriteString(`
+// TranslateField 翻译单个字段的验证错误
+func TranslateField(fe validator.FieldError) string {
+	return translateField(fe, trans)
+}
+
+// RegisterFieldMessages 注册字段通过msg标签自定义的验证错误信息
+func RegisterFieldMessages(messages map[string]string) {
+	registerFieldMessages(messages)
+}
+`)
+	code.WriteString(FieldMessageFuncs)
+
+	// 多语言时添加按语言翻译的函数
+	if len(langs) > 1 {
+		code.WriteString(strings.Replace(TranslateWithFunc, "\t\tt = trans\n\t}\n", "\t\tt = trans\n\t} else {\n\t\tt = sharedTranslator{t}\n\t}\n", 1))
+	}
+	if bilingual {
+		code.WriteString(translateBilingualFunc(langs, true, false))
+	}
+	code.WriteString(SharedTranslatorType + "\n")
+	code.WriteString(customTranslationsFunc(validations, customTags, enumTags, langs[0]))
+	return code.String()
+}
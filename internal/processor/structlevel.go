@@ -0,0 +1,74 @@
+package processor
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+)
+
+const (
+	// StructLevelMarker 结构体注释中的标记，标记后生成结构体级别的验证方法
+	StructLevelMarker = "+validate:struct"
+
+	// IgnoreMarker 结构体注释中的标记，标记后不为该结构体生成Validate方法
+	IgnoreMarker = "+validate:ignore"
+
+	// NoMethodMarker 结构体注释中的标记，标记后不为该结构体生成Validate及ValidateCtx方法，结构体的验证标签仍会生成验证方法和翻译
+	// 用于已手写Validate方法（包含额外业务逻辑）的结构体
+	NoMethodMarker = "+validate:nomethod"
+
+	// StructLevelFuncTemplate 结构体级别验证方法模板
+	StructLevelFuncTemplate = `
+// %[1]sStructLevel %[1]s的结构体级别验证，用于跨字段的验证规则
+func %[1]sStructLevel(sl validator.StructLevel) {
+	// TODO 在这里实现 %[1]s 跨字段的验证逻辑，例如:
+	// req := sl.Current().Interface().(%[1]s)
+	// if req.EndDate.Before(req.StartDate) {
+	// 	sl.ReportError(req.EndDate, "EndDate", "EndDate", "gtfield", "StartDate")
+	// }
+}
+`
+
+	// StructLevelRegisterTemplate 注册结构体级别验证方法
+	StructLevelRegisterTemplate = `
+// 注册%[1]s的结构体级别验证
+func init() {
+	validate.RegisterStructValidation(%[1]sStructLevel, %[1]s{})
+}
+`
+
+	// PerStructLevelRegisterTemplate 结构体专属验证器模式下注册结构体级别验证方法
+	PerStructLevelRegisterTemplate = `
+// 注册%[1]s的结构体级别验证
+func init() {
+	validatorSetups = append(validatorSetups, func(v *validator.Validate) {
+		v.RegisterStructValidation(%[1]sStructLevel, %[1]s{})
+	})
+}
+`
+)
+
+// hasDirective 判断结构体的注释中是否包含指定标记，单独声明的结构体注释位于GenDecl上
+func hasDirective(genDecl *ast.GenDecl, typeSpec *ast.TypeSpec, directive string) bool {
+	for _, doc := range []*ast.CommentGroup{typeSpec.Doc, typeSpec.Comment, genDecl.Doc} {
+		if doc == nil {
+			continue
+		}
+		for _, comment := range doc.List {
+			text := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(comment.Text, "//"), "/*"))
+			if strings.HasPrefix(text, directive) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// structLevelCode 生成结构体级别验证方法及其注册代码
+func structLevelCode(structName string, perStruct bool) string {
+	register := StructLevelRegisterTemplate
+	if perStruct {
+		register = PerStructLevelRegisterTemplate
+	}
+	return fmt.Sprintf(StructLevelFuncTemplate, structName) + fmt.Sprintf(register, structName)
+}
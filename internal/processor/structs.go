@@ -0,0 +1,109 @@
+package processor
+
+import "go/ast"
+
+// fieldTypeNames 获取字段类型中引用的类型名，如 *Item、[]Item、map[string]Item
+func fieldTypeNames(expr ast.Expr) []string {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return []string{t.Name}
+	case *ast.StarExpr:
+		return fieldTypeNames(t.X)
+	case *ast.ArrayType:
+		return fieldTypeNames(t.Elt)
+	case *ast.MapType:
+		return append(fieldTypeNames(t.Key), fieldTypeNames(t.Value)...)
+	}
+	return nil
+}
+
+// inlineStructTypes 获取字段类型中直接定义的匿名结构体，如 struct{...}、*struct{...}、[]struct{...}、map[string]struct{...}
+func inlineStructTypes(expr ast.Expr) []*ast.StructType {
+	switch t := expr.(type) {
+	case *ast.StructType:
+		return []*ast.StructType{t}
+	case *ast.StarExpr:
+		return inlineStructTypes(t.X)
+	case *ast.ArrayType:
+		return inlineStructTypes(t.Elt)
+	case *ast.MapType:
+		return append(inlineStructTypes(t.Key), inlineStructTypes(t.Value)...)
+	}
+	return nil
+}
+
+// structFields 获取结构体的字段及字段中匿名结构体（包括多层嵌套）的字段
+func structFields(structType *ast.StructType) []*ast.Field {
+	var fields []*ast.Field
+	for _, field := range structType.Fields.List {
+		fields = append(fields, field)
+		for _, inline := range inlineStructTypes(field.Type) {
+			fields = append(fields, structFields(inline)...)
+		}
+	}
+	return fields
+}
+
+// referencedTypes 获取结构体字段（包括匿名结构体的字段）引用的所有类型名
+func referencedTypes(structType *ast.StructType) []string {
+	var names []string
+	for _, field := range structFields(structType) {
+		names = append(names, fieldTypeNames(field.Type)...)
+	}
+	return names
+}
+
+// embeddedTypeName 获取匿名嵌入字段的类型名，如 Base、*Base
+func embeddedTypeName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// promotedFields 获取结构体自身的字段、字段中匿名结构体的字段及匿名嵌入的同文件结构体（包括多层嵌入）的字段
+func promotedFields(name string, localStructs map[string]*ast.StructType) []*ast.Field {
+	var fields []*ast.Field
+	visited := make(map[string]bool)
+	var visit func(string)
+	visit = func(n string) {
+		structType, ok := localStructs[n]
+		if !ok || visited[n] {
+			return
+		}
+		visited[n] = true
+		for _, field := range structFields(structType) {
+			fields = append(fields, field)
+			if len(field.Names) == 0 {
+				visit(embeddedTypeName(field.Type))
+			}
+		}
+	}
+	visit(name)
+	return fields
+}
+
+// expandStructs 将结构体字段（包括匿名结构体的字段）直接或通过指针、切片、数组、map引用的同文件结构体递归加入列表，保持原有顺序
+func expandStructs(names []string, localStructs map[string]*ast.StructType) []string {
+	included := make(map[string]bool)
+	for _, name := range names {
+		included[name] = true
+	}
+
+	for i := 0; i < len(names); i++ {
+		structType, ok := localStructs[names[i]]
+		if !ok {
+			continue
+		}
+		for _, ref := range referencedTypes(structType) {
+			if _, ok := localStructs[ref]; ok && !included[ref] {
+				included[ref] = true
+				names = append(names, ref)
+			}
+		}
+	}
+	return names
+}
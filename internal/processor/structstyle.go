@@ -0,0 +1,45 @@
+package processor
+
+import "fmt"
+
+// StructValidatorCode 以结构体封装的验证器，便于通过依赖注入使用而不依赖包级别的验证器
+const StructValidatorCode = `
+// Validator 以结构体封装的验证器，便于通过依赖注入使用
+// 生成的Validate()方法仍使用包级别的默认验证器
+type Validator struct {
+	v *validator.Validate
+}
+
+// New 创建验证器并注册插件内置、配置文件定义及自定义的验证标签
+func New() *Validator {
+	v := validator.New()
+	for tag, handler := range registerValidation {
+		_ = v.RegisterValidation(tag, handler)
+	}
+	return &Validator{v: v}
+}
+
+// Validate 验证结构体，返回validator的验证错误
+func (v *Validator) Validate(s any) error {
+	return v.v.Struct(s)
+}
+`
+
+// withStructValidator 验证文件缺少Validator类型时追加到文件末尾
+// 包内已声明Validator或New函数时报错，未启用时生成的Validator函数需要先删除
+func withStructValidator(filePath string, content []byte, packageFuncs map[string]bool) ([]byte, error) {
+	f, err := parseExistingFile(filePath, content)
+	if err != nil {
+		return nil, fmt.Errorf("解析验证文件失败: %w", err)
+	}
+	if declaresType(f, "Validator") {
+		return content, nil
+	}
+	funcs := declaredFuncs(f)
+	for _, name := range []string{"Validator", "New"} {
+		if funcs[name] || packageFuncs[name] {
+			return nil, fmt.Errorf("--struct-style生成的Validator类型及New函数与包中已声明的%s函数冲突，请删除该函数或使用--force重新生成验证文件", name)
+		}
+	}
+	return append(append([]byte(nil), content...), StructValidatorCode...), nil
+}
@@ -0,0 +1,141 @@
+package processor
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"strings"
+)
+
+// stubStatements 生成的验证方法桩中除return true之外的语句，只包含这些语句的验证方法视为未实现
+var stubStatements = map[string]bool{
+	"param := fl.Param()": true,
+	"_ = param":           true,
+}
+
+// unimplementedStubs 获取验证文件中仍为生成的空验证方法（始终返回true）的自定义验证标签，按tags的顺序排列
+// 检查registerValidation映射中为标签实际注册的函数，未注册时检查validate<Tag>
+// 通过RegisterValidationCtx注册的验证方法检查validate<Tag>Ctx，注册的函数不在验证文件中时视为已实现
+func unimplementedStubs(filePath string, content []byte, tags []string) ([]string, error) {
+	if len(tags) == 0 || len(content) == 0 {
+		return nil, nil
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filePath, content, 0)
+	if err != nil {
+		return nil, fmt.Errorf("解析验证文件失败: %w", err)
+	}
+	funcs := make(map[string]*ast.FuncDecl)
+	for _, decl := range f.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil && funcDecl.Body != nil {
+			funcs[funcDecl.Name.Name] = funcDecl
+		}
+	}
+
+	registered := registeredValidations(f)
+	for tag, fn := range registerValidationCalls(f) {
+		if registered[tag] == "" {
+			registered[tag] = fn
+		}
+	}
+
+	var stubs []string
+	for _, tag := range tags {
+		funcDecl, ok := funcs[validationFuncName(tag)+"Ctx"]
+		if !ok {
+			funcDecl, ok = funcs[cmp.Or(registered[tag], validationFuncName(tag))]
+		}
+		if ok && isStubBody(fset, funcDecl.Body) {
+			stubs = append(stubs, tag)
+		}
+	}
+	return stubs, nil
+}
+
+// isStubBody 判断函数体是否为生成的验证方法桩，即除读取参数外只有return true
+func isStubBody(fset *token.FileSet, body *ast.BlockStmt) bool {
+	if len(body.List) == 0 {
+		return false
+	}
+	for _, stmt := range body.List[:len(body.List)-1] {
+		var buf bytes.Buffer
+		if err := printer.Fprint(&buf, fset, stmt); err != nil || !stubStatements[buf.String()] {
+			return false
+		}
+	}
+	ret, ok := body.List[len(body.List)-1].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return false
+	}
+	ident, ok := ret.Results[0].(*ast.Ident)
+	return ok && ident.Name == "true"
+}
+
+// renameLegacyStubs 将旧版本生成的仍未实现的验证方法重命名为当前的函数名，如validateNew_tag1重命名为validateNewTag1
+// 已实现的验证方法保留原函数名，避免影响包内其他代码对其的引用
+func renameLegacyStubs(filePath string, content []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filePath, content, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("解析验证文件失败: %w", err)
+	}
+	funcs := make(map[string]*ast.FuncDecl)
+	for _, decl := range f.Decls {
+		if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil && funcDecl.Body != nil {
+			funcs[funcDecl.Name.Name] = funcDecl
+		}
+	}
+
+	renames := make(map[string]string)
+	for tag, fn := range registeredValidations(f) {
+		if tag == "" || fn != legacyValidationFuncName(tag) || fn == validationFuncName(tag) {
+			continue
+		}
+		funcDecl, ok := funcs[fn]
+		if !ok || funcs[validationFuncName(tag)] != nil || !isStubBody(fset, funcDecl.Body) {
+			continue
+		}
+		renames[fn] = validationFuncName(tag)
+	}
+	if len(renames) == 0 {
+		return content, nil
+	}
+
+	// 从后向前替换函数声明及映射中的引用，保持前面标识符的偏移量不变
+	var idents []*ast.Ident
+	ast.Inspect(f, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && renames[ident.Name] != "" {
+			idents = append(idents, ident)
+		}
+		return true
+	})
+	result := bytes.Clone(content)
+	for i := len(idents) - 1; i >= 0; i-- {
+		start := fset.Position(idents[i].Pos()).Offset
+		end := start + len(idents[i].Name)
+		result = append(result[:start], append([]byte(renames[idents[i].Name]), result[end:]...)...)
+	}
+	return result, nil
+}
+
+// checkStubs 检查自定义验证标签的验证方法是否已实现，调试模式下输出警告，启用Strict时返回错误
+func checkStubs(filePath string, content []byte, tags []string, options Options) error {
+	if !options.DebugMode && !options.Strict {
+		return nil
+	}
+	stubs, err := unimplementedStubs(filePath, content, tags)
+	if err != nil || len(stubs) == 0 {
+		return err
+	}
+	if options.DebugMode {
+		options.Log().Warnf("以下自定义验证标签的验证方法尚未实现，始终验证通过: %s", strings.Join(stubs, ", "))
+	}
+	if options.Strict {
+		return fmt.Errorf("自定义验证标签的验证方法尚未实现: %s (%s)", strings.Join(stubs, ", "), filePath)
+	}
+	return nil
+}
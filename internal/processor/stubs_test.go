@@ -0,0 +1,129 @@
+package processor
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testValidationSrc 包含生成的空验证方法、手动注册到其他函数的验证方法及旧版本函数名的验证文件
+const testValidationSrc = `package types
+
+import "github.com/go-playground/validator/v10"
+
+var registerValidation = map[string]validator.Func{
+	"age_range": validateAgeRange,
+	"custom_validation": customValidation,
+	"renamed_stub": checkRenamed,
+	"new_tag1": validateNew_tag1,
+}
+
+func validateAgeRange(fl validator.FieldLevel) bool {
+	param := fl.Param()
+	_ = param
+	return true
+}
+
+func customValidation(fl validator.FieldLevel) bool {
+	return fl.Field().String() != ""
+}
+
+func checkRenamed(fl validator.FieldLevel) bool {
+	return true
+}
+
+func validateNew_tag1(fl validator.FieldLevel) bool {
+	return true
+}
+`
+
+func TestUnimplementedStubs(t *testing.T) {
+	tags := []string{"age_range", "custom_validation", "renamed_stub", "unregistered"}
+	got, err := unimplementedStubs("validation.go", []byte(testValidationSrc), tags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 检查映射中实际注册的函数，custom_validation注册的函数已实现，unregistered没有验证函数
+	want := []string{"age_range", "renamed_stub"}
+	if !slices.Equal(got, want) {
+		t.Errorf("unimplementedStubs() = %v, want %v", got, want)
+	}
+}
+
+func TestCheckStubs(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    []string
+		strict  bool
+		wantErr bool
+	}{
+		{"implemented", []string{"custom_validation"}, true, false},
+		{"stub", []string{"age_range"}, true, true},
+		{"stub without strict", []string{"age_range"}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkStubs("validation.go", []byte(testValidationSrc), tt.tags, Options{Strict: tt.strict})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkStubs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStubWarning(t *testing.T) {
+	dir := t.TempDir()
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type CreateUserReq struct {\n"+
+		"\tCode string `json:\"code\" validate:\"tag_b\"`\n"+
+		"\tSku  string `json:\"sku\" validate:\"tag_a\"`\n"+
+		"}\n")
+	logger := &captureLogger{}
+	options := Options{EnableCustomValidation: true, DebugMode: true, Logger: logger}
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	// 调试模式下列出仍为生成的空方法的自定义验证标签
+	want := "以下自定义验证标签的验证方法尚未实现，始终验证通过: tag_a, tag_b"
+	if !slices.Contains(logger.warnings, want) {
+		t.Errorf("warnings = %q, want %q", logger.warnings, want)
+	}
+
+	// 已实现的验证方法不再警告
+	validation := strings.Replace(readFile(t, dir, "validation.go"), "// 在这里实现 tag_a 的验证逻辑\n\treturn true", "return fl.Field().Len() > 0", 1)
+	writeTypesFile(t, dir, "validation.go", validation)
+	logger.warnings = nil
+	if err := processFiles(t, options, file); err != nil {
+		t.Fatal(err)
+	}
+	want = "以下自定义验证标签的验证方法尚未实现，始终验证通过: tag_b"
+	if !slices.Contains(logger.warnings, want) {
+		t.Errorf("warnings = %q, want %q", logger.warnings, want)
+	}
+}
+
+func TestRenameLegacyStubs(t *testing.T) {
+	got, err := renameLegacyStubs("validation.go", []byte(testValidationSrc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(got)
+	if strings.Contains(content, "validateNew_tag1") {
+		t.Errorf("legacy stub validateNew_tag1 was not renamed:\n%s", content)
+	}
+	for _, want := range []string{`"new_tag1": validateNewTag1,`, "func validateNewTag1(", "func customValidation(", "func checkRenamed("} {
+		if !strings.Contains(content, want) {
+			t.Errorf("renameLegacyStubs() result does not contain %q:\n%s", want, content)
+		}
+	}
+
+	// 已实现的旧版本验证方法保留原函数名
+	implemented := strings.Replace(testValidationSrc, "func validateNew_tag1(fl validator.FieldLevel) bool {\n\treturn true", "func validateNew_tag1(fl validator.FieldLevel) bool {\n\treturn fl.Field().Len() > 0", 1)
+	got, err = renameLegacyStubs("validation.go", []byte(implemented))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != implemented {
+		t.Errorf("renameLegacyStubs() renamed an implemented function:\n%s", got)
+	}
+}
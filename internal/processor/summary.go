@@ -0,0 +1,166 @@
+package processor
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Summary 插件执行结果汇总，用于排查未生成代码等问题
+type Summary struct {
+	// 生成或已有Validate方法的结构体
+	StructsProcessed []string
+	// 发现的自定义验证标签，按字母顺序排列
+	CustomTags []string
+	// 各types目录中发现的自定义验证标签，键为目录
+	PackageCustomTags map[string][]string
+	// 写入的文件
+	FilesWritten []string
+	// 无需修改或DryRun模式下未写入的文件
+	FilesSkipped []string
+	// CheckOnly模式下内容与生成结果不一致、需要重新生成的文件
+	FilesOutdated []string
+}
+
+// addResult 记录dir目录中单个types文件的生成结果
+func (s *Summary) addResult(dir string, result *GenerateResult) {
+	if s == nil {
+		return
+	}
+	s.StructsProcessed = append(s.StructsProcessed, result.Structs...)
+	for _, tag := range result.CustomTags {
+		if !slices.Contains(s.CustomTags, tag) {
+			s.CustomTags = append(s.CustomTags, tag)
+		}
+	}
+	slices.Sort(s.CustomTags)
+	s.addPackageTags(dir, result.CustomTags)
+}
+
+// addPackageTags 记录目录中发现的自定义验证标签
+func (s *Summary) addPackageTags(dir string, tags []string) {
+	if len(tags) == 0 {
+		return
+	}
+	if s.PackageCustomTags == nil {
+		s.PackageCustomTags = make(map[string][]string)
+	}
+	for _, tag := range tags {
+		if !slices.Contains(s.PackageCustomTags[dir], tag) {
+			s.PackageCustomTags[dir] = append(s.PackageCustomTags[dir], tag)
+		}
+	}
+	slices.Sort(s.PackageCustomTags[dir])
+}
+
+// SharedTagClashes 获取在多个目录中使用的自定义验证标签，格式为"标签 (目录, 目录)"，按标签排序
+// 启用共享验证器时这些标签注册到同一个验证器上，只有最后注册的实现生效
+func (s *Summary) SharedTagClashes() []string {
+	dirs := make(map[string][]string)
+	for dir, tags := range s.PackageCustomTags {
+		for _, tag := range tags {
+			dirs[tag] = append(dirs[tag], dir)
+		}
+	}
+	var clashes []string
+	for _, tag := range slices.Sorted(maps.Keys(dirs)) {
+		if len(dirs[tag]) > 1 {
+			slices.Sort(dirs[tag])
+			clashes = append(clashes, fmt.Sprintf("%s (%s)", tag, strings.Join(dirs[tag], ", ")))
+		}
+	}
+	return clashes
+}
+
+// write 记录写入的文件，同一文件只记录一次
+func (s *Summary) write(path string) {
+	if s == nil || slices.Contains(s.FilesWritten, path) {
+		return
+	}
+	s.FilesWritten = append(s.FilesWritten, path)
+	// 先前处理其他types文件时跳过的文件被写入后不再视为跳过
+	s.FilesSkipped = slices.DeleteFunc(s.FilesSkipped, func(p string) bool {
+		return p == path
+	})
+}
+
+// skip 记录跳过的文件，已写入的文件不再记录为跳过
+func (s *Summary) skip(path string) {
+	if s == nil || slices.Contains(s.FilesWritten, path) || slices.Contains(s.FilesSkipped, path) {
+		return
+	}
+	s.FilesSkipped = append(s.FilesSkipped, path)
+}
+
+// outdate 记录CheckOnly模式下需要重新生成的文件，同一文件只记录一次
+func (s *Summary) outdate(path string) {
+	if s == nil || slices.Contains(s.FilesOutdated, path) {
+		return
+	}
+	s.FilesOutdated = append(s.FilesOutdated, path)
+}
+
+// Merge 合并其他汇总的结果，用于合并并行处理的各组types文件的汇总
+func (s *Summary) Merge(other *Summary) {
+	if s == nil || other == nil {
+		return
+	}
+	s.StructsProcessed = append(s.StructsProcessed, other.StructsProcessed...)
+	for _, tag := range other.CustomTags {
+		if !slices.Contains(s.CustomTags, tag) {
+			s.CustomTags = append(s.CustomTags, tag)
+		}
+	}
+	slices.Sort(s.CustomTags)
+	for dir, tags := range other.PackageCustomTags {
+		s.addPackageTags(dir, tags)
+	}
+	for _, path := range other.FilesWritten {
+		s.write(path)
+	}
+	for _, path := range other.FilesSkipped {
+		s.skip(path)
+	}
+	for _, path := range other.FilesOutdated {
+		s.outdate(path)
+	}
+}
+
+// CheckError CheckOnly模式下存在需要重新生成的文件时返回错误
+func (s *Summary) CheckError() error {
+	if s == nil || len(s.FilesOutdated) == 0 {
+		return nil
+	}
+	return fmt.Errorf("生成的代码不是最新的，需要重新执行插件: %s", strings.Join(s.FilesOutdated, ", "))
+}
+
+// handled 判断本次执行中是否已经处理过该文件（写入或跳过）
+func (s *Summary) handled(path string) bool {
+	return s != nil && (slices.Contains(s.FilesWritten, path) || slices.Contains(s.FilesSkipped, path))
+}
+
+// String 返回汇总的文本格式
+func (s *Summary) String() string {
+	var b strings.Builder
+	b.WriteString("============= 执行汇总 =============\n")
+	items := []struct {
+		name  string
+		items []string
+	}{
+		{"处理的结构体", s.StructsProcessed},
+		{"自定义验证标签", s.CustomTags},
+		{"写入的文件", s.FilesWritten},
+		{"跳过的文件", s.FilesSkipped},
+		{"需要重新生成的文件", s.FilesOutdated},
+	}
+	for _, item := range items {
+		b.WriteString(fmt.Sprintf("%s(%d):", item.name, len(item.items)))
+		for _, v := range item.items {
+			b.WriteString("\n  " + v)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("===================================\n")
+	return b.String()
+}
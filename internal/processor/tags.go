@@ -0,0 +1,52 @@
+package processor
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// splitValidateTag 将validate标签拆分为单个验证器
+// 先按顶层的逗号拆分，再按|拆分或运算的验证器，单引号中的内容（如oneof='red green'）不拆分
+// 例如: required,min=3|max=5 -> [required min=3 max=5]
+func splitValidateTag(tag string) []string {
+	var validators []string
+	for _, token := range splitOutsideQuotes(tag, ',') {
+		for _, v := range splitOutsideQuotes(token, '|') {
+			if v = strings.TrimSpace(v); v != "" {
+				validators = append(validators, v)
+			}
+		}
+	}
+	return validators
+}
+
+// splitOutsideQuotes 按分隔符拆分字符串，忽略单引号中的分隔符
+func splitOutsideQuotes(s string, sep rune) []string {
+	var parts []string
+	var part strings.Builder
+	inQuote := false
+	for _, r := range s {
+		switch {
+		case r == '\'':
+			inQuote = !inQuote
+		case r == sep && !inQuote:
+			parts = append(parts, part.String())
+			part.Reset()
+			continue
+		}
+		part.WriteRune(r)
+	}
+	return append(parts, part.String())
+}
+
+// parseValidator 拆分单个验证器的名称和参数
+// 例如: within=10 -> within, 10, true
+func parseValidator(v string) (name, param string, hasParam bool) {
+	return strings.Cut(v, "=")
+}
+
+// sortedTags 按字母顺序返回标签集合中的标签，map的遍历顺序不固定，生成代码时需要按固定顺序输出，避免重复执行插件时文件反复变化
+func sortedTags(tags map[string]bool) []string {
+	return slices.Sorted(maps.Keys(tags))
+}
@@ -0,0 +1,60 @@
+package processor
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitValidateTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{"rgb|rgba", []string{"rgb", "rgba"}},
+		{"oneof='a b c'", []string{"oneof='a b c'"}},
+		{"required,min=3|max=5", []string{"required", "min=3", "max=5"}},
+		{"omitempty,oneof='a,b' 'c|d'", []string{"omitempty", "oneof='a,b' 'c|d'"}},
+		{"required, mobile", []string{"required", "mobile"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := splitValidateTag(tt.tag); !slices.Equal(got, tt.want) {
+			t.Errorf("splitValidateTag(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestSplitOutsideQuotes(t *testing.T) {
+	tests := []struct {
+		s    string
+		sep  rune
+		want []string
+	}{
+		{"a,b", ',', []string{"a", "b"}},
+		{"oneof='a,b',c", ',', []string{"oneof='a,b'", "c"}},
+		{"rgb|rgba", '|', []string{"rgb", "rgba"}},
+		{"abc", ',', []string{"abc"}},
+	}
+	for _, tt := range tests {
+		if got := splitOutsideQuotes(tt.s, tt.sep); !slices.Equal(got, tt.want) {
+			t.Errorf("splitOutsideQuotes(%q, %q) = %q, want %q", tt.s, tt.sep, got, tt.want)
+		}
+	}
+}
+
+func TestProcessTypesFileSplitsOrTags(t *testing.T) {
+	// rgb|rgba是两个内置标签，不能被当作一个自定义标签生成验证方法
+	dir := t.TempDir()
+	file := writeTypesFile(t, dir, "types.go", "package types\n\n"+
+		"type ColorReq struct {\n"+
+		"\tColor string `json:\"color\" validate:\"required,rgb|rgba\"`\n"+
+		"\tSize  string `json:\"size\" validate:\"oneof='s m' l\"`\n"+
+		"}\n")
+	summary := &Summary{}
+	if _, err := ProcessTypesFile(false, file, Options{EnableCustomValidation: true}, summary); err != nil {
+		t.Fatal(err)
+	}
+	if len(summary.CustomTags) != 0 {
+		t.Errorf("CustomTags = %v, want none", summary.CustomTags)
+	}
+}
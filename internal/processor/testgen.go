@@ -0,0 +1,110 @@
+package processor
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ValidationTestTemplate 验证方法的表格驱动测试模板，%[1]s 为测试函数名，%[2]s 为验证规则，%[3]s 为跳过测试的语句，%[4]s 为合法值，%[5]s 为非法值
+const ValidationTestTemplate = `
+func %[1]s(t *testing.T) {%[3]s
+	tests := []struct {
+		value any
+		valid bool
+	}{
+		{value: %[4]s, valid: true},
+		{value: %[5]s, valid: false},
+	}
+	for _, tt := range tests {
+		err := validate.Var(tt.value, %[2]q)
+		if (err == nil) != tt.valid {
+			t.Errorf("validate.Var(%%v, %[2]s) error = %%v, want valid = %%v", tt.value, err, tt.valid)
+		}
+	}
+}
+`
+
+// validationTestSamples 插件内置验证函数测试用例中的合法值和非法值，键为验证函数名，别名共用验证函数的用例
+var validationTestSamples = map[string][2]string{
+	"validateMobile":      {`"13800138000"`, `"12345"`},
+	"validateIdCard":      {`"11010519491231002X"`, `"123"`},
+	"validateBankcard":    {`"4111111111111111"`, `"4111111111111112"`},
+	"validateChinesename": {`"张三"`, `"Tom"`},
+	"validateVsemver":     {`"v1.2.3-rc.1"`, `"1.2"`},
+	"validateEmails":      {`"a@example.com, b@example.org"`, `"a@example.com,bad"`},
+	"validateRunelen":     {`"中文a"`, `"abcd"`},
+	"validateRunemin":     {`"你好呀"`, `"你好"`},
+	"validateRunemax":     {`"你好呀"`, `"abcd"`},
+	"validateNumstrGt":    {`"10.5"`, `"0"`},
+	"validateNumstrGte":   {`"10.5"`, `"abc"`},
+	"validateNumstrLt":    {`"0"`, `"10.5"`},
+}
+
+// validationTestParams 带参数的插件内置验证函数测试用例使用的参数，键为验证函数名
+// 按字符数验证长度的用例使用汉字与字母混合的字符串，按字符数而不是字节数计算长度
+var validationTestParams = map[string]string{
+	"validateRunelen":   "3",
+	"validateRunemin":   "3",
+	"validateRunemax":   "3",
+	"validateNumstrGt":  "0",
+	"validateNumstrGte": "10.5",
+	"validateNumstrLt":  "10.5",
+}
+
+// validationTestFileName 获取验证文件对应的测试文件名，如validation.go -> validation_test.go
+func validationTestFileName(validationFileName string) string {
+	return strings.TrimSuffix(validationFileName, ".go") + "_test.go"
+}
+
+// validationTestFuncName 获取验证标签的测试函数名，如mobile -> TestValidateMobile
+func validationTestFuncName(tag string) string {
+	return "TestValidate" + exportName(tag)
+}
+
+// validationTests 生成验证标签的测试函数，registered为标签对应的验证函数名，overridden为配置文件覆盖了正则表达式的验证函数
+// 没有内置测试用例或覆盖了正则表达式的标签生成占位用例并跳过测试
+func validationTests(tags []string, registered map[string]string, overridden map[string]bool) string {
+	var code strings.Builder
+	for _, tag := range tags {
+		skip := ""
+		samples, ok := validationTestSamples[registered[tag]]
+		if !ok || overridden[registered[tag]] {
+			skip = fmt.Sprintf("\n\tt.Skip(\"TODO 填写 %s 的合法值和非法值后删除此行\")\n", tag)
+			samples = [2]string{`"valid"`, `"invalid"`}
+		}
+		rule := tag
+		if param, ok := validationTestParams[registered[tag]]; ok {
+			rule += "=" + param
+		}
+		code.WriteString(fmt.Sprintf(ValidationTestTemplate, validationTestFuncName(tag), rule, skip, samples[0], samples[1]))
+	}
+	return code.String()
+}
+
+// generateValidationTestFile 生成验证文件中注册的验证方法的测试文件
+// 测试文件已存在时只追加缺少的测试函数，不修改已有的测试，没有需要追加的测试时返回nil
+func generateValidationTestFile(filePath, pkg string, registered map[string]string, overridden map[string]bool, existing []byte, options Options) ([]byte, error) {
+	tags := slices.Sorted(maps.Keys(registered))
+	if existing == nil {
+		content := fmt.Sprintf("package %s\n\nimport \"testing\"\n", pkg) + validationTests(tags, registered, overridden)
+		return formatSource([]byte(content), options)
+	}
+
+	f, err := parseExistingFile(filePath, existing)
+	if err != nil {
+		return nil, fmt.Errorf("解析现有测试文件失败: %w", err)
+	}
+	declared := declaredFuncs(f)
+	var missing []string
+	for _, tag := range tags {
+		if !declared[validationTestFuncName(tag)] {
+			missing = append(missing, tag)
+		}
+	}
+	if len(missing) == 0 {
+		return nil, nil
+	}
+	return formatSource(append(append([]byte(nil), existing...), validationTests(missing, registered, overridden)...), options)
+}
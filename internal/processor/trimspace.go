@@ -0,0 +1,17 @@
+package processor
+
+import "strings"
+
+// fieldStringExpr 验证函数中读取字段字符串值的表达式
+const fieldStringExpr = "fl.Field().String()"
+
+// trimSpaceValidations 插件内置及配置文件定义的验证函数在验证前去掉字段值首尾的空白
+// 只影响生成的验证函数，validator内置的验证标签（如email、len）不受影响
+func trimSpaceValidations(validations []builtInValidation) []builtInValidation {
+	trimmed := make([]builtInValidation, len(validations))
+	for i, v := range validations {
+		v.Code = strings.ReplaceAll(v.Code, fieldStringExpr, "strings.TrimSpace("+fieldStringExpr+")")
+		trimmed[i] = v
+	}
+	return trimmed
+}
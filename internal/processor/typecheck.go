@@ -0,0 +1,142 @@
+package processor
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// maxTypeCheckErrors 类型检查失败时错误信息中最多列出的错误数
+const maxTypeCheckErrors = 10
+
+// typeCheckImporter 类型检查时使用的导入器，本次生成的包使用内存中检查的结果
+// 其他包与importer.Default()一样读取编译器的导出数据，非标准库的依赖同样通过go list按所在模块解析
+type typeCheckImporter struct {
+	checked map[string]*types.Package
+	gc      types.Importer
+}
+
+func newTypeCheckImporter(fset *token.FileSet, dir string) *typeCheckImporter {
+	lookup := func(path string) (io.ReadCloser, error) {
+		cmd := exec.Command("go", "list", "-export", "-f", "{{.Export}}", path)
+		cmd.Dir = dir
+		out, err := cmd.Output()
+		if err != nil {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				return nil, fmt.Errorf("查找%s的导出数据失败: %s", path, bytes.TrimSpace(exitErr.Stderr))
+			}
+			return nil, err
+		}
+		export := strings.TrimSpace(string(out))
+		if export == "" {
+			return nil, fmt.Errorf("%s没有导出数据", path)
+		}
+		return os.Open(export)
+	}
+	return &typeCheckImporter{
+		checked: make(map[string]*types.Package),
+		gc:      importer.ForCompiler(fset, "gc", lookup),
+	}
+}
+
+func (imp *typeCheckImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := imp.checked[path]; ok {
+		return pkg, nil
+	}
+	return imp.gc.Import(path)
+}
+
+// typeCheck 对生成的文件所在的包进行类型检查，overlay为即将写入的文件内容，覆盖磁盘上的文件
+// importPaths为本次生成的其他包的目录及导入路径，这些包先于types目录检查，types目录中的导入使用内存中的检查结果
+func typeCheck(typesDir string, overlay map[string][]byte, importPaths map[string]string) error {
+	dirs := make(map[string]bool)
+	for filePath := range overlay {
+		dirs[filepath.Dir(filePath)] = true
+	}
+	delete(dirs, typesDir)
+	order := append(slices.Sorted(maps.Keys(dirs)), typesDir)
+
+	fset := token.NewFileSet()
+	imp := newTypeCheckImporter(fset, typesDir)
+	for _, dir := range order {
+		files, err := packageFiles(fset, dir, overlay)
+		if err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			continue
+		}
+		var errs []string
+		conf := types.Config{
+			Importer: imp,
+			Error: func(err error) {
+				errs = append(errs, err.Error())
+			},
+		}
+		pkg, _ := conf.Check(cmp.Or(importPaths[dir], files[0].Name.Name), fset, files, nil)
+		if len(errs) > 0 {
+			if len(errs) > maxTypeCheckErrors {
+				errs = append(errs[:maxTypeCheckErrors], fmt.Sprintf("... 共%d个错误", len(errs)))
+			}
+			return fmt.Errorf("生成的代码未通过类型检查，未写入文件:\n\t%s", strings.Join(errs, "\n\t"))
+		}
+		if path, ok := importPaths[dir]; ok {
+			imp.checked[path] = pkg
+		}
+	}
+	return nil
+}
+
+// packageFiles 解析目录中参与构建的.go文件（测试文件除外），overlay中的文件内容优先于磁盘上的文件
+func packageFiles(fset *token.FileSet, dir string, overlay map[string][]byte) ([]*ast.File, error) {
+	sources := make(map[string][]byte)
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		sources[filepath.Join(dir, name)] = nil
+	}
+	for filePath, content := range overlay {
+		if filepath.Dir(filePath) == dir {
+			sources[filePath] = content
+		}
+	}
+
+	var files []*ast.File
+	for _, filePath := range slices.Sorted(maps.Keys(sources)) {
+		// 排除构建约束不满足的文件
+		if match, err := build.Default.MatchFile(dir, filepath.Base(filePath)); err == nil && !match && sources[filePath] == nil {
+			continue
+		}
+		var src any
+		if content := sources[filePath]; content != nil {
+			src = content
+		}
+		f, err := parser.ParseFile(fset, filePath, src, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("类型检查时解析%s失败: %w", filePath, err)
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
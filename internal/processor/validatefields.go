@@ -0,0 +1,122 @@
+package processor
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path"
+)
+
+const (
+	// FieldErrorType ValidateFields返回的字段验证错误，声明在验证文件中
+	FieldErrorType = `
+// FieldError 字段的验证错误
+type FieldError struct {
+	// 字段的完整路径，如CreateReq.address.zip
+	Path string ` + "`json:\"path\"`" + `
+	// 字段名
+	Field string ` + "`json:\"field\"`" + `
+	// 验证失败的标签
+	Tag string ` + "`json:\"tag\"`" + `
+	// 翻译后的错误信息
+	Message string ` + "`json:\"message\"`" + `
+}
+`
+
+	// ValidateFieldsMethod 返回所有字段验证错误的ValidateFields方法，Path为字段的完整命名空间
+	// %[1]s 为结构体名，%[2]s 为验证器表达式，%[3]s 为翻译单个字段验证错误的表达式，%[4]s 为接收者名称
+	ValidateFieldsMethod = `
+func (%[4]s *%[1]s) ValidateFields() ([]FieldError, error) {
+	err := %[2]s.Struct(%[4]s)
+	if err == nil {
+		return nil, nil
+	}
+	es, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return nil, err
+	}
+	fields := make([]FieldError, 0, len(es))
+	for _, err := range es {
+		fields = append(fields, FieldError{
+			Path:    err.Namespace(),
+			Field:   err.Field(),
+			Tag:     err.Tag(),
+			Message: %[3]s,
+		})
+	}
+	return fields, nil
+}
+`
+
+	// ValidatorPackageValidateFieldsMethod 启用独立验证包时types包中的ValidateFields方法，%[1]s 为结构体名，%[2]s 为接收者名称，%[3]s 为验证包名
+	ValidatorPackageValidateFieldsMethod = `
+func (%[2]s *%[1]s) ValidateFields() ([]%[3]s.FieldError, error) {
+	return %[3]s.ValidateFields(%[2]s)
+}
+`
+
+	// ValidatorPackageValidateFieldsFunc 独立验证包中供types包调用的ValidateFields函数，%s 为翻译单个字段验证错误的表达式
+	ValidatorPackageValidateFieldsFunc = `
+// ValidateFields 验证结构体，返回所有字段的验证错误，Path为字段的完整命名空间
+func ValidateFields(s any) ([]FieldError, error) {
+	err := validate.Struct(s)
+	if err == nil {
+		return nil, nil
+	}
+	es, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return nil, err
+	}
+	fields := make([]FieldError, 0, len(es))
+	for _, err := range es {
+		fields = append(fields, FieldError{
+			Path:    err.Namespace(),
+			Field:   err.Field(),
+			Tag:     err.Tag(),
+			Message: %s,
+		})
+	}
+	return fields, nil
+}
+`
+)
+
+// validateFieldsMethod 生成结构体的ValidateFields方法，启用独立验证包时调用验证包的ValidateFields
+func validateFieldsMethod(structName, validatorExpr, translateExpr, receiver, validatorImport string) string {
+	if validatorImport != "" {
+		return fmt.Sprintf(ValidatorPackageValidateFieldsMethod, structName, receiver, path.Base(validatorImport))
+	}
+	return fmt.Sprintf(ValidateFieldsMethod, structName, validatorExpr, translateExpr, receiver)
+}
+
+// withValidateFields 验证文件缺少FieldError类型时追加到文件末尾，pkgFunc表示验证文件属于独立验证包，同时追加ValidateFields函数
+func withValidateFields(filePath string, content []byte, pkgFunc bool, translateExpr string) ([]byte, error) {
+	f, err := parseExistingFile(filePath, content)
+	if err != nil {
+		return nil, fmt.Errorf("解析验证文件失败: %w", err)
+	}
+	updated := content
+	if !declaresType(f, "FieldError") {
+		updated = append(append([]byte(nil), updated...), FieldErrorType...)
+	}
+	if pkgFunc && !declaredFuncs(f)["ValidateFields"] {
+		updated = append(append([]byte(nil), updated...), fmt.Sprintf(ValidatorPackageValidateFieldsFunc, translateExpr)...)
+	}
+	return updated, nil
+}
+
+// declaresType 判断文件中是否声明了指定名称的类型
+func declaresType(f *ast.File, name string) bool {
+	for _, decl := range f.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			if typeSpec, ok := spec.(*ast.TypeSpec); ok && typeSpec.Name.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package processor
+
+import (
+	"fmt"
+	"path"
+)
+
+const (
+	// ValidateJSONMethod 返回各字段错误信息的ValidateJSON方法，键为字段的json名称，值为翻译后的错误信息
+	// %[1]s 为结构体名，%[2]s 为验证器表达式，%[3]s 为翻译单个字段验证错误的表达式，%[4]s 为接收者名称
+	ValidateJSONMethod = `
+func (%[4]s *%[1]s) ValidateJSON() (map[string]string, error) {
+	errs := make(map[string]string)
+	err := %[2]s.Struct(%[4]s)
+	if err == nil {
+		return errs, nil
+	}
+	es, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return errs, err
+	}
+	for _, err := range es {
+		errs[err.Field()] = %[3]s
+	}
+	return errs, nil
+}
+`
+
+	// ValidatorPackageValidateJSONMethod 启用独立验证包时types包中的ValidateJSON方法，%[1]s 为结构体名，%[2]s 为接收者名称，%[3]s 为验证包名
+	ValidatorPackageValidateJSONMethod = `
+func (%[2]s *%[1]s) ValidateJSON() (map[string]string, error) {
+	return %[3]s.ValidateJSON(%[2]s)
+}
+`
+
+	// ValidatorPackageValidateJSONFunc 独立验证包中供types包调用的ValidateJSON函数
+	ValidatorPackageValidateJSONFunc = `
+// ValidateJSON 验证结构体，返回字段json名称到翻译后错误信息的映射，验证通过时返回空映射
+func ValidateJSON(s any) (map[string]string, error) {
+	errs := make(map[string]string)
+	err := validate.Struct(s)
+	if err == nil {
+		return errs, nil
+	}
+	es, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return errs, err
+	}
+	for _, err := range es {
+		errs[err.Field()] = TranslateField(err)
+	}
+	return errs, nil
+}
+`
+)
+
+// validateJSONMethod 生成结构体的ValidateJSON方法，启用独立验证包时调用验证包的ValidateJSON
+func validateJSONMethod(structName, validatorExpr string, tr *translatorRef, receiver, validatorImport string) string {
+	if validatorImport != "" {
+		return fmt.Sprintf(ValidatorPackageValidateJSONMethod, structName, receiver, path.Base(validatorImport))
+	}
+	return fmt.Sprintf(ValidateJSONMethod, structName, validatorExpr, tr.fieldExpr("err"), receiver)
+}
@@ -0,0 +1,110 @@
+package processor
+
+import (
+	"fmt"
+	"go/token"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// ValidatorPackageValidateMethod 启用独立验证包时types包中的Validate方法，%[1]s 为结构体名，%[2]s 为接收者名称，%[3]s 为验证包名
+	ValidatorPackageValidateMethod = `
+func (%[2]s *%[1]s) Validate() error {
+	return %[3]s.Validate(%[2]s)
+}
+`
+
+	// ValidatorPackageRegisterTranslator 独立验证包在验证器上注册同一包中的翻译器
+	ValidatorPackageRegisterTranslator = `
+// 在验证器上注册翻译器的字段名和翻译
+func init() {
+	Register(validate)
+}
+`
+
+	// ValidatorPackageFuncs 独立验证包中供types包调用的验证函数，%s 为翻译单个字段验证错误的表达式
+	ValidatorPackageFuncs = `
+// Validate 验证结构体，返回第一个字段的验证错误
+func Validate(s any) error {
+	return firstError(validate.Struct(s))
+}
+
+// ValidateCtx 使用context验证结构体，自定义验证方法可以读取请求上下文中的值
+func ValidateCtx(ctx context.Context, s any) error {
+	return firstError(validate.StructCtx(ctx, s))
+}
+
+// firstError 返回第一个字段翻译后的验证错误
+func firstError(err error) error {
+	es, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+	for _, err := range es {
+		return fmt.Errorf("%%s", %s)
+	}
+	return err
+}
+`
+)
+
+// modulePackage 校验模块内包的相对路径，desc用于错误信息，未设置时返回空字符串
+func modulePackage(dir, desc string) (string, error) {
+	if dir == "" {
+		return "", nil
+	}
+	cleaned := path.Clean(filepath.ToSlash(dir))
+	if path.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("%s必须是模块内的相对路径: %s", desc, dir)
+	}
+	if !token.IsIdentifier(path.Base(cleaned)) {
+		return "", fmt.Errorf("%s的目录名不是合法的包名: %s", desc, path.Base(cleaned))
+	}
+	return cleaned, nil
+}
+
+// validatorPackage 获取独立验证包相对于模块根目录的路径，未启用时返回空字符串
+// 验证包不能引用types包中的类型，需要按结构体生成代码的选项不能同时使用
+func validatorPackage(options Options) (string, error) {
+	dir, err := modulePackage(options.ValidatorPackage, "独立验证包")
+	if err != nil || dir == "" {
+		return dir, err
+	}
+	conflicts := []struct {
+		enabled bool
+		flag    string
+	}{
+		{options.PerStructValidator, "--per-struct"},
+		{options.GenerateErrorCodes, "--error-codes"},
+		{options.GenerateErrorHandler, "--error-handler"},
+		{options.SharedTranslatorPackage != "", "--shared-translator"},
+		{options.Prune, "--prune"},
+	}
+	for _, c := range conflicts {
+		if c.enabled {
+			return "", fmt.Errorf("独立验证包不能与%s同时使用", c.flag)
+		}
+	}
+	return dir, nil
+}
+
+// validatorPackageCode 生成独立验证包的验证文件中验证器变量的声明及供types包调用的验证函数
+func validatorPackageCode(lang string, options Options) string {
+	var code strings.Builder
+	translateExpr := "err.Translate(trans)"
+	if options.EnableTranslator {
+		// 翻译器文件与验证文件在同一包中，通过Register注册翻译
+		code.WriteString("\n" + validatorVar(options) + "\n")
+		code.WriteString(ValidatorPackageRegisterTranslator)
+		translateExpr = "TranslateField(err)"
+	} else {
+		code.WriteString(validatorVarDecl(lang, options, nil))
+	}
+	code.WriteString(fmt.Sprintf(ValidatorPackageFuncs, translateExpr))
+	if options.GenerateJSONMethod {
+		code.WriteString(ValidatorPackageValidateJSONFunc)
+	}
+	return code.String()
+}
@@ -0,0 +1,151 @@
+package processor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// diffContext 统一差异格式中变更前后保留的上下文行数
+const diffContext = 3
+
+// writeFile 写入生成的文件，DryRun及CheckOnly模式下不写入，只向标准输出打印与现有文件的统一差异
+func writeFile(filePath string, content []byte, dryRun bool) error {
+	if !dryRun {
+		// 共享翻译器包等目录可能还不存在
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return err
+		}
+		return os.WriteFile(filePath, content, 0644)
+	}
+
+	oldContent, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	fmt.Print(unifiedDiff(filePath, string(oldContent), string(content)))
+	return nil
+}
+
+// diffLine 差异中的一行，op为' '、'-'或'+'
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// unifiedDiff 生成新旧内容的统一差异格式文本，内容相同时返回空字符串
+func unifiedDiff(filePath, oldContent, newContent string) string {
+	if oldContent == newContent {
+		return ""
+	}
+	lines := diffLines(splitLines(oldContent), splitLines(newContent))
+
+	var diff strings.Builder
+	fromFile := "a/" + strings.TrimPrefix(filePath, "/")
+	if oldContent == "" {
+		fromFile = "/dev/null"
+	}
+	diff.WriteString(fmt.Sprintf("--- %s\n+++ b/%s\n", fromFile, strings.TrimPrefix(filePath, "/")))
+
+	// 按变更位置划分区块，相邻变更之间的上下文不超过两倍时合并为同一区块
+	for start := 0; start < len(lines); {
+		if lines[start].op == ' ' {
+			start++
+			continue
+		}
+		hunkStart := max(start-diffContext, 0)
+		end := start
+		for i := start; i < len(lines); i++ {
+			if lines[i].op != ' ' {
+				end = i
+			} else if i-end > 2*diffContext {
+				break
+			}
+		}
+		hunkEnd := min(end+diffContext+1, len(lines))
+
+		// 计算区块在新旧文件中的起始行号和行数
+		oldStart, newStart := 1, 1
+		for _, line := range lines[:hunkStart] {
+			if line.op != '+' {
+				oldStart++
+			}
+			if line.op != '-' {
+				newStart++
+			}
+		}
+		oldCount, newCount := 0, 0
+		for _, line := range lines[hunkStart:hunkEnd] {
+			if line.op != '+' {
+				oldCount++
+			}
+			if line.op != '-' {
+				newCount++
+			}
+		}
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+
+		diff.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount))
+		for _, line := range lines[hunkStart:hunkEnd] {
+			diff.WriteString(string(line.op) + line.text + "\n")
+		}
+		start = hunkEnd
+	}
+	return diff.String()
+}
+
+// splitLines 按行拆分内容，忽略末尾的换行
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+}
+
+// diffLines 基于最长公共子序列计算逐行差异
+func diffLines(oldLines, newLines []string) []diffLine {
+	// lcs[i][j] 为oldLines[i:]与newLines[j:]的最长公共子序列长度
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(oldLines) && j < len(newLines) {
+		switch {
+		case oldLines[i] == newLines[j]:
+			lines = append(lines, diffLine{' ', oldLines[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{'-', oldLines[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', newLines[j]})
+			j++
+		}
+	}
+	for ; i < len(oldLines); i++ {
+		lines = append(lines, diffLine{'-', oldLines[i]})
+	}
+	for ; j < len(newLines); j++ {
+		lines = append(lines, diffLine{'+', newLines[j]})
+	}
+	return lines
+}
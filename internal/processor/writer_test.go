@@ -0,0 +1,23 @@
+package processor
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name       string
+		oldContent string
+		newContent string
+		want       string
+	}{
+		{"unchanged", "a\nb\n", "a\nb\n", ""},
+		{"new file", "", "a\n", "--- /dev/null\n+++ b/dir/file.go\n@@ -0,0 +1,1 @@\n+a\n"},
+		{"changed line", "a\nb\nc\n", "a\nB\nc\n", "--- a/dir/file.go\n+++ b/dir/file.go\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("/dir/file.go", tt.oldContent, tt.newContent); got != tt.want {
+				t.Errorf("unifiedDiff() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
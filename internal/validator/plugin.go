@@ -1,43 +1,217 @@
 package validator
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/xs-cw/goctl-validate/internal/processor"
 
 	"github.com/zeromicro/go-zero/tools/goctl/plugin"
 )
 
-// ProcessPlugin 处理插件逻辑
-func ProcessPlugin(p *plugin.Plugin, options processor.Options) error {
-	// 根据p.Api 直接处理
-	// return processor.ProcessTypesAPI(p, options)
-	// 查找并处理types.go文件
-	// 目录中是否已经生成过声明变量
-	genFlag := false
-	err := filepath.Walk(p.Dir, func(path string, info os.FileInfo, err error) error {
+// ProcessPlugin 处理插件逻辑，返回执行结果汇总，调试模式下打印汇总
+// CheckOnly模式下不写入文件，存在需要重新生成的文件时返回错误
+// 单个types文件处理失败（如存在语法错误）时继续处理其他文件，最后返回所有文件的错误
+func ProcessPlugin(p *plugin.Plugin, options processor.Options) (*processor.Summary, error) {
+	summary := &processor.Summary{}
+	// 生成的文件名与goctl生成的文件使用相同的命名风格
+	if options.NamingStyle == "" {
+		options.NamingStyle = p.Style
+	}
+	// 输出目录可能位于项目目录中，查找types文件时跳过
+	if options.OutputDir != "" {
+		outputDir, err := filepath.Abs(options.OutputDir)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		if info.IsDir() {
-			return nil
+		options.OutputDir = outputDir
+	}
+	// 根据p.Api直接处理，不依赖types.go是否已经写入
+	if options.FromAPI {
+		if err := processor.ProcessTypesAPI(p, options, summary); err != nil {
+			return nil, err
 		}
-		if strings.Contains(path, "internal/types/") && strings.HasSuffix(info.Name(), ".go") {
-			if options.DebugMode {
-				fmt.Printf("处理文件: %s\n", path)
-			}
-			gen, err := processor.ProcessTypesFile(genFlag, path, options)
-			if err != nil {
-				return err
+		if options.DebugMode {
+			options.Log().Debugf("%s", summary)
+		}
+		return summary, errors.Join(checkSharedTags(summary, options), summary.CheckError())
+	}
+	// 查找types目录中的.go文件，按目录分组后由有限数量的worker并行处理
+	dirs := typesDirs(options)
+	files, err := typesFiles(p.Dir, dirs, options)
+	if err != nil {
+		return nil, err
+	}
+	matched := len(files) > 0
+	groups := groupFiles(files, options)
+	results := make([]groupResult, len(groups))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range pluginWorkers(options, len(groups)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = processGroup(groups[i], options)
 			}
-			if gen {
-				genFlag = true
+		}()
+	}
+	for i := range groups {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	// 按分组的顺序合并结果，汇总及错误的顺序与处理的并发无关
+	var errs []error
+	for _, result := range results {
+		summary.Merge(result.summary)
+		errs = append(errs, result.errs...)
+	}
+	if options.DebugMode {
+		if !matched {
+			options.Log().Warnf("在 %s 下未找到位于 %s 目录中的.go文件，未生成任何验证代码", p.Dir, strings.Join(dirs, ", "))
+		}
+		options.Log().Debugf("%s", summary)
+	}
+	errs = append(errs, checkSharedTags(summary, options))
+	if options.CheckOnly {
+		errs = append(errs, summary.CheckError())
+	}
+	return summary, errors.Join(errs...)
+}
+
+// checkSharedTags 启用共享验证器时检查多个包中同名的自定义验证标签
+// 这些标签注册到同一个验证器上，只有最后注册的实现生效，输出警告，启用Strict时返回错误
+func checkSharedTags(summary *processor.Summary, options processor.Options) error {
+	if !options.SharedValidator {
+		return nil
+	}
+	clashes := summary.SharedTagClashes()
+	if len(clashes) == 0 {
+		return nil
+	}
+	options.Log().Warnf("以下自定义验证标签在多个包中注册到共享验证器，只有最后注册的实现生效，请保持各包的实现一致或使用--per-struct: %s", strings.Join(clashes, "; "))
+	if options.Strict {
+		return fmt.Errorf("自定义验证标签在多个包中注册到共享验证器: %s", strings.Join(clashes, "; "))
+	}
+	return nil
+}
+
+// groupResult 一组types文件的处理结果
+type groupResult struct {
+	summary *processor.Summary
+	errs    []error
+}
+
+// typesFiles 获取root下位于types目录中的.go文件，按路径排序
+// 跳过插件生成的验证文件、翻译器文件及测试文件等，设置了输出目录时跳过该目录
+func typesFiles(root string, dirs []string, options processor.Options) ([]string, error) {
+	skipDir := options.OutputDir
+	var files []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && skipDir != "" {
+			if abs, err := filepath.Abs(path); err == nil && abs == skipDir {
+				return filepath.SkipDir
 			}
 		}
+		if !info.IsDir() && inTypesDir(path, dirs) && strings.HasSuffix(info.Name(), ".go") && !processor.IsGeneratedFile(path, options) {
+			files = append(files, path)
+		}
 		return nil
 	})
-	return err
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// groupFiles 将types文件分组，同一组的文件按顺序处理，不同组可以并行处理
+// 同一目录中的文件共用生成的验证文件及声明变量，分为一组；共享翻译器包及独立验证包被所有目录共用，所有文件分为一组
+func groupFiles(files []string, options processor.Options) [][]string {
+	if options.SharedTranslatorPackage != "" || options.ValidatorPackage != "" {
+		return [][]string{files}
+	}
+	var groups [][]string
+	// 目录对应的分组下标，子目录中的文件可能排在同一目录的文件之间
+	index := make(map[string]int)
+	for _, file := range files {
+		dir := filepath.Dir(file)
+		if i, ok := index[dir]; ok {
+			groups[i] = append(groups[i], file)
+			continue
+		}
+		index[dir] = len(groups)
+		groups = append(groups, []string{file})
+	}
+	return groups
+}
+
+// pluginWorkers 获取并行处理的worker数量，不超过GOMAXPROCS及分组数量
+// DryRun及CheckOnly模式下打印的差异需要保持顺序，只使用一个worker
+func pluginWorkers(options processor.Options, groups int) int {
+	if options.DryRun || options.CheckOnly {
+		return 1
+	}
+	return max(1, min(runtime.GOMAXPROCS(0), groups))
+}
+
+// processGroup 按顺序处理一组types文件，单个文件处理失败时继续处理其他文件
+func processGroup(files []string, options processor.Options) groupResult {
+	result := groupResult{summary: &processor.Summary{}}
+	// 各目录中是否已经生成过声明变量，不同types目录属于不同的包，需要分别声明
+	genFlags := make(map[string]bool)
+	for _, path := range files {
+		if options.DebugMode {
+			options.Log().Debugf("处理文件: %s", path)
+		}
+		dir := filepath.Dir(path)
+		gen, err := processor.ProcessTypesFile(genFlags[dir], path, options, result.summary)
+		if err != nil {
+			result.errs = append(result.errs, fmt.Errorf("处理%s失败: %w", path, err))
+			continue
+		}
+		if gen {
+			genFlags[dir] = true
+		}
+	}
+	return result
+}
+
+// typesDirs 获取需要处理的types目录，统一使用/分隔并以/结尾
+func typesDirs(options processor.Options) []string {
+	var dirs []string
+	for _, dir := range append([]string{options.TypesDir}, options.TypesDirs...) {
+		dir = strings.TrimSpace(dir)
+		if dir == "" {
+			continue
+		}
+		dir = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(dir)), "./")
+		dirs = append(dirs, strings.TrimSuffix(dir, "/")+"/")
+	}
+	if len(dirs) == 0 {
+		dirs = append(dirs, processor.DefaultTypesDir)
+	}
+	return dirs
+}
+
+// inTypesDir 判断文件是否位于某个types目录中
+func inTypesDir(path string, dirs []string) bool {
+	path = "/" + strings.TrimPrefix(filepath.ToSlash(path), "/")
+	for _, dir := range dirs {
+		if strings.Contains(path, "/"+strings.TrimPrefix(dir, "/")) {
+			return true
+		}
+	}
+	return false
 }
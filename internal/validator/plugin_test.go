@@ -0,0 +1,487 @@
+package validator
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/xs-cw/goctl-validate/internal/processor"
+
+	"github.com/zeromicro/go-zero/tools/goctl/api/spec"
+	"github.com/zeromicro/go-zero/tools/goctl/plugin"
+)
+
+// testLogger 记录调试及警告日志，多个目录由worker并行处理时同时写入
+type testLogger struct {
+	mu       sync.Mutex
+	debugs   []string
+	warnings []string
+}
+
+func (l *testLogger) Debugf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.debugs = append(l.debugs, fmt.Sprintf(format, args...))
+}
+
+func (l *testLogger) Warnf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warnings = append(l.warnings, fmt.Sprintf(format, args...))
+}
+
+// writeFile 写入root下的文件，自动创建目录
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// typesSrc 使用指定验证标签的types文件
+func typesSrc(name, tag string) string {
+	return "package types\n\n" +
+		"type " + name + " struct {\n" +
+		"\tValue string `json:\"value\" validate:\"" + tag + "\"`\n" +
+		"}\n"
+}
+
+func TestSharedValidatorTagClash(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   processor.Options
+		wantWarn  bool
+		wantError bool
+	}{
+		{"per package validator", processor.Options{}, false, false},
+		{"shared validator", processor.Options{SharedValidator: true}, true, false},
+		{"shared validator strict", processor.Options{SharedValidator: true, Strict: true}, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeFile(t, root, "user/internal/types/types.go", typesSrc("UserReq", "required,age_range"))
+			writeFile(t, root, "order/internal/types/types.go", typesSrc("OrderReq", "age_range"))
+			writeFile(t, root, "item/internal/types/types.go", typesSrc("ItemReq", "sku"))
+
+			logger := &testLogger{}
+			options := tt.options
+			options.TypesDir = processor.DefaultTypesDir
+			options.EnableCustomValidation = true
+			options.Logger = logger
+			_, err := ProcessPlugin(&plugin.Plugin{Dir: root}, options)
+			// 启用Strict时未实现的验证方法同样返回错误，只检查共享验证器的错误
+			if gotError := err != nil && strings.Contains(err.Error(), "共享验证器"); gotError != tt.wantError {
+				t.Fatalf("ProcessPlugin() error = %v, wantError %v", err, tt.wantError)
+			}
+			// sku只在一个包中使用，不会冲突
+			warned := len(logger.warnings) > 0 && strings.Contains(logger.warnings[0], "age_range (")
+			if warned != tt.wantWarn || (warned && strings.Contains(logger.warnings[0], "sku")) {
+				t.Errorf("warnings = %q, want a warning for age_range only: %v", logger.warnings, tt.wantWarn)
+			}
+		})
+	}
+}
+
+func TestTypesFilesSkipsGeneratedFiles(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		// goctl生成的types文件带有goctl的生成代码标记，仍需处理
+		"internal/types/types.go": "// Code generated by goctl. DO NOT EDIT.\n" + typesSrc("UserReq", "required"),
+		"internal/types/order.go": typesSrc("OrderReq", "required"),
+		// 插件生成的文件及测试文件
+		"internal/types/checks.go":                   "package types\n",
+		"internal/types/translator.go":               "package types\n",
+		"internal/types/errcode.go":                  "package types\n",
+		"internal/types/errhandler.go":               "package types\n",
+		"internal/types/enums.go":                    "package types\n",
+		"internal/types/validation_methods.go":       "package types\n",
+		"internal/types/order_validation_methods.go": processor.GeneratedHeader + "package types\n",
+		"internal/types/validation_test.go":          "package types\n",
+		"internal/handler/handler.go":                "package handler\n",
+	}
+	for name, content := range files {
+		writeFile(t, root, name, content)
+	}
+	options := processor.Options{ValidationFileName: "checks.go"}
+	got, err := typesFiles(root, []string{processor.DefaultTypesDir}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(root, "internal/types/order.go"), filepath.Join(root, "internal/types/types.go")}
+	if !slices.Equal(got, want) {
+		t.Errorf("typesFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestNoValidationFileWithoutTags(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "internal/types/types.go", "package types\n\n"+
+		"type PingResp struct {\n"+
+		"\tName string `json:\"name\"`\n"+
+		"}\n")
+	options := processor.Options{
+		TypesDir:               processor.DefaultTypesDir,
+		EnableCustomValidation: true,
+		EnableTranslator:       true,
+		Logger:                 &testLogger{},
+	}
+	if _, err := ProcessPlugin(&plugin.Plugin{Dir: root}, options); err != nil {
+		t.Fatal(err)
+	}
+	// 没有结构体使用验证标签（也没有以Req结尾的结构体）时不生成任何文件
+	entries, err := os.ReadDir(filepath.Join(root, "internal/types"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.Name() != "types.go" {
+			t.Errorf("ProcessPlugin() wrote %s for a types file without validate tags", entry.Name())
+		}
+	}
+}
+
+// newTestModule 创建引用validator的临时模块，返回模块根目录，依赖从本地模块缓存中读取
+// 需要go命令编译生成的代码，-short时跳过
+func newTestModule(t *testing.T) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("skipping compilation of generated code in short mode")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	sum, err := os.ReadFile(filepath.Join("..", "..", "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	writeFile(t, root, "go.mod", "module example.com/gen\n\ngo 1.23.7\n\n"+
+		"require (\n"+
+		"\tgithub.com/go-playground/locales v0.14.1\n"+
+		"\tgithub.com/go-playground/universal-translator v0.18.1\n"+
+		"\tgithub.com/go-playground/validator/v10 v10.26.0\n"+
+		")\n")
+	writeFile(t, root, "go.sum", string(sum))
+	return root
+}
+
+// goVet 编译检查临时模块中的全部包
+func goVet(t *testing.T, root string) {
+	t.Helper()
+	cmd := exec.Command("go", "vet", "./...")
+	cmd.Dir = root
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOSUMDB=off", "GOWORK=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go vet failed: %v\n%s", err, out)
+	}
+}
+
+func TestGroupTypesPackages(t *testing.T) {
+	root := newTestModule(t)
+	writeFile(t, root, "internal/types/user/types.go", strings.Replace(typesSrc("UserReq", "required,mobile,age_range"), "package types", "package user", 1))
+	writeFile(t, root, "internal/types/order/types.go", strings.Replace(typesSrc("OrderReq", "required,mobile,age_range"), "package types", "package order", 1))
+	options := processor.Options{
+		TypesDir:               processor.DefaultTypesDir,
+		EnableCustomValidation: true,
+		EnableTranslator:       true,
+		Logger:                 &testLogger{},
+	}
+	if _, err := ProcessPlugin(&plugin.Plugin{Dir: root}, options); err != nil {
+		t.Fatal(err)
+	}
+	// 每个分组目录生成各自的验证文件和翻译器文件，分别声明验证器并注册验证方法
+	for _, group := range []string{"user", "order"} {
+		for _, name := range []string{"validation.go", "translator.go"} {
+			if _, err := os.Stat(filepath.Join(root, "internal/types", group, name)); err != nil {
+				t.Errorf("%s/%s was not generated: %v", group, name, err)
+			}
+		}
+	}
+	goVet(t, root)
+}
+
+func TestCustomTypesDir(t *testing.T) {
+	tests := []struct {
+		name     string
+		options  processor.Options
+		want     []string
+		wantWarn bool
+	}{
+		{"types dir", processor.Options{TypesDir: "pkg/model"}, []string{"pkg/model"}, false},
+		{"types dirs", processor.Options{TypesDir: "pkg/model", TypesDirs: []string{"./api/dto/"}}, []string{"pkg/model", "api/dto"}, false},
+		{"default", processor.Options{}, []string{"internal/types"}, false},
+		{"no match", processor.Options{TypesDir: "missing"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, dir := range []string{"pkg/model", "api/dto", "internal/types"} {
+				writeFile(t, root, dir+"/types.go", typesSrc("UserReq", "required"))
+			}
+			logger := &testLogger{}
+			options := tt.options
+			options.DebugMode = true
+			options.Logger = logger
+			if _, err := ProcessPlugin(&plugin.Plugin{Dir: root}, options); err != nil {
+				t.Fatal(err)
+			}
+			for _, dir := range []string{"pkg/model", "api/dto", "internal/types"} {
+				_, err := os.Stat(filepath.Join(root, dir, "validation.go"))
+				if generated := err == nil; generated != slices.Contains(tt.want, dir) {
+					t.Errorf("validation.go generated in %s = %v, want %v", dir, generated, !generated)
+				}
+			}
+			// 没有找到types文件时在调试模式中提示
+			if warned := slices.ContainsFunc(logger.warnings, func(w string) bool { return strings.Contains(w, "未找到") }); warned != tt.wantWarn {
+				t.Errorf("warnings = %q, want a missing types dir warning: %v", logger.warnings, tt.wantWarn)
+			}
+		})
+	}
+}
+
+func TestProcessPluginSummary(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "internal/types/types.go", typesSrc("UserReq", "required,age_range"))
+	writeFile(t, root, "internal/types/order.go", typesSrc("OrderReq", "required"))
+	logger := &testLogger{}
+	options := processor.Options{EnableCustomValidation: true, DebugMode: true, Logger: logger}
+	summary, err := ProcessPlugin(&plugin.Plugin{Dir: root}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"OrderReq", "UserReq"}; !slices.Equal(summary.StructsProcessed, want) {
+		t.Errorf("StructsProcessed = %v, want %v", summary.StructsProcessed, want)
+	}
+	if want := []string{"age_range"}; !slices.Equal(summary.CustomTags, want) {
+		t.Errorf("CustomTags = %v, want %v", summary.CustomTags, want)
+	}
+	written := make([]string, 0, len(summary.FilesWritten))
+	for _, file := range summary.FilesWritten {
+		written = append(written, filepath.Base(file))
+	}
+	slices.Sort(written)
+	if want := []string{"order.go", "types.go", "validation.go"}; !slices.Equal(written, want) {
+		t.Errorf("FilesWritten = %v, want %v", summary.FilesWritten, want)
+	}
+	// 调试模式下输出汇总
+	if !slices.ContainsFunc(logger.debugs, func(d string) bool { return strings.Contains(d, "处理的结构体(2)") }) {
+		t.Errorf("debug logs = %q, want the summary", logger.debugs)
+	}
+}
+
+func TestSharedTranslatorPackage(t *testing.T) {
+	root := newTestModule(t)
+	writeFile(t, root, "user/internal/types/types.go", typesSrc("UserReq", "required,mobile"))
+	writeFile(t, root, "order/internal/types/types.go", typesSrc("OrderReq", "required,idcard"))
+	options := processor.Options{
+		EnableTranslator:        true,
+		SharedTranslatorPackage: "internal/validatetrans",
+		Logger:                  &testLogger{},
+	}
+	if _, err := ProcessPlugin(&plugin.Plugin{Dir: root}, options); err != nil {
+		t.Fatal(err)
+	}
+	// 翻译器只生成到共享翻译器包中，各types包不再生成translator.go
+	var translators []string
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err == nil && d.Name() == "translator.go" {
+			rel, _ := filepath.Rel(root, path)
+			translators = append(translators, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"internal/validatetrans/translator.go"}; !slices.Equal(translators, want) {
+		t.Errorf("translator files = %v, want %v", translators, want)
+	}
+	goVet(t, root)
+}
+
+func TestProcessPluginFromAPI(t *testing.T) {
+	root := newTestModule(t)
+	api := &spec.ApiSpec{
+		Types: []spec.Type{
+			spec.DefineStruct{
+				RawName: "CreateUserReq",
+				Members: []spec.Member{
+					{Name: "Mobile", Type: spec.PrimitiveType{RawName: "string"}, Tag: "`json:\"mobile\" validate:\"required,mobile\"`"},
+				},
+			},
+		},
+	}
+	options := processor.Options{FromAPI: true, EnableTranslator: true, Logger: &testLogger{}}
+	// types.go还没有写入时根据api定义生成
+	summary, err := ProcessPlugin(&plugin.Plugin{Api: api, Dir: root}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(summary.StructsProcessed, []string{"CreateUserReq"}) {
+		t.Errorf("StructsProcessed = %v, want [CreateUserReq]", summary.StructsProcessed)
+	}
+	// goctl之后写入的types.go与生成的方法文件一起编译
+	writeFile(t, root, "internal/types/types.go", "package types\n\n"+
+		"type CreateUserReq struct {\n"+
+		"\tMobile string `json:\"mobile\" validate:\"required,mobile\"`\n"+
+		"}\n")
+	goVet(t, root)
+}
+
+func TestPluginStyleFileNames(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "internal/types/types.go", typesSrc("UserReq", "required"))
+	writeFile(t, root, "internal/types/order.go", typesSrc("OrderReq", "required"))
+	options := processor.Options{MethodsInSeparateFile: true, Logger: &testLogger{}}
+	// 未设置命名风格时使用goctl的命名风格，多个单词的文件名按风格格式化
+	summary, err := ProcessPlugin(&plugin.Plugin{Dir: root, Style: "goZero"}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	written := make([]string, 0, len(summary.FilesWritten))
+	for _, file := range summary.FilesWritten {
+		written = append(written, filepath.Base(file))
+	}
+	slices.Sort(written)
+	if want := []string{"orderValidationMethods.go", "validation.go", "validationMethods.go"}; !slices.Equal(written, want) {
+		t.Errorf("FilesWritten = %v, want %v", written, want)
+	}
+	// 再次执行时跳过按命名风格生成的文件，不会作为types文件处理
+	files, err := typesFiles(root, []string{processor.DefaultTypesDir}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{filepath.Join(root, "internal/types/order.go"), filepath.Join(root, "internal/types/types.go")}; !slices.Equal(files, want) {
+		t.Errorf("typesFiles() = %v, want %v", files, want)
+	}
+}
+
+func TestProcessPluginContinuesAfterParseError(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "internal/types/types.go", typesSrc("UserReq", "required"))
+	writeFile(t, root, "internal/types/broken.go", "package types\n\ntype BrokenReq struct {\n")
+	_, err := ProcessPlugin(&plugin.Plugin{Dir: root}, processor.Options{Logger: &testLogger{}})
+	broken := filepath.Join(root, "internal/types/broken.go")
+	if err == nil || !strings.Contains(err.Error(), broken) {
+		t.Fatalf("ProcessPlugin() error = %v, want an error naming %s", err, broken)
+	}
+	// 语法错误的文件不影响其他文件的生成
+	content, err := os.ReadFile(filepath.Join(root, "internal/types/types.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "func (r *UserReq) Validate() error") {
+		t.Errorf("types.go has no Validate method:\n%s", content)
+	}
+	if _, err := os.Stat(filepath.Join(root, "internal/types/validation.go")); err != nil {
+		t.Errorf("validation.go was not generated: %v", err)
+	}
+}
+
+func TestCheckOnly(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "internal/types/types.go", typesSrc("UserReq", "required,mobile"))
+	p := &plugin.Plugin{Dir: root}
+	check := processor.Options{CheckOnly: true, Logger: &testLogger{}}
+	// 还没有生成时需要生成的文件都已过期，不写入任何文件
+	typesPath := filepath.Join(root, "internal/types/types.go")
+	if _, err := ProcessPlugin(p, check); err == nil || !strings.Contains(err.Error(), typesPath) {
+		t.Fatalf("ProcessPlugin() error = %v, want %s to be outdated", err, typesPath)
+	}
+	if _, err := os.Stat(filepath.Join(root, "internal/types/validation.go")); !os.IsNotExist(err) {
+		t.Fatalf("validation.go was written in check mode: %v", err)
+	}
+	if _, err := ProcessPlugin(p, processor.Options{Logger: &testLogger{}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ProcessPlugin(p, check); err != nil {
+		t.Fatalf("ProcessPlugin() error = %v, want the generated files to be current", err)
+	}
+	// types文件新增结构体后需要重新生成
+	content, err := os.ReadFile(typesPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale := string(content) + strings.TrimPrefix(typesSrc("OrderReq", "required"), "package types\n")
+	writeFile(t, root, "internal/types/types.go", stale)
+	if _, err := ProcessPlugin(p, check); err == nil || !strings.Contains(err.Error(), typesPath) {
+		t.Fatalf("ProcessPlugin() error = %v, want %s to be outdated", err, typesPath)
+	}
+	if content, _ := os.ReadFile(typesPath); string(content) != stale {
+		t.Errorf("types.go was rewritten in check mode")
+	}
+}
+
+func TestProcessPluginManyFiles(t *testing.T) {
+	// 多个分组目录并行处理，每个目录中的多个文件按顺序处理，只声明一次验证器
+	writeTypes := func(root string) {
+		for g := range 8 {
+			group := fmt.Sprintf("group%d", g)
+			for f := range 3 {
+				src := strings.Replace(typesSrc(fmt.Sprintf("Item%dReq", f), "required,mobile,tag_a"), "package types", "package "+group, 1)
+				writeFile(t, root, fmt.Sprintf("internal/types/%s/item%d.go", group, f), src)
+			}
+		}
+	}
+	snapshot := func(root string) map[string]string {
+		files := make(map[string]string)
+		err := filepath.WalkDir(filepath.Join(root, "internal"), func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			content, err := os.ReadFile(path)
+			rel, _ := filepath.Rel(root, path)
+			files[filepath.ToSlash(rel)] = string(content)
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return files
+	}
+	options := processor.Options{EnableCustomValidation: true, EnableTranslator: true, Logger: &testLogger{}}
+
+	var first map[string]string
+	var firstSummary string
+	for range 3 {
+		root := newTestModule(t)
+		writeTypes(root)
+		summary, err := ProcessPlugin(&plugin.Plugin{Dir: root}, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(summary.StructsProcessed) != 24 {
+			t.Fatalf("StructsProcessed = %v, want 24 structs", summary.StructsProcessed)
+		}
+		files, summaryText := snapshot(root), strings.ReplaceAll(summary.String(), root, "")
+		for g := range 8 {
+			validation := files[fmt.Sprintf("internal/types/group%d/validation.go", g)]
+			if strings.Count(validation, "func validateTagA(") != 1 {
+				t.Errorf("group%d/validation.go does not declare validateTagA once:\n%s", g, validation)
+			}
+		}
+		// 多次执行生成的文件及汇总完全相同
+		if first == nil {
+			first, firstSummary = files, summaryText
+			goVet(t, root)
+			continue
+		}
+		if !maps.Equal(files, first) {
+			t.Errorf("generated files differ between runs")
+		}
+		if summaryText != firstSummary {
+			t.Errorf("summary differs between runs:\n%s\nwant:\n%s", summaryText, firstSummary)
+		}
+	}
+}
@@ -19,11 +19,87 @@ var (
 	debugMode bool
 	// 是否启用翻译器功能
 	enableTranslator bool
+	// 翻译器是否延迟初始化
+	lazyTranslator bool
+	// 错误信息中的字段名是否优先使用label标签
+	useLabelTag bool
+	// 是否生成双语错误信息的翻译函数
+	bilingual bool
+	// 翻译语言
+	translationLanguage string
+	// 多语言翻译
+	translationLanguages []string
+	// 是否为每个结构体使用独立的验证器
+	perStructValidator bool
+	// types文件所在目录
+	typesDirs []string
+	// 需要生成Validate方法的结构体名称后缀
+	includeSuffixes []string
+	// 是否生成带错误码的聚合验证错误
+	generateErrorCodes bool
+	// 是否生成验证错误处理函数
+	generateErrorHandler bool
+	// 是否根据oneof标签生成枚举常量
+	generateEnums bool
+	// 是否只打印差异而不写入文件
+	dryRun bool
+	// 是否只检查生成的代码是否为最新
+	checkOnly bool
+	// 是否在自定义验证方法未实现时报错
+	strict bool
+	// 自定义验证器配置文件
+	configPath string
+	// 生成的验证函数是否去掉字段值首尾的空白
+	trimSpace bool
+	// 是否生成ValidateCtx方法
+	generateContextMethod bool
+	// 验证方法文件名
+	validationFileName string
+	// 翻译器文件名
+	translatorFileName string
+	// 生成的方法的接收者名称
+	receiverName string
+	// 是否生成MustValidate方法
+	generateMust bool
+	// 自定义验证方法是否通过RegisterValidationCtx注册
+	customValidationCtx bool
+	// 是否生成ValidateJSON方法
+	generateJSONMethod bool
+	// 是否生成ValidateFields方法
+	generateFieldsMethod bool
+	// 共享翻译器包路径
+	sharedTranslatorPackage string
+	// 是否直接根据api文件生成
+	fromAPI bool
+	// 是否将Validate方法写入单独的文件
+	methodsInSeparateFile bool
+	// 生成文件的输出目录
+	outputDir string
+	// 是否生成以结构体封装的验证器
+	structStyle bool
+	// 是否不生成Validate方法
+	skipMethodGeneration bool
+	// 是否完整重新生成验证文件和翻译器文件
+	force bool
+	// 是否清理未使用的自定义标签
+	prune bool
+	// 独立验证包路径
+	validatorPackage string
+	// 是否使用进程内共享的验证器
+	sharedValidator bool
+	// 是否在写入前进行类型检查
+	typeCheck bool
+	// 生成的方法是否使用值接收者
+	valueReceiver bool
+	// 是否生成验证方法的测试文件
+	generateTests bool
 
 	rootCmd = &cobra.Command{
 		Use:     "validate",
 		Short:   "A goctl plugin to generate validation code for API types",
 		Version: version,
+		// goctl调用插件时会传入validate等参数，存在子命令时仍需接受这些参数
+		Args: cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			p, err := plugin.NewPlugin()
 			if err != nil {
@@ -32,12 +108,69 @@ var (
 
 			// 设置处理选项
 			options := processor.Options{
-				EnableCustomValidation: enableCustomValidation,
-				DebugMode:              debugMode,
-				EnableTranslator:       enableTranslator,
+				EnableCustomValidation:  enableCustomValidation,
+				DebugMode:               debugMode,
+				EnableTranslator:        enableTranslator,
+				LazyTranslator:          lazyTranslator,
+				UseLabelTag:             useLabelTag,
+				Bilingual:               bilingual,
+				TranslationLanguage:     translationLanguage,
+				TranslationLanguages:    translationLanguages,
+				PerStructValidator:      perStructValidator,
+				TypesDirs:               typesDirs,
+				IncludeSuffixes:         includeSuffixes,
+				GenerateErrorCodes:      generateErrorCodes,
+				GenerateErrorHandler:    generateErrorHandler,
+				GenerateEnums:           generateEnums,
+				DryRun:                  dryRun,
+				CheckOnly:               checkOnly,
+				Strict:                  strict,
+				ConfigPath:              configPath,
+				TrimSpace:               trimSpace,
+				GenerateContextMethod:   generateContextMethod,
+				ValidationFileName:      validationFileName,
+				TranslatorFileName:      translatorFileName,
+				ReceiverName:            receiverName,
+				GenerateMust:            generateMust,
+				GenerateJSONMethod:      generateJSONMethod,
+				GenerateFieldsMethod:    generateFieldsMethod,
+				CustomValidationCtx:     customValidationCtx,
+				SharedTranslatorPackage: sharedTranslatorPackage,
+				FromAPI:                 fromAPI,
+				MethodsInSeparateFile:   methodsInSeparateFile,
+				OutputDir:               outputDir,
+				StructStyle:             structStyle,
+				SkipMethodGeneration:    skipMethodGeneration,
+				Force:                   force,
+				Prune:                   prune,
+				ValidatorPackage:        validatorPackage,
+				SharedValidator:         sharedValidator,
+				TypeCheck:               typeCheck,
+				ValueReceiver:           valueReceiver,
+				GenerateTests:           generateTests,
 			}
 
-			return validator.ProcessPlugin(p, options)
+			_, err = validator.ProcessPlugin(p, options)
+			return err
+		},
+	}
+
+	// 列出目录中的结构体及验证标签，不生成代码
+	tagsCmd = &cobra.Command{
+		Use:   "tags [dir]",
+		Short: "List the structs and validate tags found in a directory without generating code, marking custom tags that would get stubs",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := "."
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			inspection, err := processor.InspectDir(dir, processor.Options{ConfigPath: configPath})
+			if err != nil {
+				return err
+			}
+			fmt.Print(inspection)
+			return nil
 		},
 	}
 )
@@ -46,6 +179,46 @@ func init() {
 	rootCmd.Flags().BoolVar(&enableCustomValidation, "custom", false, "Enable custom validation methods")
 	rootCmd.Flags().BoolVar(&debugMode, "debug", false, "Enable debug mode")
 	rootCmd.Flags().BoolVar(&enableTranslator, "translator", false, "Enable validation error translator (default: Chinese)")
+	rootCmd.Flags().BoolVar(&lazyTranslator, "lazy-translator", false, "Create the translator on first use inside Translate instead of in init(), guarded by sync.Once (requires --translator)")
+	rootCmd.Flags().BoolVar(&bilingual, "bilingual", false, "Generate TranslateBilingual(err) string joining the messages of the first two --langs languages per field, e.g. \"用户名为必填字段 (username is a required field)\" (requires --translator and --langs)")
+	rootCmd.Flags().BoolVar(&useLabelTag, "label", false, "Prefer the label struct tag over the json name as the field name in translated messages, e.g. label:\"用户名\" (requires --translator)")
+	rootCmd.Flags().StringVar(&sharedTranslatorPackage, "shared-translator", "", "Generate the translator once into this package (relative to the module root, e.g. internal/validatetrans) and reference it from every types directory")
+	rootCmd.Flags().StringVar(&validatorPackage, "validator-package", "", "Generate the validation and translator files into this package (relative to the module root, e.g. internal/validate) and call it from the Validate methods")
+	rootCmd.Flags().BoolVar(&sharedValidator, "shared-validator", false, "Obtain the validator from the process-wide validate.Default() shipped with the plugin instead of creating one per package")
+	rootCmd.Flags().BoolVar(&typeCheck, "type-check", false, "Type-check the packages of the generated files before writing them and fail instead of writing code that does not compile")
+	rootCmd.Flags().BoolVar(&generateContextMethod, "ctx", false, "Also generate ValidateCtx(ctx context.Context) methods using StructCtx")
+	rootCmd.Flags().BoolVar(&generateMust, "must", false, "Also generate MustValidate() methods that panic when validation fails")
+	rootCmd.Flags().BoolVar(&generateFieldsMethod, "fields", false, "Also generate ValidateFields() methods returning every field error with its full namespace path")
+	rootCmd.Flags().BoolVar(&customValidationCtx, "custom-ctx", false, "Generate custom validator stubs as context-aware functions registered with RegisterValidationCtx, with setValidationMessage to override the translated message (requires --custom)")
+	rootCmd.Flags().BoolVar(&generateJSONMethod, "json", false, "Also generate ValidateJSON() methods returning translated messages keyed by JSON field name (requires --translator)")
+	rootCmd.Flags().StringVar(&configPath, "config", "", "YAML/JSON config file defining custom validators by regex, message and error code")
+	rootCmd.Flags().BoolVar(&trimSpace, "trim-space", false, "Trim leading and trailing whitespace from the field value in the plugin built-in and config-defined validators before matching; go-playground tags are not affected")
+	rootCmd.Flags().BoolVar(&force, "force", false, "Fully regenerate the validation and translator files from the current tags, dropping stale validators and translations")
+	rootCmd.Flags().BoolVar(&prune, "prune", false, "Remove registrations and translations of custom tags no longer used by any struct in the package")
+	rootCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print unified diffs of the files that would be written instead of writing them")
+	rootCmd.Flags().BoolVar(&checkOnly, "check", false, "Check that the generated code is up to date without writing files, printing diffs and exiting non-zero when any file would change (for CI)")
+	rootCmd.Flags().BoolVar(&strict, "strict", false, "Fail when a custom tag's generated validate<Tag> function is still the empty stub that always returns true (warned in debug mode)")
+	rootCmd.Flags().BoolVar(&fromAPI, "from-api", false, "Generate from the type definitions in the .api file instead of types.go, writing the methods to validation_methods.go")
+	rootCmd.Flags().BoolVar(&methodsInSeparateFile, "methods-file", false, "Write the Validate methods to validation_methods.go next to each types file instead of appending them to types.go, so goctl can regenerate types.go freely")
+	rootCmd.Flags().StringVar(&outputDir, "output-dir", "", "Write all generated and modified files (including types.go) under this directory, mirroring their paths relative to the module root, and leave the project untouched")
+	rootCmd.Flags().BoolVar(&structStyle, "struct-style", false, "Also generate a Validator struct with a New() constructor that registers all validation tags, for dependency injection")
+	rootCmd.Flags().BoolVar(&skipMethodGeneration, "no-methods", false, "Only generate the validation and translator files, leaving types.go untouched and Validate methods to you")
+	rootCmd.Flags().BoolVar(&perStructValidator, "per-struct", false, "Use a dedicated validator per struct that only registers the tags it uses")
+	rootCmd.Flags().BoolVar(&generateErrorCodes, "error-codes", false, "Generate ValidationErrors with error codes and return it from Validate")
+	rootCmd.Flags().BoolVar(&generateErrorHandler, "error-handler", false, "Generate ValidationErrorHandler for httpx.SetErrorHandler that responds 400 on validation errors")
+	rootCmd.Flags().BoolVar(&generateEnums, "enums", false, "Generate enums.go with typed constants named <Field><Value> for the values of oneof tags, e.g. StatusPending = \"pending\"")
+	rootCmd.Flags().StringVar(&validationFileName, "validation-file", processor.DefaultValidationFileName, "File name of the generated validation methods in the types directory")
+	rootCmd.Flags().StringVar(&translatorFileName, "translator-file", processor.DefaultTranslatorFileName, "File name of the generated translator in the types directory")
+	rootCmd.Flags().BoolVar(&generateTests, "tests", false, "Generate validation_test.go with a table-driven test per registered validator, only appending tests that are missing")
+	rootCmd.Flags().BoolVar(&valueReceiver, "value-receiver", false, "Generate methods with value receivers (func (r X) Validate()) instead of pointer receivers; value receivers copy the struct on each call")
+	rootCmd.Flags().StringVar(&receiverName, "receiver", processor.DefaultReceiverName, "Receiver name of the generated Validate methods")
+	rootCmd.Flags().StringSliceVar(&typesDirs, "types-dir", []string{processor.DefaultTypesDir}, "Directories containing the generated types files (e.g. internal/types/,types/)")
+	rootCmd.Flags().StringSliceVar(&includeSuffixes, "include-suffixes", processor.DefaultIncludeSuffixes, "Struct name suffixes that get Validate methods even without validate tags (e.g. Req,Resp,Form)")
+	rootCmd.Flags().StringSliceVar(&translationLanguages, "langs", nil, "Translation languages registered on the translator, the first one is the default (e.g. zh,en)")
+	rootCmd.Flags().StringVar(&translationLanguage, "lang", processor.DefaultTranslationLanguage, "Translation language of validation errors (zh, en, ja, ko)")
+
+	tagsCmd.Flags().StringVar(&configPath, "config", "", "YAML/JSON config file defining custom validators by regex, message and error code")
+	rootCmd.AddCommand(tagsCmd)
 }
 
 func main() {
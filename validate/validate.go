@@ -0,0 +1,23 @@
+// Package validate 提供进程内共享的验证器
+// goctl-validate启用--shared-validator时，生成的各types包都通过Default获取同一个验证器，
+// 只创建一次验证器及其反射缓存，各包的自定义验证方法注册在同一个验证器上
+package validate
+
+import (
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	defaultOnce     sync.Once
+	defaultValidate *validator.Validate
+)
+
+// Default 返回进程内共享的验证器，首次调用时创建
+func Default() *validator.Validate {
+	defaultOnce.Do(func() {
+		defaultValidate = validator.New()
+	})
+	return defaultValidate
+}
@@ -0,0 +1,63 @@
+package validate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// generatedPackages 模拟进程中加载的生成包数量
+const generatedPackages = 20
+
+// createReq 生成包中的请求结构体
+type createReq struct {
+	Name   string `validate:"required,min=2,max=50"`
+	Mobile string `validate:"required,mobile"`
+}
+
+// validateMobile 生成包中注册的自定义验证方法
+func validateMobile(fl validator.FieldLevel) bool {
+	return len(fl.Field().String()) == 11
+}
+
+// registerPackage 模拟生成包在init中注册验证方法，并验证一次请求以建立结构体缓存
+func registerPackage(b *testing.B, v *validator.Validate) {
+	if err := v.RegisterValidation("mobile", validateMobile); err != nil {
+		b.Fatal(err)
+	}
+	if err := v.Struct(createReq{Name: "name", Mobile: "13800138000"}); err != nil {
+		b.Fatal(err)
+	}
+}
+
+func TestDefaultShared(t *testing.T) {
+	if Default() != Default() {
+		t.Fatal("Default() returned different validators")
+	}
+}
+
+// BenchmarkPerPackageValidator 每个生成包各自创建验证器并注册验证方法
+func BenchmarkPerPackageValidator(b *testing.B) {
+	for range b.N {
+		for range generatedPackages {
+			registerPackage(b, validator.New())
+		}
+	}
+}
+
+// BenchmarkSharedValidator 所有生成包共用一个验证器，创建验证器及结构体缓存的开销只发生一次
+func BenchmarkSharedValidator(b *testing.B) {
+	for range b.N {
+		v := validator.New()
+		for range generatedPackages {
+			registerPackage(b, v)
+		}
+	}
+}
+
+func ExampleDefault() {
+	v := Default()
+	fmt.Println(v.Var("13800138000", "required,numeric,len=11") == nil)
+	// Output: true
+}